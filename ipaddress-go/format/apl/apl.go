@@ -0,0 +1,217 @@
+// Package apl encodes and decodes RFC 3123 Address Prefix List (APL) items,
+// as carried in the RDATA of a DNS APL resource record, on top of the
+// AddressSection primitives in the ipaddr package.
+package apl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+)
+
+const (
+	afiIPv4 = 1
+	afiIPv6 = 2
+
+	ipv4Bits = 32
+	ipv6Bits = 128
+)
+
+// APLItem is a single RFC 3123 APL item: an address prefix, expressed as an
+// *ipaddr.AddressSection along with its prefix length, plus a negation flag
+// ("!" in presentation format).
+type APLItem struct {
+	Section   *ipaddr.AddressSection
+	PrefixLen ipaddr.BitCount
+	Negation  bool
+}
+
+func familyBits(afi uint16) (int, error) {
+	switch afi {
+	case afiIPv4:
+		return ipv4Bits, nil
+	case afiIPv6:
+		return ipv6Bits, nil
+	default:
+		return 0, fmt.Errorf("apl: unsupported address family %d", afi)
+	}
+}
+
+func (item APLItem) afi() (uint16, error) {
+	switch item.Section.GetBitCount() {
+	case ipv4Bits:
+		return afiIPv4, nil
+	case ipv6Bits:
+		return afiIPv6, nil
+	default:
+		return 0, fmt.Errorf("apl: section has unsupported bit count %d", item.Section.GetBitCount())
+	}
+}
+
+// Marshal encodes items into the concatenated RFC 3123 wire format used in APL
+// RDATA: each item becomes a 4-byte header (16-bit AFI, 8-bit prefix length,
+// 8-bit AFDLENGTH with the negation bit in its high bit) followed by the
+// trailing-zero-trimmed network bytes of the prefix.
+func Marshal(items []APLItem) ([]byte, error) {
+	var out []byte
+	for _, item := range items {
+		afi, err := item.afi()
+		if err != nil {
+			return nil, err
+		}
+		full := item.Section.ToPrefixBlockLen(item.PrefixLen).WithoutPrefixLength().GetBytes()
+		afdLen := len(full)
+		for afdLen > 0 && full[afdLen-1] == 0 {
+			afdLen--
+		}
+		out = append(out, byte(afi>>8), byte(afi), byte(item.PrefixLen))
+		neg := byte(0)
+		if item.Negation {
+			neg = 0x80
+		}
+		out = append(out, neg|byte(afdLen))
+		out = append(out, full[:afdLen]...)
+	}
+	return out, nil
+}
+
+// Unmarshal decodes a concatenated sequence of RFC 3123 wire items, rejecting
+// any item whose prefix length exceeds the family's bit count, whose AFDLENGTH
+// implies address bytes beyond the prefix, or whose trimmed bytes carry
+// non-zero bits past the prefix length.
+func Unmarshal(data []byte) ([]APLItem, error) {
+	var items []APLItem
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("apl: truncated item header")
+		}
+		afi := uint16(data[0])<<8 | uint16(data[1])
+		prefixLen := ipaddr.BitCount(data[2])
+		negation := data[3]&0x80 != 0
+		afdLen := int(data[3] &^ 0x80)
+
+		familyBitCount, err := familyBits(afi)
+		if err != nil {
+			return nil, err
+		}
+		if int(prefixLen) > familyBitCount {
+			return nil, fmt.Errorf("apl: prefix length %d exceeds %d bits for family %d", prefixLen, familyBitCount, afi)
+		}
+		if afdLen > (familyBitCount+7)/8 {
+			return nil, fmt.Errorf("apl: AFDLENGTH %d implies bytes beyond the address", afdLen)
+		}
+		if prefixBytes := (int(prefixLen) + 7) / 8; afdLen > prefixBytes {
+			return nil, fmt.Errorf("apl: AFDLENGTH %d implies bytes beyond the prefix length %d", afdLen, prefixLen)
+		}
+		if len(data) < 4+afdLen {
+			return nil, fmt.Errorf("apl: truncated address data")
+		}
+		afd := data[4 : 4+afdLen]
+
+		byteCount := familyBitCount / 8
+		full := make([]byte, byteCount)
+		copy(full, afd)
+		if err := checkZeroPastPrefix(full, int(prefixLen)); err != nil {
+			return nil, err
+		}
+
+		var section *ipaddr.AddressSection
+		if afi == afiIPv4 {
+			addr, convErr := ipaddr.NewIPv4AddressFromIP(full)
+			if convErr != nil {
+				return nil, convErr
+			}
+			section = addr.GetSection().ToAddressSection()
+		} else {
+			addr, convErr := ipaddr.NewIPv6AddressFromIP(full)
+			if convErr != nil {
+				return nil, convErr
+			}
+			section = addr.GetSection().ToAddressSection()
+		}
+		items = append(items, APLItem{Section: section, PrefixLen: prefixLen, Negation: negation})
+		data = data[4+afdLen:]
+	}
+	return items, nil
+}
+
+// checkZeroPastPrefix rejects address bytes that have non-zero bits beyond the
+// given prefix length, which RFC 3123 requires decoders to reject.
+func checkZeroPastPrefix(bytes []byte, prefixLen int) error {
+	for i, b := range bytes {
+		bitOffset := i * 8
+		if bitOffset+8 <= prefixLen {
+			continue
+		}
+		var mask byte
+		if bitOffset >= prefixLen {
+			mask = 0xff
+		} else {
+			mask = 0xff >> uint(prefixLen-bitOffset)
+		}
+		if b&mask != 0 {
+			return fmt.Errorf("apl: non-zero bits past prefix length %d at byte %d", prefixLen, i)
+		}
+	}
+	return nil
+}
+
+// String renders this item in RFC 3123 zone-file presentation format, eg
+// "1:192.168.32.0/21" or "!2:2001:db8::/32".
+func (item APLItem) String() string {
+	afi, err := item.afi()
+	if err != nil {
+		return ""
+	}
+	sign := ""
+	if item.Negation {
+		sign = "!"
+	}
+	addr := item.Section.ToIPAddressSection().ToIPAddress()
+	return fmt.Sprintf("%s%d:%s/%d", sign, afi, addr.GetLower().String(), item.PrefixLen)
+}
+
+// ParseText parses a whitespace-separated sequence of "[!]afi:address/prefix"
+// tokens, as found in APL presentation format, eg
+// "1:192.168.32.0/21 !2:2001:db8::/32".
+func ParseText(text string) ([]APLItem, error) {
+	var items []APLItem
+	for _, token := range strings.Fields(text) {
+		negation := false
+		if strings.HasPrefix(token, "!") {
+			negation = true
+			token = token[1:]
+		}
+		afiStr, rest, ok := strings.Cut(token, ":")
+		if !ok {
+			return nil, fmt.Errorf("apl: invalid token %q", token)
+		}
+		afiNum, err := strconv.Atoi(afiStr)
+		if err != nil {
+			return nil, fmt.Errorf("apl: invalid address family in %q: %w", token, err)
+		}
+		addrStr, prefixStr, ok := strings.Cut(rest, "/")
+		if !ok {
+			return nil, fmt.Errorf("apl: missing prefix length in %q", token)
+		}
+		prefixNum, err := strconv.Atoi(prefixStr)
+		if err != nil {
+			return nil, fmt.Errorf("apl: invalid prefix length in %q: %w", token, err)
+		}
+		addr, addrErr := ipaddr.NewIPAddressString(addrStr).ToAddress()
+		if addrErr != nil {
+			return nil, addrErr
+		}
+		if _, err := familyBits(uint16(afiNum)); err != nil {
+			return nil, err
+		}
+		items = append(items, APLItem{
+			Section:   addr.GetSection().ToAddressSection(),
+			PrefixLen: ipaddr.BitCount(prefixNum),
+			Negation:  negation,
+		})
+	}
+	return items, nil
+}
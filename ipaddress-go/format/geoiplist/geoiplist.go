@@ -0,0 +1,82 @@
+// Package geoiplist marshals and unmarshals IP address lists to and from
+// the JSON schema used by sing-box/meta-rules style geoip rulesets: a
+// top-level object carrying a version and a set of named rules, each a list
+// of canonical CIDR strings such as "104.28.13.94/31" or "2606:40:758::/45".
+package geoiplist
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr/trie"
+)
+
+// Rule is a single named CIDR list within a ruleset, eg the "cn" or "private"
+// entry of a geoip file.
+type Rule struct {
+	Name   string   `json:"name,omitempty"`
+	IPCIDR []string `json:"ip_cidr"`
+}
+
+// Ruleset is the top-level geoip document.
+type Ruleset struct {
+	Version int    `json:"version"`
+	Rules   []Rule `json:"rules"`
+}
+
+// Marshal renders addrs, keyed by rule name, as a Ruleset document.
+func Marshal(version int, rules map[string][]*ipaddr.IPAddress) ([]byte, error) {
+	doc := Ruleset{Version: version}
+	for name, addrs := range rules {
+		cidrs := make([]string, len(addrs))
+		for i, addr := range addrs {
+			cidrs[i] = addr.String()
+		}
+		doc.Rules = append(doc.Rules, Rule{Name: name, IPCIDR: cidrs})
+	}
+	return json.Marshal(doc)
+}
+
+// Unmarshal parses a Ruleset document, returning the parsed addresses keyed
+// by rule name.  Every CIDR entry is parsed through IPAddressString; a
+// malformed entry causes the whole call to fail, naming the offending rule
+// and entry.
+func Unmarshal(data []byte) (map[string][]*ipaddr.IPAddress, error) {
+	var doc Ruleset
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	result := make(map[string][]*ipaddr.IPAddress, len(doc.Rules))
+	for _, rule := range doc.Rules {
+		addrs := make([]*ipaddr.IPAddress, len(rule.IPCIDR))
+		for i, cidr := range rule.IPCIDR {
+			addr, err := ipaddr.NewIPAddressString(cidr).ToAddress()
+			if err != nil {
+				return nil, fmt.Errorf("geoiplist: rule %q entry %d (%q): %w", rule.Name, i, cidr, err)
+			}
+			addrs[i] = addr
+		}
+		result[rule.Name] = addrs
+	}
+	return result, nil
+}
+
+// UnmarshalTrie is like Unmarshal, but returns each rule's entries coalesced
+// into an AssociativeTrie for efficient bulk containment lookups against the
+// ruleset, rather than as a plain slice.
+func UnmarshalTrie(data []byte) (map[string]*trie.AssociativeTrie[struct{}], error) {
+	rules, err := Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*trie.AssociativeTrie[struct{}], len(rules))
+	for name, addrs := range rules {
+		t := trie.NewAssociativeTrie[struct{}]()
+		for _, addr := range addrs {
+			t.AddPrefix(addr)
+		}
+		result[name] = t
+	}
+	return result, nil
+}
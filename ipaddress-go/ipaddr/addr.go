@@ -2,6 +2,7 @@ package ipaddr
 
 import (
 	"net"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -19,8 +20,8 @@ const (
 type SegmentValueProvider func(segmentIndex int) SegInt
 
 type addressCache struct {
-	ip           net.IPAddr // lower converted (cloned when returned)
-	lower, upper *addressInternal
+	ip           atomic.Pointer[net.IPAddr] // lower converted (cloned when returned)
+	lower, upper atomic.Pointer[addressInternal]
 }
 
 type addressInternal struct {
@@ -55,21 +56,50 @@ func (addr *addressInternal) getBytes() []byte {
 }
 
 func (addr *addressInternal) getLower() *Address {
-	//TODO cache the result in the addressCache
+	if cached := addr.cache.lower.Load(); cached != nil {
+		return cached.toAddress()
+	}
 	section := addr.section.GetLower()
 	if section == addr.section {
 		return addr.toAddress()
 	}
-	return &Address{addressInternal{section: section, zone: addr.zone, cache: &addressCache{}}}
+	result := &addressInternal{section: section, zone: addr.zone, cache: &addressCache{}}
+	addr.cache.lower.CompareAndSwap(nil, result)
+	return addr.cache.lower.Load().toAddress()
 }
 
 func (addr *addressInternal) getUpper() *Address {
-	//TODO cache the result in the addressCache
+	if cached := addr.cache.upper.Load(); cached != nil {
+		return cached.toAddress()
+	}
 	section := addr.section.GetUpper()
 	if section == addr.section {
 		return addr.toAddress()
 	}
-	return &Address{addressInternal{section: section, zone: addr.zone, cache: &addressCache{}}}
+	result := &addressInternal{section: section, zone: addr.zone, cache: &addressCache{}}
+	addr.cache.upper.CompareAndSwap(nil, result)
+	return addr.cache.upper.Load().toAddress()
+}
+
+// getNetIP returns addr's net.IPAddr form, computing and atomically
+// publishing it into addr.cache.ip on first use so that later callers, and
+// GetNetIP below, return the cached value rather than rebuilding it. The
+// net.IP byte slice within is cloned on every call regardless, since
+// net.IP is mutable and callers must not be able to corrupt the cache by
+// writing through a returned slice.
+func (addr *addressInternal) getNetIP() net.IPAddr {
+	if cached := addr.cache.ip.Load(); cached != nil {
+		return cloneNetIPAddr(*cached)
+	}
+	ipAddr := net.IPAddr{IP: net.IP(addr.getBytes()), Zone: string(addr.zone)}
+	addr.cache.ip.CompareAndSwap(nil, &ipAddr)
+	return cloneNetIPAddr(*addr.cache.ip.Load())
+}
+
+func cloneNetIPAddr(ipAddr net.IPAddr) net.IPAddr {
+	ip := make(net.IP, len(ipAddr.IP))
+	copy(ip, ipAddr.IP)
+	return net.IPAddr{IP: ip, Zone: ipAddr.Zone}
 }
 
 func (addr *addressInternal) toAddress() *Address {
@@ -0,0 +1,28 @@
+package ipaddr
+
+import "testing"
+
+// benchIPv4RangeAddress backs the GetLower and GetNetIP benchmarks below: a
+// multi-valued address so GetLower must actually build (and, on repeat
+// calls, retrieve from cache) a distinct lower Address.
+var benchIPv4RangeAddress = func() *IPAddress {
+	addr, _ := NewIPAddressString("1.2.3.0-255").ToAddress()
+	return addr
+}()
+
+// BenchmarkIPAddressGetLower exercises addressCache's atomic lower-value
+// cache: only the first call computes the result, every subsequent call
+// just loads the cached pointer.
+func BenchmarkIPAddressGetLower(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchIPv4RangeAddress.GetLower()
+	}
+}
+
+// BenchmarkIPAddressGetNetIP exercises addressCache's atomic net.IPAddr
+// cache, which is rebuilt from scratch only on the first call.
+func BenchmarkIPAddressGetNetIP(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchIPv4RangeAddress.GetNetIP()
+	}
+}
@@ -0,0 +1,127 @@
+package ipaddr
+
+import "bytes"
+
+// This file adds a total order on IPAddress and IPAddressSection, mirroring
+// the ordering net/netip settled on for Prefix.Compare/AddrPort.Compare:
+// order by address family first (IPv4 before IPv6), then by address value,
+// then by prefix length (shorter first, when the address values are equal).
+
+// ComparePrefixLen compares two PrefixLen values, returning a negative
+// number, zero, or a positive number.
+// A nil PrefixLen (no prefix length) sorts after any non-nil value, matching
+// PrefixBitCount.Compare.
+func ComparePrefixLen(p, other PrefixLen) int {
+	return p.Compare(other)
+}
+
+// ComparePort compares two Port values, returning a negative number, zero,
+// or a positive number.  A nil Port
+// (no port) sorts before any non-nil value, matching PortVal.Compare.
+func ComparePort(p, other Port) int {
+	return p.Compare(other)
+}
+
+// Compare returns a total ordering over IPAddress values: IPv4 addresses
+// sort before IPv6 addresses, then addresses are ordered by value, then
+// (for addresses with equal value) by prefix length, with a shorter prefix
+// length sorting first and the absence of one sorting after any prefix
+// length. A nil receiver or argument
+// sorts before a non-nil one.
+func (addr *IPAddress) Compare(other *IPAddress) int {
+	if addr == nil {
+		if other == nil {
+			return 0
+		}
+		return -1
+	} else if other == nil {
+		return 1
+	}
+	if c := addr.GetIPVersion().index() - other.GetIPVersion().index(); c != 0 {
+		return c
+	}
+	if c := bytes.Compare(addrRawBytes(addr), addrRawBytes(other)); c != 0 {
+		return c
+	}
+	return addr.GetNetworkPrefixLength().Compare(other.GetNetworkPrefixLength())
+}
+
+// addrRawBytes returns addr's raw address bytes, in network byte order,
+// regardless of IP version.
+func addrRawBytes(addr *IPAddress) []byte {
+	if v4 := addr.ToIPv4Address(); v4 != nil {
+		return v4.GetBytes()
+	} else if v6 := addr.ToIPv6Address(); v6 != nil {
+		return v6.GetBytes()
+	}
+	return nil
+}
+
+// Less reports whether addr sorts before other according to Compare.
+func (addr *IPAddress) Less(other *IPAddress) bool {
+	return addr.Compare(other) < 0
+}
+
+// Compare returns a total ordering over IPAddressSection values, using the
+// same family-then-value-then-prefix-length rules as (*IPAddress).Compare.
+func (section *IPAddressSection) Compare(other *IPAddressSection) int {
+	if section == nil {
+		if other == nil {
+			return 0
+		}
+		return -1
+	} else if other == nil {
+		return 1
+	}
+	sectionBytes, otherBytes := section.GetBytes(), other.GetBytes()
+	if c := len(sectionBytes) - len(otherBytes); c != 0 {
+		// a section's byte count (4 vs 16) stands in for IP version here,
+		// since IPAddressSection carries no version of its own the way
+		// IPAddress does.
+		return c
+	}
+	if c := bytes.Compare(sectionBytes, otherBytes); c != 0 {
+		return c
+	}
+	return section.GetNetworkPrefixLength().Compare(other.GetNetworkPrefixLength())
+}
+
+// Less reports whether section sorts before other according to Compare.
+func (section *IPAddressSection) Less(other *IPAddressSection) bool {
+	return section.Compare(other) < 0
+}
+
+// SortAddresses sorts addrs in place in the total order defined by
+// (*IPAddress).Compare, for direct use with slices.SortFunc:
+//
+//	slices.SortFunc(addrs, (*IPAddress).Compare)
+//
+// SortAddresses is provided as a convenience for callers on older Go
+// versions, or who would rather not spell out the slices.SortFunc call.
+func SortAddresses(addrs []*IPAddress) {
+	insertionSortAddresses(addrs)
+}
+
+// SortPrefixes sorts sections in place in the total order defined by
+// (*IPAddressSection).Compare.
+func SortPrefixes(sections []*IPAddressSection) {
+	insertionSortSections(sections)
+}
+
+// insertionSortAddresses avoids taking a dependency on the generics-only
+// slices package, which not every build of this module can assume.
+func insertionSortAddresses(addrs []*IPAddress) {
+	for i := 1; i < len(addrs); i++ {
+		for j := i; j > 0 && addrs[j].Less(addrs[j-1]); j-- {
+			addrs[j], addrs[j-1] = addrs[j-1], addrs[j]
+		}
+	}
+}
+
+func insertionSortSections(sections []*IPAddressSection) {
+	for i := 1; i < len(sections); i++ {
+		for j := i; j > 0 && sections[j].Less(sections[j-1]); j-- {
+			sections[j], sections[j-1] = sections[j-1], sections[j]
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package ipaddr
+
+import (
+	"math/big"
+	"sync"
+)
+
+// This file adds sequential network and host address generators to
+// IPv4AddressNetwork and IPv6AddressNetwork, modeled on ns-3's
+// Ipv4AddressGenerator: callers ask for the next unallocated subnet or host
+// address instead of picking one by hand, with collisions against
+// previously-generated or explicitly-allocated addresses caught
+// automatically.
+
+// SubnetGenerator hands out successive, non-overlapping subnets of a fixed
+// prefix length, advancing past any subnet already returned or recorded via
+// AddAllocated. A SubnetGenerator is safe for concurrent use.
+type SubnetGenerator struct {
+	mu        sync.Mutex
+	network   IPAddressNetwork
+	version   IPVersion
+	prefixLen BitCount
+	step      *big.Int
+	next      *big.Int
+	testMode  bool
+	allocated map[string]bool
+}
+
+func newAddressGeneratorStep(version IPVersion, prefixLen BitCount) *big.Int {
+	hostBits := BitCount(ipVersionBitCount(version)) - prefixLen
+	return new(big.Int).Lsh(bigOne(), uint(hostBits))
+}
+
+func newSubnetGenerator(network IPAddressNetwork, initialNet *IPAddress, prefixLen BitCount) *SubnetGenerator {
+	initialNet = initialNet.init()
+	version := initialNet.GetIPVersion()
+	// warm/share the network's mask cache rather than have every
+	// generator compute its own prefix-length mask independently
+	network.GetNetworkMask(prefixLen)
+	return &SubnetGenerator{
+		network:   network,
+		version:   version,
+		prefixLen: prefixLen,
+		step:      newAddressGeneratorStep(version, prefixLen),
+		next:      addrValue(initialNet.ToPrefixBlockLen(prefixLen).GetLower()),
+		allocated: make(map[string]bool),
+	}
+}
+
+// SetTestMode enables or disables collision tracking. With test mode
+// enabled, NextNetwork always advances and never reports a collision,
+// which is useful when fuzzing callers that expect a steady stream of
+// subnets regardless of what has already been allocated.
+func (g *SubnetGenerator) SetTestMode(enabled bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.testMode = enabled
+}
+
+// NextNetwork returns the next subnet of this generator's prefix length
+// that has not already been returned by NextNetwork or recorded via
+// AddAllocated, and advances past it.
+func (g *SubnetGenerator) NextNetwork() (*IPAddress, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for {
+		key := g.next.String()
+		if g.testMode || !g.allocated[key] {
+			addr := valueToAddr(g.next, g.version).ToPrefixBlockLen(g.prefixLen)
+			if !g.testMode {
+				g.allocated[key] = true
+			}
+			g.next.Add(g.next, g.step)
+			return addr, nil
+		}
+		g.next.Add(g.next, g.step)
+	}
+}
+
+// AddAllocated records subnet as already allocated, so future calls to
+// NextNetwork skip over it. It returns an error if subnet was already
+// recorded.
+func (g *SubnetGenerator) AddAllocated(subnet *IPAddress) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := addrValue(subnet.ToPrefixBlockLen(g.prefixLen).GetLower()).String()
+	if g.allocated[key] {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.address.already.allocated"}}
+	}
+	g.allocated[key] = true
+	return nil
+}
+
+// HostGenerator hands out successive host addresses within a fixed subnet,
+// advancing past any address already returned or recorded via
+// AddAllocated. A HostGenerator is safe for concurrent use.
+type HostGenerator struct {
+	mu        sync.Mutex
+	version   IPVersion
+	low       *big.Int
+	high      *big.Int
+	next      *big.Int
+	testMode  bool
+	allocated map[string]bool
+}
+
+func newHostGenerator(subnet *IPAddress) *HostGenerator {
+	subnet = subnet.init()
+	return &HostGenerator{
+		version:   subnet.GetIPVersion(),
+		low:       addrValue(subnet.GetLower()),
+		high:      addrValue(subnet.GetUpper()),
+		next:      addrValue(subnet.GetLower()),
+		allocated: make(map[string]bool),
+	}
+}
+
+// SetTestMode enables or disables collision tracking, per
+// SubnetGenerator.SetTestMode.
+func (g *HostGenerator) SetTestMode(enabled bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.testMode = enabled
+}
+
+// NextAddress returns the next host address in this generator's subnet that
+// has not already been returned by NextAddress or recorded via
+// AddAllocated, and advances past it. It returns an error once the subnet is
+// exhausted.
+func (g *HostGenerator) NextAddress() (*IPAddress, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.next.Cmp(g.high) <= 0 {
+		key := g.next.String()
+		if g.testMode || !g.allocated[key] {
+			addr := valueToAddr(g.next, g.version)
+			if !g.testMode {
+				g.allocated[key] = true
+			}
+			g.next.Add(g.next, bigOneConst())
+			return addr, nil
+		}
+		g.next.Add(g.next, bigOneConst())
+	}
+	return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.address.pool.exhausted"}}
+}
+
+// AddAllocated records addr as already allocated, so future calls to
+// NextAddress skip over it. It returns an error if addr was already
+// recorded, or lies outside this generator's subnet.
+func (g *HostGenerator) AddAllocated(addr *IPAddress) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	val := addrValue(addr)
+	if val.Cmp(g.low) < 0 || val.Cmp(g.high) > 0 {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.address.out.of.range"}}
+	}
+	key := val.String()
+	if g.allocated[key] {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.address.already.allocated"}}
+	}
+	g.allocated[key] = true
+	return nil
+}
+
+// NewSubnetGenerator returns the network's SubnetGenerator for prefixLen,
+// creating one seeded at initialNet if this is the first request for that
+// prefix length. Generators for different prefix lengths are independent:
+// advancing the /24 generator has no effect on the /16 generator.
+func (network *IPv4AddressNetwork) NewSubnetGenerator(initialNet *IPAddress, prefixLen BitCount) *SubnetGenerator {
+	return network.ipAddressNetwork.subnetGenerator(network, initialNet, prefixLen)
+}
+
+// NewHostGenerator returns this network's HostGenerator over subnet's host
+// addresses, creating it on the first request for that subnet; later calls
+// for the same subnet return the same generator.
+func (network *IPv4AddressNetwork) NewHostGenerator(subnet *IPAddress) *HostGenerator {
+	return network.ipAddressNetwork.hostGenerator(subnet)
+}
+
+// NewSubnetGenerator is NewSubnetGenerator for IPv6AddressNetwork.
+func (network *IPv6AddressNetwork) NewSubnetGenerator(initialNet *IPAddress, prefixLen BitCount) *SubnetGenerator {
+	return network.ipAddressNetwork.subnetGenerator(network, initialNet, prefixLen)
+}
+
+// NewHostGenerator is NewHostGenerator for IPv6AddressNetwork.
+func (network *IPv6AddressNetwork) NewHostGenerator(subnet *IPAddress) *HostGenerator {
+	return network.ipAddressNetwork.hostGenerator(subnet)
+}
+
+func (network *ipAddressNetwork) subnetGenerator(owner IPAddressNetwork, initialNet *IPAddress, prefixLen BitCount) *SubnetGenerator {
+	network.genMu.Lock()
+	defer network.genMu.Unlock()
+	if network.subnetGens == nil {
+		network.subnetGens = make(map[BitCount]*SubnetGenerator)
+	}
+	if gen := network.subnetGens[prefixLen]; gen != nil {
+		return gen
+	}
+	gen := newSubnetGenerator(owner, initialNet, prefixLen)
+	network.subnetGens[prefixLen] = gen
+	return gen
+}
+
+func (network *ipAddressNetwork) hostGenerator(subnet *IPAddress) *HostGenerator {
+	network.genMu.Lock()
+	defer network.genMu.Unlock()
+	if network.hostGens == nil {
+		network.hostGens = make(map[string]*HostGenerator)
+	}
+	key := subnet.String()
+	if gen := network.hostGens[key]; gen != nil {
+		return gen
+	}
+	gen := newHostGenerator(subnet)
+	network.hostGens[key] = gen
+	return gen
+}
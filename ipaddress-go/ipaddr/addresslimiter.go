@@ -0,0 +1,150 @@
+package ipaddr
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"iter"
+)
+
+// AddressStringFormat selects which String-family method WriteAddressesTo
+// uses to render each address.
+type AddressStringFormat int
+
+const (
+	FormatCanonical AddressStringFormat = iota
+	FormatNormalized
+	FormatCompressed
+)
+
+func (addr *IPv4Address) formatString(format AddressStringFormat) string {
+	switch format {
+	case FormatNormalized:
+		return addr.ToNormalizedString()
+	case FormatCompressed:
+		return addr.ToCompressedString()
+	default:
+		return addr.ToCanonicalString()
+	}
+}
+
+// WriteAddressesTo streams every address represented by addr to w, one
+// string in the given format per line, without ever materializing more than one
+// address's worth of temporary buffer at a time. It is safe to call on a
+// subnet or range far too large to fit in memory as a slice, eg 10.0.0.0/8.
+func (addr *IPv4Address) WriteAddressesTo(w io.Writer, format AddressStringFormat) (n int64, err error) {
+	addr = addr.init()
+	bw := bufio.NewWriter(w)
+	it := addr.Iterator()
+	for it.HasNext() {
+		line := it.Next().formatString(format) + "\n"
+		written, werr := bw.WriteString(line)
+		n += int64(written)
+		if werr != nil {
+			return n, werr
+		}
+	}
+	if err = bw.Flush(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// WriteAddressesTo streams every address in rng to w, one string in the
+// given format per line, without materializing the range as a slice.
+func (rng *IPv4AddressSeqRange) WriteAddressesTo(w io.Writer, format AddressStringFormat) (n int64, err error) {
+	bw := bufio.NewWriter(w)
+	it := rng.Iterator()
+	for it.HasNext() {
+		line := it.Next().formatString(format) + "\n"
+		written, werr := bw.WriteString(line)
+		n += int64(written)
+		if werr != nil {
+			return n, werr
+		}
+	}
+	if err = bw.Flush(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// ErrAddressLimitExceeded is returned by AddressLimitedWriter.Consume once N
+// addresses have already been consumed, mirroring io.ErrShortWrite's role in
+// gVisor's tcpip.LimitedWriter.
+var ErrAddressLimitExceeded = errors.New("ipaddress: address limit exceeded")
+
+// AddressConsumer accepts one address at a time, eg to write it out, insert
+// it into an index, or tally it, without requiring the full set to be
+// materialized in memory first.
+type AddressConsumer interface {
+	Consume(addr *IPAddress) error
+}
+
+// AddressLimitedWriter wraps an AddressConsumer, stopping cleanly with
+// ErrAddressLimitExceeded once N addresses have been consumed, the way
+// gVisor's tcpip.LimitedWriter caps an io.Writer at N bytes with
+// io.ErrShortWrite.
+type AddressLimitedWriter struct {
+	W AddressConsumer
+	N int64
+}
+
+// Consume forwards addr to W, decrementing N, or returns
+// ErrAddressLimitExceeded without calling W once N has reached zero.
+func (lw *AddressLimitedWriter) Consume(addr *IPAddress) error {
+	if lw.N <= 0 {
+		return ErrAddressLimitExceeded
+	}
+	if err := lw.W.Consume(addr); err != nil {
+		return err
+	}
+	lw.N--
+	return nil
+}
+
+// ConsumeAddresses feeds every address produced by it to consumer, stopping
+// cleanly, without error, if consumer returns ErrAddressLimitExceeded; any
+// other error from consumer is returned to the caller.
+func ConsumeAddresses(consumer AddressConsumer, it IPAddressIterator) error {
+	for it.HasNext() {
+		if err := consumer.Consume(it.Next()); err != nil {
+			if errors.Is(err, ErrAddressLimitExceeded) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Chunks returns a single-pass, Go 1.23 range-over-func enumerator over
+// every address addr represents. It buffers at most size addresses ahead of
+// the consuming range loop, bounding peak memory use regardless of how many
+// addresses addr represents in total, eg for a /8 or larger subnet.
+func (addr *IPv4Address) Chunks(size int) iter.Seq[*IPv4Address] {
+	if size <= 0 {
+		size = 1
+	}
+	return func(yield func(*IPv4Address) bool) {
+		addr = addr.init()
+		it := addr.Iterator()
+		buf := make([]*IPv4Address, 0, size)
+		for it.HasNext() {
+			buf = append(buf, it.Next())
+			if len(buf) == size {
+				for _, a := range buf {
+					if !yield(a) {
+						return
+					}
+				}
+				buf = buf[:0]
+			}
+		}
+		for _, a := range buf {
+			if !yield(a) {
+				return
+			}
+		}
+	}
+}
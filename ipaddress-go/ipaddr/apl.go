@@ -0,0 +1,172 @@
+package ipaddr
+
+import "fmt"
+
+// APLItem is a single RFC 3123 Address Prefix List item: a negation flag ("!"
+// in presentation format), a prefix length, and the prefix address.  The
+// address family (1 for IPv4, 2 for IPv6) is derived from Prefix, and
+// trailing zero bytes of the prefix are trimmed only in the wire encoding
+// described in RFC 3123 section 4.
+type APLItem struct {
+	Negate    bool
+	PrefixLen BitCount
+	Prefix    *IPAddress
+}
+
+// afi returns the IANA address family number for this item's prefix: 1 for IPv4,
+// 2 for IPv6.
+func (item *APLItem) afi() (uint16, error) {
+	if item.Prefix.IsIPv4() {
+		return 1, nil
+	} else if item.Prefix.IsIPv6() {
+		return 2, nil
+	}
+	return 0, fmt.Errorf("ipaddress: APL item has indeterminate IP version")
+}
+
+// aplFamilyBitCount returns the address width in bits for an RFC 3123
+// address family number, 1 (IPv4) or 2 (IPv6).
+func aplFamilyBitCount(afi uint16) (BitCount, error) {
+	switch afi {
+	case 1:
+		return IPv4BitCount, nil
+	case 2:
+		return IPv6BitCount, nil
+	default:
+		return 0, fmt.Errorf("ipaddress: unsupported APL address family %d", afi)
+	}
+}
+
+// ToAPLBytes encodes this item into its RFC 3123 wire form: a 2-byte address
+// family, a 1-byte prefix length, and a 1-byte length/negation octet followed by
+// the significant prefix bytes (those up to the last non-zero byte).
+func (item *APLItem) ToAPLBytes() ([]byte, error) {
+	afi, err := item.afi()
+	if err != nil {
+		return nil, err
+	}
+	familyBitCount, err := aplFamilyBitCount(afi)
+	if err != nil {
+		return nil, err
+	}
+	if item.PrefixLen < 0 || item.PrefixLen > familyBitCount {
+		return nil, fmt.Errorf("ipaddress: prefix length %d exceeds %d bits for family %d", item.PrefixLen, familyBitCount, afi)
+	}
+	full := item.Prefix.ToPrefixBlockLen(item.PrefixLen).GetLower().GetBytes()
+	afdLen := len(full)
+	for afdLen > 0 && full[afdLen-1] == 0 {
+		afdLen--
+	}
+	out := make([]byte, 4+afdLen)
+	out[0] = byte(afi >> 8)
+	out[1] = byte(afi)
+	out[2] = byte(item.PrefixLen)
+	negBit := byte(0)
+	if item.Negate {
+		negBit = 0x80
+	}
+	out[3] = negBit | byte(afdLen)
+	copy(out[4:], full[:afdLen])
+	return out, nil
+}
+
+// ToAPLString renders this item in RFC 3123 zone-file presentation format, eg
+// "1:10.0.0.0/8" or "!2:2001:db8::/32".
+func (item *APLItem) ToAPLString() (string, error) {
+	afi, err := item.afi()
+	if err != nil {
+		return "", err
+	}
+	sign := ""
+	if item.Negate {
+		sign = "!"
+	}
+	return fmt.Sprintf("%s%d:%s/%d", sign, afi, item.Prefix.GetLower().String(), item.PrefixLen), nil
+}
+
+// ToAPLItem encodes this address as a single non-negated RFC 3123 APL wire
+// item, using its network prefix length (or its full bit count if it has
+// none) as the item's prefix length.
+func (addr *IPAddress) ToAPLItem() ([]byte, error) {
+	addr = addr.init()
+	prefixLen := addr.GetNetworkPrefixLength()
+	var bitCount BitCount
+	if prefixLen != nil {
+		bitCount = prefixLen.Len()
+	} else {
+		bitCount = BitCount(ipVersionBitCount(addr.GetIPVersion()))
+	}
+	item := &APLItem{PrefixLen: bitCount, Prefix: addr}
+	return item.ToAPLBytes()
+}
+
+// ParseAPLItem decodes a single RFC 3123 APL wire item, returning the
+// address (as a prefix block of the item's prefix length) and whether the
+// item's negation bit was set.
+func ParseAPLItem(data []byte) (addr *IPAddress, negated bool, err error) {
+	items, err := ParseAPLBytes(data)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(items) != 1 {
+		return nil, false, fmt.Errorf("ipaddress: expected exactly one APL item, got %d", len(items))
+	}
+	item := items[0]
+	return item.Prefix.ToPrefixBlockLen(item.PrefixLen), item.Negate, nil
+}
+
+// ParseAPLBytes decodes a sequence of concatenated RFC 3123 APL wire items,
+// as found in the RDATA of a DNS APL resource record.
+func ParseAPLBytes(data []byte) ([]*APLItem, error) {
+	var items []*APLItem
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("ipaddress: truncated APL item header")
+		}
+		afi := uint16(data[0])<<8 | uint16(data[1])
+		prefixLen := BitCount(data[2])
+		negate := data[3]&0x80 != 0
+		afdLen := int(data[3] &^ 0x80)
+
+		familyBitCount, ferr := aplFamilyBitCount(afi)
+		if ferr != nil {
+			return nil, ferr
+		}
+		if prefixLen > familyBitCount {
+			return nil, fmt.Errorf("ipaddress: prefix length %d exceeds %d bits for family %d", prefixLen, familyBitCount, afi)
+		}
+		if afdLen > int(familyBitCount+7)/8 {
+			return nil, fmt.Errorf("ipaddress: AFDLENGTH %d implies bytes beyond the address", afdLen)
+		}
+		if len(data) < 4+afdLen {
+			return nil, fmt.Errorf("ipaddress: truncated APL address data")
+		}
+		afd := data[4 : 4+afdLen]
+		var addr *IPAddress
+		var err AddressValueException
+		switch afi {
+		case 1:
+			bytes := make([]byte, IPv4ByteCount)
+			copy(bytes, afd)
+			var ipv4Addr *IPv4Address
+			ipv4Addr, err = NewIPv4AddressFromIP(bytes)
+			if ipv4Addr != nil {
+				addr = ipv4Addr.ToIPAddress()
+			}
+		case 2:
+			bytes := make([]byte, IPv6ByteCount)
+			copy(bytes, afd)
+			var ipv6Addr *IPv6Address
+			ipv6Addr, err = NewIPv6AddressFromIP(bytes)
+			if ipv6Addr != nil {
+				addr = ipv6Addr.ToIPAddress()
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &APLItem{Negate: negate, PrefixLen: prefixLen, Prefix: addr})
+		data = data[4+afdLen:]
+	}
+	return items, nil
+}
@@ -0,0 +1,150 @@
+package ipaddr
+
+import "testing"
+
+// TestAPLItemRoundTrip checks that ToAPLBytes/ParseAPLBytes round-trip for
+// IPv4 and IPv6 items, with and without negation, and that trailing
+// zero bytes are trimmed from the wire form as RFC 3123 section 4 requires.
+func TestAPLItemRoundTrip(t *testing.T) {
+	tests := []struct {
+		addr      string
+		prefixLen BitCount
+		negate    bool
+		wantLen   int // expected wire length: 4-byte header + significant AFD bytes
+	}{
+		{"10.0.0.0", 8, false, 5},
+		{"10.0.0.0", 8, true, 5},
+		{"0.0.0.0", 0, false, 4},
+		{"2001:db8::", 32, false, 8},
+		{"::", 0, false, 4},
+	}
+	for _, tt := range tests {
+		addr, err := NewIPAddressString(tt.addr).ToAddress()
+		if err != nil {
+			t.Fatalf("%q: failed to parse: %v", tt.addr, err)
+		}
+		item := &APLItem{Negate: tt.negate, PrefixLen: tt.prefixLen, Prefix: addr}
+		data, aerr := item.ToAPLBytes()
+		if aerr != nil {
+			t.Fatalf("%q: ToAPLBytes: %v", tt.addr, aerr)
+		}
+		if len(data) != tt.wantLen {
+			t.Errorf("%q: wire length got %d, want %d", tt.addr, len(data), tt.wantLen)
+		}
+		parsed, negated, perr := ParseAPLItem(data)
+		if perr != nil {
+			t.Fatalf("%q: ParseAPLItem: %v", tt.addr, perr)
+		}
+		if negated != tt.negate {
+			t.Errorf("%q: negated got %v, want %v", tt.addr, negated, tt.negate)
+		}
+		wantBlock := addr.ToPrefixBlockLen(tt.prefixLen)
+		if parsed.GetLower().String() != wantBlock.GetLower().String() {
+			t.Errorf("%q: round trip got %q, want %q", tt.addr, parsed.GetLower().String(), wantBlock.GetLower().String())
+		}
+	}
+}
+
+// TestAPLItemToString checks RFC 3123 presentation-format rendering,
+// including the "!" negation sign.
+func TestAPLItemToString(t *testing.T) {
+	addr, err := NewIPAddressString("10.0.0.0").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	tests := []struct {
+		negate bool
+		want   string
+	}{
+		{false, "1:10.0.0.0/8"},
+		{true, "!1:10.0.0.0/8"},
+	}
+	for _, tt := range tests {
+		item := &APLItem{Negate: tt.negate, PrefixLen: 8, Prefix: addr}
+		got, err := item.ToAPLString()
+		if err != nil {
+			t.Fatalf("ToAPLString: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("negate=%v: got %q, want %q", tt.negate, got, tt.want)
+		}
+	}
+}
+
+// TestParseAPLBytesMultiple checks that ParseAPLBytes decodes a sequence of
+// concatenated items, as found in an APL RDATA, of mixed address families.
+func TestParseAPLBytesMultiple(t *testing.T) {
+	v4, err := NewIPAddressString("10.0.0.0").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	v6, err := NewIPAddressString("2001:db8::").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	item1 := &APLItem{PrefixLen: 8, Prefix: v4}
+	item2 := &APLItem{Negate: true, PrefixLen: 32, Prefix: v6}
+	data1, _ := item1.ToAPLBytes()
+	data2, _ := item2.ToAPLBytes()
+	items, perr := ParseAPLBytes(append(data1, data2...))
+	if perr != nil {
+		t.Fatalf("ParseAPLBytes: %v", perr)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	if items[0].Negate || items[1].Negate != true {
+		t.Errorf("negation flags got (%v, %v), want (false, true)", items[0].Negate, items[1].Negate)
+	}
+}
+
+// TestParseAPLBytesTruncated checks that ParseAPLBytes rejects truncated
+// input rather than panicking or silently misparsing it.
+func TestParseAPLBytesTruncated(t *testing.T) {
+	tests := [][]byte{
+		{0, 1, 8},              // header needs 4 bytes
+		{0, 1, 8, 0x04, 10, 0}, // afdLen 4, but only 2 bytes of data follow
+		{0, 3, 8, 0},           // unsupported address family 3
+	}
+	for i, data := range tests {
+		if _, err := ParseAPLBytes(data); err == nil {
+			t.Errorf("case %d: expected an error, got none", i)
+		}
+	}
+}
+
+// TestParseAPLBytesPrefixLenOverflow checks that ParseAPLBytes rejects a
+// prefix length that exceeds the family's address width (32 for IPv4, 128
+// for IPv6) instead of silently accepting a corrupt record.
+func TestParseAPLBytesPrefixLenOverflow(t *testing.T) {
+	// afi 1 (IPv4), prefix length 33 (> 32 bits), afdLen 0
+	data := []byte{0, 1, 33, 0}
+	if _, err := ParseAPLBytes(data); err == nil {
+		t.Error("prefix length 33 for IPv4: expected an error, got none")
+	}
+}
+
+// TestParseAPLBytesAFDLenOverflow checks that ParseAPLBytes rejects an
+// AFDLENGTH that implies address bytes beyond the family's width, instead of
+// silently truncating it into the fixed-size address buffer.
+func TestParseAPLBytesAFDLenOverflow(t *testing.T) {
+	// afi 1 (IPv4, 4 bytes wide), afdLen 5 (one byte too many)
+	data := []byte{0, 1, 32, 5, 1, 2, 3, 4, 5}
+	if _, err := ParseAPLBytes(data); err == nil {
+		t.Error("AFDLENGTH 5 for IPv4: expected an error, got none")
+	}
+}
+
+// TestToAPLBytesPrefixLenOverflow checks that ToAPLBytes rejects a prefix
+// length that exceeds the family's address width rather than writing a
+// corrupt wire record.
+func TestToAPLBytesPrefixLenOverflow(t *testing.T) {
+	addr, err := NewIPAddressString("10.0.0.0").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	item := &APLItem{PrefixLen: 33, Prefix: addr}
+	if _, err := item.ToAPLBytes(); err == nil {
+		t.Error("prefix length 33 for IPv4: expected an error, got none")
+	}
+}
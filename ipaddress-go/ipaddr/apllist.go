@@ -0,0 +1,104 @@
+package ipaddr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// APLList is an ordered sequence of RFC 3123 APL items, as carried together
+// in the RDATA of a single DNS APL resource record.
+type APLList []*APLItem
+
+// MarshalAPL encodes list into the concatenated RFC 3123 wire format used in
+// APL RDATA.
+func (list APLList) MarshalAPL() ([]byte, error) {
+	var out []byte
+	for _, item := range list {
+		itemBytes, err := item.ToAPLBytes()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, itemBytes...)
+	}
+	return out, nil
+}
+
+// UnmarshalAPL decodes a concatenated sequence of RFC 3123 wire items into an
+// APLList, per ParseAPLBytes.
+func (list *APLList) UnmarshalAPL(data []byte) error {
+	items, err := ParseAPLBytes(data)
+	if err != nil {
+		return err
+	}
+	*list = items
+	return nil
+}
+
+// String renders list in RFC 3123 zone-file presentation format, the
+// whitespace-separated sequence of "[!]afi:address/prefix" tokens accepted by
+// ParseAPLList, eg "1:192.168.32.0/21 !1:192.168.38.0/28 2:2001:db8::/32".
+func (list APLList) String() string {
+	tokens := make([]string, 0, len(list))
+	for _, item := range list {
+		token, err := item.ToAPLString()
+		if err != nil {
+			return ""
+		}
+		tokens = append(tokens, token)
+	}
+	return strings.Join(tokens, " ")
+}
+
+// ParseAPLList parses a whitespace-separated sequence of "[!]afi:address/prefix"
+// tokens, as found in APL presentation format, eg
+// "1:192.168.32.0/21 !1:192.168.38.0/28 2:2001:db8::/32".
+func ParseAPLList(text string) (APLList, error) {
+	var list APLList
+	for _, token := range strings.Fields(text) {
+		negate := false
+		if strings.HasPrefix(token, "!") {
+			negate = true
+			token = token[1:]
+		}
+		afiStr, rest, ok := strings.Cut(token, ":")
+		if !ok {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.apl.token"}}
+		}
+		afi, err := strconv.Atoi(afiStr)
+		if err != nil {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.apl.token"}}
+		}
+		if afi != 1 && afi != 2 {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.apl.token"}}
+		}
+		addrStr, prefixStr, ok := strings.Cut(rest, "/")
+		if !ok {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.apl.token"}}
+		}
+		prefixNum, err := strconv.Atoi(prefixStr)
+		if err != nil {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.apl.token"}}
+		}
+		addr, addrErr := NewIPAddressString(addrStr).ToAddress()
+		if addrErr != nil {
+			return nil, addrErr
+		}
+		list = append(list, &APLItem{Negate: negate, PrefixLen: BitCount(prefixNum), Prefix: addr})
+	}
+	return list, nil
+}
+
+// Matches reports whether ip falls within this APL list, applying RFC 3123's
+// ordered semantics: list entries are evaluated in order, and the last entry
+// whose prefix contains ip determines the result (a match under a negated
+// entry excludes ip, a match under a plain entry includes it); ip is excluded
+// if no entry's prefix contains it.
+func (list APLList) Matches(ip *IPAddress) bool {
+	matched := false
+	for _, item := range list {
+		if item.Prefix.ToPrefixBlockLen(item.PrefixLen).Contains(ip) {
+			matched = !item.Negate
+		}
+	}
+	return matched
+}
@@ -0,0 +1,135 @@
+package ipaddr
+
+import "fmt"
+
+// This file adds Append-style formatting methods alongside the existing
+// String()/ToXxxString() methods on AddressDivisionGrouping, AddressSection,
+// and IPAddress, for callers that want to write directly into a
+// caller-supplied buffer (eg a log line or a streaming encoder) instead of
+// allocating a fresh string per call. AppendText and AppendBinary also
+// implement the standard library's encoding.TextAppender and
+// encoding.BinaryAppender interfaces.
+
+// AppendText implements the encoding.TextAppender interface, appending the
+// same text String would return.
+func (grouping *AddressDivisionGrouping) AppendText(b []byte) ([]byte, error) {
+	return append(b, grouping.String()...), nil
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface, appending
+// this grouping's raw bytes.
+func (grouping *AddressDivisionGrouping) AppendBinary(b []byte) ([]byte, error) {
+	return append(b, grouping.GetBytes()...), nil
+}
+
+// AppendText implements the encoding.TextAppender interface, appending the
+// same text String would return.
+func (section *AddressSection) AppendText(b []byte) ([]byte, error) {
+	return append(b, section.String()...), nil
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface, appending
+// this section's raw bytes.
+func (section *AddressSection) AppendBinary(b []byte) ([]byte, error) {
+	return append(b, section.GetBytes()...), nil
+}
+
+// AppendCanonical appends the same text ToCanonicalString would return.
+func (section *AddressSection) AppendCanonical(b []byte) []byte {
+	return append(b, section.ToCanonicalString()...)
+}
+
+// AppendCompressed appends the same text ToCompressedString would return.
+func (section *AddressSection) AppendCompressed(b []byte) []byte {
+	return append(b, section.ToCompressedString()...)
+}
+
+// AppendReverseDNS appends the same text ToReverseDNSStringOpts would return,
+// per the given options.
+func (section *AddressSection) AppendReverseDNS(b []byte, opts ReverseDNSOptions) ([]byte, AddressStringException) {
+	str, err := section.ToReverseDNSStringOpts(opts)
+	if err != nil {
+		return b, err
+	}
+	return append(b, str...), nil
+}
+
+// AppendSegmentHex appends this section's segments as lowercase hexadecimal,
+// separated by ':', regardless of the section's own native separator or
+// radix, eg a MAC section's "aa:bb:cc:dd:ee:ff" unchanged, but an IPv4
+// section's "1.2.3.4" as "1:2:3:4".
+func (section *AddressSection) AppendSegmentHex(b []byte) []byte {
+	segCount := section.GetSegmentCount()
+	for i := 0; i < segCount; i++ {
+		if i > 0 {
+			b = append(b, ':')
+		}
+		b = append(b, fmt.Sprintf("%x", section.GetSegment(i).GetSegmentValue())...)
+	}
+	return b
+}
+
+// AppendText implements the encoding.TextAppender interface, appending the
+// same text String would return.
+func (addr *IPAddress) AppendText(b []byte) ([]byte, error) {
+	return append(b, addr.String()...), nil
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface, appending
+// the same bytes MarshalBinary would return.
+func (addr *IPAddress) AppendBinary(b []byte) ([]byte, error) {
+	data, err := addr.MarshalBinary()
+	if err != nil {
+		return b, err
+	}
+	return append(b, data...), nil
+}
+
+// AppendCanonical appends addr's canonical string form: ToCanonicalString for
+// an IPv6Address, and the ordinary String form, which is already canonical,
+// for an IPv4Address.
+func (addr *IPAddress) AppendCanonical(b []byte) []byte {
+	if v6 := addr.ToIPv6Address(); v6 != nil {
+		return append(b, v6.ToCanonicalString()...)
+	}
+	return append(b, addr.String()...)
+}
+
+// AppendCompressed appends addr's compressed string form: ToCompressedString
+// for an IPv6Address, and the ordinary String form, which IPv4 has no
+// shorter alternative to, for an IPv4Address.
+func (addr *IPAddress) AppendCompressed(b []byte) []byte {
+	if v6 := addr.ToIPv6Address(); v6 != nil {
+		return append(b, v6.ToCompressedString()...)
+	}
+	return append(b, addr.String()...)
+}
+
+// AppendReverseDNS appends the same text ToReverseDNSStringOpts would return for
+// addr's IPv4Address or IPv6Address form, per the given options.
+func (addr *IPAddress) AppendReverseDNS(b []byte, opts ReverseDNSOptions) ([]byte, AddressStringException) {
+	var str string
+	var err AddressStringException
+	if v4 := addr.ToIPv4Address(); v4 != nil {
+		str, err = v4.ToReverseDNSStringOpts(opts)
+	} else if v6 := addr.ToIPv6Address(); v6 != nil {
+		str, err = v6.ToReverseDNSStringOpts(opts)
+	} else {
+		return b, &addressStringError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	}
+	if err != nil {
+		return b, err
+	}
+	return append(b, str...), nil
+}
+
+// AppendSegmentHex appends addr's segments as lowercase hexadecimal,
+// separated by ':', per AddressSection.AppendSegmentHex.
+func (addr *IPAddress) AppendSegmentHex(b []byte) []byte {
+	if v4 := addr.ToIPv4Address(); v4 != nil {
+		return v4.GetSection().ToAddressSection().AppendSegmentHex(b)
+	} else if v6 := addr.ToIPv6Address(); v6 != nil {
+		return v6.GetSection().ToAddressSection().AppendSegmentHex(b)
+	}
+	return b
+}
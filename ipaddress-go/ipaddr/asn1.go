@@ -0,0 +1,137 @@
+package ipaddr
+
+// ToASN1DER returns the RFC 5280 GeneralName.iPAddress encoding of this
+// address: the 4- or 16-byte network-order address, suitable for wrapping
+// in an X.509 "[7] IMPLICIT OCTET STRING".  If this address is a prefix
+// block rather than a single address, use ToNameConstraintDER instead.
+func (addr *IPAddress) ToASN1DER() []byte {
+	addr = addr.init()
+	if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+		return ipv6Addr.GetBytes()
+	}
+	return addr.ToIPv4Address().GetBytes()
+}
+
+// FromASN1DER parses the RFC 5280 GeneralName.iPAddress encoding produced by
+// ToASN1DER: a 4-byte network-order IPv4 address or a 16-byte network-order
+// IPv6 address. Any other length is rejected.
+func FromASN1DER(data []byte) (*IPAddress, AddressValueException) {
+	switch len(data) {
+	case IPv4ByteCount:
+		addr, err := NewIPv4AddressFromIP(data)
+		if err != nil {
+			return nil, err
+		}
+		return addr.ToIPAddress(), nil
+	case IPv6ByteCount:
+		addr, err := NewIPv6AddressFromIP(data)
+		if err != nil {
+			return nil, err
+		}
+		return addr.ToIPAddress(), nil
+	default:
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+}
+
+// ToNameConstraintDER returns the RFC 5280 section 4.2.1.10 encoding of this
+// address as a name constraint: the address bytes followed by the network
+// mask bytes, 8 bytes total for IPv4 or 32 for IPv6.  This address must have
+// a network prefix length; the mask is derived from it.
+func (addr *IPAddress) ToNameConstraintDER() ([]byte, AddressValueException) {
+	addr = addr.init()
+	prefixLen := addr.GetNetworkPrefixLength()
+	if prefixLen == nil {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixLength.required"}}
+	}
+	network := addr.ToZeroHost()
+	addrBytes := network.GetBytes()
+	maskBytes := prefixMaskBytes(prefixLen.Len(), len(addrBytes)*8)
+	return append(append([]byte{}, addrBytes...), maskBytes...), nil
+}
+
+// prefixMaskBytes returns the bitCount-bit network mask, rendered as
+// byteBitCount/8 network-order bytes.
+func prefixMaskBytes(bitCount, byteBitCount BitCount) []byte {
+	bytes := make([]byte, byteBitCount/8)
+	for i := range bytes {
+		segStart := BitCount(i * 8)
+		switch {
+		case bitCount >= segStart+8:
+			bytes[i] = 0xff
+		case bitCount <= segStart:
+			bytes[i] = 0
+		default:
+			bytes[i] = byte(0xff << uint(8-(bitCount-segStart)))
+		}
+	}
+	return bytes
+}
+
+// FromNameConstraintDER parses the RFC 5280 section 4.2.1.10 name
+// constraint encoding produced by ToNameConstraintDER, reconstructing the
+// prefixed subnet: 8 bytes (4 address + 4 mask) for IPv4, or 32 bytes (16
+// address + 16 mask) for IPv6. The mask must be a contiguous run of set
+// bits followed by zero bits, as a valid network mask; anything else is
+// rejected.
+func FromNameConstraintDER(data []byte) (*IPAddress, AddressValueException) {
+	var addrBytes, maskBytes []byte
+	switch len(data) {
+	case 2 * IPv4ByteCount:
+		addrBytes, maskBytes = data[:IPv4ByteCount], data[IPv4ByteCount:]
+	case 2 * IPv6ByteCount:
+		addrBytes, maskBytes = data[:IPv6ByteCount], data[IPv6ByteCount:]
+	default:
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	prefixLen, ok := maskBytesToPrefixLen(maskBytes)
+	if !ok {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixLength.invalid"}}
+	}
+	addr, err := FromASN1DER(addrBytes)
+	if err != nil {
+		return nil, err
+	}
+	return addr.ToPrefixBlockLen(prefixLen), nil
+}
+
+// maskBytesToPrefixLen converts a network-order network mask to its prefix
+// length, reporting ok=false if mask is not a contiguous run of set bits
+// followed by zero bits.
+func maskBytesToPrefixLen(mask []byte) (prefixLen BitCount, ok bool) {
+	seenZero := false
+	for _, b := range mask {
+		if seenZero {
+			if b != 0 {
+				return 0, false
+			}
+			continue
+		}
+		switch b {
+		case 0xff:
+			prefixLen += 8
+		case 0:
+			seenZero = true
+		default:
+			bits, isContiguous := contiguousMaskBits(b)
+			if !isContiguous {
+				return 0, false
+			}
+			prefixLen += bits
+			seenZero = true
+		}
+	}
+	return prefixLen, true
+}
+
+// contiguousMaskBits reports the number of leading set bits in b if b is a
+// valid partial mask byte (a run of set bits followed by a run of clear
+// bits), eg 0xf0 -> (4, true).
+func contiguousMaskBits(b byte) (BitCount, bool) {
+	for k := BitCount(1); k < 8; k++ {
+		if b == byte(0xff<<uint(8-k)) {
+			return k, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,91 @@
+package ipaddr
+
+import "testing"
+
+// TestASN1DERRoundTrip checks that FromASN1DER reverses ToASN1DER for both
+// IPv4 and IPv6 addresses.
+func TestASN1DERRoundTrip(t *testing.T) {
+	tests := []string{"1.2.3.4", "0.0.0.0", "2001:db8::1", "::"}
+	for _, s := range tests {
+		addr, err := NewIPAddressString(s).ToAddress()
+		if err != nil {
+			t.Fatalf("%q: failed to parse: %v", s, err)
+		}
+		data := addr.ToASN1DER()
+		parsed, derr := FromASN1DER(data)
+		if derr != nil {
+			t.Fatalf("%q: FromASN1DER: %v", s, derr)
+		}
+		if parsed.String() != addr.String() {
+			t.Errorf("%q: round trip got %q, want %q", s, parsed.String(), addr.String())
+		}
+	}
+}
+
+// TestASN1DERInvalidLength checks that FromASN1DER rejects any length other
+// than 4 or 16 bytes, rather than misreading it as one family or the other.
+func TestASN1DERInvalidLength(t *testing.T) {
+	tests := [][]byte{
+		{},
+		{1, 2, 3},
+		{1, 2, 3, 4, 5},
+		make([]byte, 17),
+	}
+	for i, data := range tests {
+		if _, err := FromASN1DER(data); err == nil {
+			t.Errorf("case %d (len %d): expected an error, got none", i, len(data))
+		}
+	}
+}
+
+// TestNameConstraintDERRoundTrip checks that FromNameConstraintDER reverses
+// ToNameConstraintDER for prefixed IPv4 and IPv6 subnets.
+func TestNameConstraintDERRoundTrip(t *testing.T) {
+	tests := []string{"192.168.32.0/21", "10.0.0.0/8", "2001:db8::/32", "::/0"}
+	for _, s := range tests {
+		addr, err := NewIPAddressString(s).ToAddress()
+		if err != nil {
+			t.Fatalf("%q: failed to parse: %v", s, err)
+		}
+		data, derr := addr.ToNameConstraintDER()
+		if derr != nil {
+			t.Fatalf("%q: ToNameConstraintDER: %v", s, derr)
+		}
+		parsed, perr := FromNameConstraintDER(data)
+		if perr != nil {
+			t.Fatalf("%q: FromNameConstraintDER: %v", s, perr)
+		}
+		wantBlock := addr.ToPrefixBlockLen(addr.GetNetworkPrefixLength().Len())
+		if parsed.GetLower().String() != wantBlock.GetLower().String() ||
+			parsed.GetNetworkPrefixLength().Len() != wantBlock.GetNetworkPrefixLength().Len() {
+			t.Errorf("%q: round trip got %v, want %v", s, parsed, wantBlock)
+		}
+	}
+}
+
+// TestNameConstraintDERInvalidLength checks that FromNameConstraintDER
+// rejects any length other than 8 (IPv4) or 32 (IPv6) bytes.
+func TestNameConstraintDERInvalidLength(t *testing.T) {
+	tests := [][]byte{
+		{},
+		make([]byte, 7),
+		make([]byte, 9),
+		make([]byte, 31),
+	}
+	for i, data := range tests {
+		if _, err := FromNameConstraintDER(data); err == nil {
+			t.Errorf("case %d (len %d): expected an error, got none", i, len(data))
+		}
+	}
+}
+
+// TestNameConstraintDERNonContiguousMask checks that FromNameConstraintDER
+// rejects a mask that isn't a contiguous run of set bits followed by zero
+// bits, eg one with a "hole" in it.
+func TestNameConstraintDERNonContiguousMask(t *testing.T) {
+	// 1.2.3.4 followed by mask 255.0.255.0, which is not a valid network mask.
+	data := []byte{1, 2, 3, 4, 0xff, 0, 0xff, 0}
+	if _, err := FromNameConstraintDER(data); err == nil {
+		t.Error("non-contiguous mask: expected an error, got none")
+	}
+}
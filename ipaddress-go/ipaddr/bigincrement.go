@@ -0,0 +1,87 @@
+package ipaddr
+
+import "math/big"
+
+// IncrementBig returns the address that is the given number of addresses
+// higher (or, for a negative increment, lower) than the lowest address
+// represented by this subnet, mirroring Increment but without the int64
+// range limit.  This allows incrementing IPv6 subnets by offsets that do not
+// fit into an int64, such as 1<<64.  It returns nil on overflow past
+// the address space boundary.
+func (addr *IPAddress) IncrementBig(increment *big.Int) *IPAddress {
+	addr = addr.init()
+	value := new(big.Int).Add(addrValue(addr.GetLower()), increment)
+	if value.Sign() < 0 || value.Cmp(maxValueForVersion(addr.GetIPVersion())) > 0 {
+		return nil
+	}
+	return valueToAddr(value, addr.GetIPVersion())
+}
+
+// IncrementBig returns the address that is the given number of addresses
+// higher (or lower, for a negative increment) than the lowest address of
+// this subnet.  It returns nil on overflow past the address space boundary.
+func (addr *IPv6Address) IncrementBig(increment *big.Int) *IPv6Address {
+	return addr.ToIPAddress().IncrementBig(increment).ToIPv6Address()
+}
+
+// IncrementBig returns the address that is the given number of addresses
+// higher (or lower, for a negative increment) than the lowest address of
+// this subnet.  It returns nil on overflow past the address space boundary.
+func (addr *IPv4Address) IncrementBig(increment *big.Int) *IPv4Address {
+	return addr.ToIPAddress().IncrementBig(increment).ToIPv4Address()
+}
+
+// bigRangeIterator walks a lower/upper bound in strides of step, without
+// materializing every intermediate address of a very large range up front.
+type bigRangeIterator struct {
+	current *big.Int
+	upper   *big.Int
+	step    *big.Int
+	version IPVersion
+	done    bool
+}
+
+// HasNext returns whether there is a next address to produce.
+func (it *bigRangeIterator) HasNext() bool {
+	return !it.done
+}
+
+// Next returns the next address in the stride, advancing the iterator.
+func (it *bigRangeIterator) Next() *IPAddress {
+	if it.done {
+		return nil
+	}
+	result := valueToAddr(it.current, it.version)
+	next := new(big.Int).Add(it.current, it.step)
+	if next.Cmp(it.upper) > 0 {
+		it.done = true
+	} else {
+		it.current = next
+	}
+	return result
+}
+
+// IteratorBig returns an iterator over this subnet or range, from its lowest
+// to its highest address, advancing by step each time rather than by a
+// single address.  It is intended for traversing very large IPv6 ranges in
+// bulk without allocating an intermediate address for every skipped value.
+func (addr *IPAddress) IteratorBig(step *big.Int) IPAddressIterator {
+	addr = addr.init()
+	return &bigRangeIterator{
+		current: addrValue(addr.GetLower()),
+		upper:   addrValue(addr.GetUpper()),
+		step:    step,
+		version: addr.GetIPVersion(),
+	}
+}
+
+// IteratorBig returns an iterator over this range, from its lowest to its
+// highest address, advancing by step addresses at a time.
+func (rng *IPAddressSeqRange) IteratorBig(step *big.Int) IPAddressIterator {
+	return &bigRangeIterator{
+		current: addrValue(rng.GetLower()),
+		upper:   addrValue(rng.GetUpper()),
+		step:    step,
+		version: rng.GetLower().GetIPVersion(),
+	}
+}
@@ -0,0 +1,163 @@
+package ipaddr
+
+// BitwiseAnd returns the bitwise AND of addr and other, segment by segment.
+// Both addr and other must be single addresses, not a range or subnet with
+// multiple values, since the AND of two ranges is not in general itself a
+// single contiguous range; IncompatibleAddressException is returned
+// otherwise.
+func (addr *IPv4Address) BitwiseAnd(other *IPv4Address) (*IPv4Address, IncompatibleAddressException) {
+	return addr.bitwiseCombine(other, func(a, b SegInt) SegInt { return a & b })
+}
+
+// BitwiseOr returns the bitwise OR of addr and other, segment by segment,
+// under the same single-address requirement as BitwiseAnd.
+func (addr *IPv4Address) BitwiseOr(other *IPv4Address) (*IPv4Address, IncompatibleAddressException) {
+	return addr.bitwiseCombine(other, func(a, b SegInt) SegInt { return a | b })
+}
+
+// BitwiseXor returns the bitwise XOR of addr and other, segment by segment,
+// under the same single-address requirement as BitwiseAnd.
+func (addr *IPv4Address) BitwiseXor(other *IPv4Address) (*IPv4Address, IncompatibleAddressException) {
+	return addr.bitwiseCombine(other, func(a, b SegInt) SegInt { return a ^ b })
+}
+
+// BitwiseNot returns the bitwise complement of addr, segment by segment.
+// addr must be a single address; IncompatibleAddressException is returned
+// for a range or subnet with multiple values.
+func (addr *IPv4Address) BitwiseNot() (*IPv4Address, IncompatibleAddressException) {
+	addr = addr.init()
+	if addr.IsMultiple() {
+		return nil, &incompatibleAddressError{addressError: addressError{key: "ipaddress.error.bitwise.range"}}
+	}
+	return NewIPv4AddressFromValues(func(i int) SegInt {
+		return ^addr.GetSegment(i).GetSegmentValue() & 0xff
+	}), nil
+}
+
+func (addr *IPv4Address) bitwiseCombine(other *IPv4Address, op func(a, b SegInt) SegInt) (*IPv4Address, IncompatibleAddressException) {
+	addr = addr.init()
+	if addr.IsMultiple() || other.IsMultiple() {
+		return nil, &incompatibleAddressError{addressError: addressError{key: "ipaddress.error.bitwise.range"}}
+	}
+	return NewIPv4AddressFromValues(func(i int) SegInt {
+		return op(addr.GetSegment(i).GetSegmentValue(), other.GetSegment(i).GetSegmentValue())
+	}), nil
+}
+
+// BitwiseAnd returns the bitwise AND of addr and other, segment by segment.
+// Both addr and other must be single addresses, not a range or subnet with
+// multiple values, since the AND of two ranges is not in general itself a
+// single contiguous range; IncompatibleAddressException is returned
+// otherwise.
+func (addr *IPv6Address) BitwiseAnd(other *IPv6Address) (*IPv6Address, IncompatibleAddressException) {
+	return addr.bitwiseCombine(other, func(a, b SegInt) SegInt { return a & b })
+}
+
+// BitwiseOr returns the bitwise OR of addr and other, segment by segment,
+// under the same single-address requirement as BitwiseAnd.
+func (addr *IPv6Address) BitwiseOr(other *IPv6Address) (*IPv6Address, IncompatibleAddressException) {
+	return addr.bitwiseCombine(other, func(a, b SegInt) SegInt { return a | b })
+}
+
+// BitwiseXor returns the bitwise XOR of addr and other, segment by segment,
+// under the same single-address requirement as BitwiseAnd.
+func (addr *IPv6Address) BitwiseXor(other *IPv6Address) (*IPv6Address, IncompatibleAddressException) {
+	return addr.bitwiseCombine(other, func(a, b SegInt) SegInt { return a ^ b })
+}
+
+// BitwiseNot returns the bitwise complement of addr, segment by segment.
+// addr must be a single address; IncompatibleAddressException is returned
+// for a range or subnet with multiple values.
+func (addr *IPv6Address) BitwiseNot() (*IPv6Address, IncompatibleAddressException) {
+	addr = addr.init()
+	if addr.IsMultiple() {
+		return nil, &incompatibleAddressError{addressError: addressError{key: "ipaddress.error.bitwise.range"}}
+	}
+	maxVal := addr.GetMaxSegmentValue()
+	return NewIPv6AddressFromVals(func(i int) SegInt {
+		return ^addr.GetSegment(i).GetSegmentValue() & maxVal
+	}), nil
+}
+
+func (addr *IPv6Address) bitwiseCombine(other *IPv6Address, op func(a, b SegInt) SegInt) (*IPv6Address, IncompatibleAddressException) {
+	addr = addr.init()
+	if addr.IsMultiple() || other.IsMultiple() {
+		return nil, &incompatibleAddressError{addressError: addressError{key: "ipaddress.error.bitwise.range"}}
+	}
+	return NewIPv6AddressFromVals(func(i int) SegInt {
+		return op(addr.GetSegment(i).GetSegmentValue(), other.GetSegment(i).GetSegmentValue())
+	}), nil
+}
+
+// BitwiseAnd returns the bitwise AND of addr and other, dispatching to
+// IPv4Address.BitwiseAnd or IPv6Address.BitwiseAnd according to their
+// shared IP version. IncompatibleAddressException is returned if the two
+// addresses are not the same IP version, or per the single-address
+// requirement of the version-specific method.
+func (addr *IPAddress) BitwiseAnd(other *IPAddress) (*IPAddress, IncompatibleAddressException) {
+	return addr.bitwiseDispatch(other,
+		func(a, b *IPv4Address) (*IPv4Address, IncompatibleAddressException) { return a.BitwiseAnd(b) },
+		func(a, b *IPv6Address) (*IPv6Address, IncompatibleAddressException) { return a.BitwiseAnd(b) })
+}
+
+// BitwiseOr returns the bitwise OR of addr and other, under the same
+// dispatch rules as BitwiseAnd.
+func (addr *IPAddress) BitwiseOr(other *IPAddress) (*IPAddress, IncompatibleAddressException) {
+	return addr.bitwiseDispatch(other,
+		func(a, b *IPv4Address) (*IPv4Address, IncompatibleAddressException) { return a.BitwiseOr(b) },
+		func(a, b *IPv6Address) (*IPv6Address, IncompatibleAddressException) { return a.BitwiseOr(b) })
+}
+
+// BitwiseXor returns the bitwise XOR of addr and other, under the same
+// dispatch rules as BitwiseAnd.
+func (addr *IPAddress) BitwiseXor(other *IPAddress) (*IPAddress, IncompatibleAddressException) {
+	return addr.bitwiseDispatch(other,
+		func(a, b *IPv4Address) (*IPv4Address, IncompatibleAddressException) { return a.BitwiseXor(b) },
+		func(a, b *IPv6Address) (*IPv6Address, IncompatibleAddressException) { return a.BitwiseXor(b) })
+}
+
+// BitwiseNot returns the bitwise complement of addr, per the single-address
+// requirement of IPv4Address.BitwiseNot/IPv6Address.BitwiseNot.
+func (addr *IPAddress) BitwiseNot() (*IPAddress, IncompatibleAddressException) {
+	addr = addr.init()
+	if thisAddr := addr.ToIPv4Address(); thisAddr != nil {
+		res, err := thisAddr.BitwiseNot()
+		if err != nil {
+			return nil, err
+		}
+		return res.ToIPAddress(), nil
+	} else if thisAddr := addr.ToIPv6Address(); thisAddr != nil {
+		res, err := thisAddr.BitwiseNot()
+		if err != nil {
+			return nil, err
+		}
+		return res.ToIPAddress(), nil
+	}
+	return nil, &incompatibleAddressError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+}
+
+func (addr *IPAddress) bitwiseDispatch(
+	other *IPAddress,
+	v4 func(a, b *IPv4Address) (*IPv4Address, IncompatibleAddressException),
+	v6 func(a, b *IPv6Address) (*IPv6Address, IncompatibleAddressException),
+) (*IPAddress, IncompatibleAddressException) {
+	addr = addr.init()
+	if thisAddr := addr.ToIPv4Address(); thisAddr != nil {
+		if oth := other.ToIPv4Address(); oth != nil {
+			res, err := v4(thisAddr, oth)
+			if err != nil {
+				return nil, err
+			}
+			return res.ToIPAddress(), nil
+		}
+	} else if thisAddr := addr.ToIPv6Address(); thisAddr != nil {
+		if oth := other.ToIPv6Address(); oth != nil {
+			res, err := v6(thisAddr, oth)
+			if err != nil {
+				return nil, err
+			}
+			return res.ToIPAddress(), nil
+		}
+	}
+	return nil, &incompatibleAddressError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+}
@@ -0,0 +1,44 @@
+package ipaddr
+
+// This file adds zero-copy bulk byte accessors to AddressDivisionGrouping,
+// for hot paths such as trie inserts or hashing large sets of sections,
+// where GetBytes/GetUpperBytes's unconditional cloneBytes of the cached
+// slice is wasted work. AppendBytes/AppendUpperBytes mirror net/netip's
+// AppendTo/MarshalBinary append-into-caller-slice convention; BytesView
+// goes one step further and hands back the cached slice itself rather than
+// even a caller-supplied copy, for read-only callers willing to accept the
+// aliasing risk documented below.
+
+// AppendBytes appends this grouping's lowest value, in the same byte order
+// as GetBytes, to dst and returns the extended slice, without cloning the
+// cached bytes the way GetBytes does.
+func (grouping *AddressDivisionGrouping) AppendBytes(dst []byte) []byte {
+	if grouping.hasNoDivisions() {
+		return dst
+	}
+	return append(dst, grouping.getBytes()...)
+}
+
+// AppendUpperBytes appends this grouping's highest value, in the same byte
+// order as GetUpperBytes, to dst and returns the extended slice, without
+// cloning the cached bytes the way GetUpperBytes does.
+func (grouping *AddressDivisionGrouping) AppendUpperBytes(dst []byte) []byte {
+	if grouping.hasNoDivisions() {
+		return dst
+	}
+	return append(dst, grouping.getUpperBytes()...)
+}
+
+// BytesView returns lower and upper slices aliasing this grouping's cached
+// byte representation directly, without the per-call allocation GetBytes
+// and GetUpperBytes incur. Callers must treat the returned slices as
+// read-only: mutating them corrupts the cache shared by every other caller
+// of GetBytes, GetUpperBytes, and BytesView on this grouping. lower and
+// upper are the same slice when the grouping holds a single value, exactly
+// as the cache itself is shared in that case.
+func (grouping *AddressDivisionGrouping) BytesView() (lower, upper []byte) {
+	if grouping.hasNoDivisions() {
+		return emptyBytes, emptyBytes
+	}
+	return grouping.getBytes(), grouping.getUpperBytes()
+}
@@ -0,0 +1,27 @@
+package ipaddr
+
+import "testing"
+
+var benchGrouping = func() *AddressDivisionGrouping {
+	addr, _ := NewIPAddressString("2001:db8::1/64").ToAddress()
+	return addr.ToIPv6Address().GetSection().ToAddressSection().ToAddressDivisionGrouping()
+}()
+
+func BenchmarkGetBytes(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = benchGrouping.GetBytes()
+	}
+}
+
+func BenchmarkAppendBytes(b *testing.B) {
+	buf := make([]byte, 0, 16)
+	for i := 0; i < b.N; i++ {
+		_ = benchGrouping.AppendBytes(buf[:0])
+	}
+}
+
+func BenchmarkBytesView(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = benchGrouping.BytesView()
+	}
+}
@@ -0,0 +1,106 @@
+package ipaddr
+
+import "math/big"
+
+// CidrHost returns the address within this prefix block at host offset
+// hostnum, mirroring Terraform's cidrhost function: network | (hostnum &
+// hostmask).  A negative hostnum counts down from the broadcast/last
+// address, eg -1 is the last address in the block.  It is an error for
+// hostnum to not fit within the block's host bits.
+func (addr *IPAddress) CidrHost(hostnum *big.Int) (*IPAddress, AddressValueException) {
+	addr = addr.init()
+	prefixLen := addr.GetNetworkPrefixLength()
+	if prefixLen == nil {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixLength.required"}}
+	}
+	hostBits := BitCount(ipVersionBitCount(addr.GetIPVersion())) - prefixLen.Len()
+	hostCount := new(big.Int).Lsh(bigOne(), uint(hostBits))
+
+	offset := new(big.Int).Set(hostnum)
+	if offset.Sign() < 0 {
+		offset.Add(offset, hostCount)
+	}
+	if offset.Sign() < 0 || offset.Cmp(hostCount) >= 0 {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+	}
+
+	network := addr.ToZeroHost()
+	return network.IncrementBig(offset), nil
+}
+
+// CidrSubnet extends this prefix block's prefix length by newbits and
+// selects the netnum'th child block of that new length, mirroring
+// Terraform's cidrsubnet function.
+func (addr *IPAddress) CidrSubnet(newbits BitCount, netnum *big.Int) (*IPAddress, AddressValueException) {
+	addr = addr.init()
+	prefixLen := addr.GetNetworkPrefixLength()
+	if prefixLen == nil {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixLength.required"}}
+	}
+	newPrefixLen := prefixLen.Len() + newbits
+	bitCount := BitCount(ipVersionBitCount(addr.GetIPVersion()))
+	if newPrefixLen > bitCount {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+	}
+	subnetCount := new(big.Int).Lsh(bigOne(), uint(newbits))
+	if netnum.Sign() < 0 || netnum.Cmp(subnetCount) >= 0 {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+	}
+
+	network := addrValue(addr.ToZeroHost().GetLower())
+	shift := bitCount - newPrefixLen
+	offset := new(big.Int).Lsh(netnum, uint(shift))
+	value := new(big.Int).Add(network, offset)
+	result := valueToAddr(value, addr.GetIPVersion())
+	return result.ToPrefixBlockLen(newPrefixLen), nil
+}
+
+// CidrSubnets is the plural form of CidrSubnet, computing one child block
+// per requested newbits value; the Nth requested block's netnum is chosen
+// automatically so that the blocks never overlap, mirroring Terraform's
+// cidrsubnets function.
+func (addr *IPAddress) CidrSubnets(newbitsList ...BitCount) ([]*IPAddress, AddressValueException) {
+	addr = addr.init()
+	prefixLen := addr.GetNetworkPrefixLength()
+	if prefixLen == nil {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixLength.required"}}
+	}
+	results := make([]*IPAddress, len(newbitsList))
+	current := addr.ToZeroHost()
+	basePrefixLen := prefixLen.Len()
+	for i, newbits := range newbitsList {
+		newPrefixLen := basePrefixLen + newbits
+		bitCount := BitCount(ipVersionBitCount(addr.GetIPVersion()))
+		if newPrefixLen > bitCount {
+			return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+		}
+		// align the start up to the next boundary of the requested size
+		size := new(big.Int).Lsh(bigOne(), uint(bitCount-newPrefixLen))
+		start := addrValue(current.GetLower())
+		if rem := new(big.Int).Mod(start, size); rem.Sign() != 0 {
+			start.Add(start, size).Sub(start, rem)
+		}
+		block := valueToAddr(start, addr.GetIPVersion()).ToPrefixBlockLen(newPrefixLen)
+		results[i] = block
+		current = block.GetUpper().IncrementBig(bigOne())
+		current = current.SetPrefixLen(basePrefixLen)
+	}
+	return results, nil
+}
+
+// CidrNetmask returns the dotted-decimal (IPv4) or colon-hex (IPv6) netmask
+// string for this address's prefix length, mirroring Terraform's
+// cidrnetmask function.
+func (addr *IPAddress) CidrNetmask() (string, AddressValueException) {
+	addr = addr.init()
+	prefixLen := addr.GetNetworkPrefixLength()
+	if prefixLen == nil {
+		return "", &addressValueError{addressError: addressError{key: "ipaddress.error.prefixLength.required"}}
+	}
+	if addr.IsIPv4() {
+		return ipv4MaskFor(prefixLen.Len()).String(), nil
+	}
+	value := new(big.Int).Lsh(bigOne(), uint(IPv6BitCount-prefixLen.Len()))
+	value.Sub(new(big.Int).Lsh(bigOne(), IPv6BitCount), value)
+	return valueToAddr(value, IPv6).String(), nil
+}
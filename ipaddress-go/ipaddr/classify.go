@@ -0,0 +1,396 @@
+package ipaddr
+
+// AddressClass is a bitmask identifying which IANA special-purpose address
+// registry entries (RFC 6890 and its IPv4/IPv6-specific successors, RFC 5735,
+// RFC 3879, RFC 3927, RFC 4193, RFC 4291 and RFC 7526) an address falls into,
+// if any. A multicast address ORs one of the Scope* bits describing its
+// RFC 4291 section 2.7 scope onto ClassMulticast.
+type AddressClass uint32
+
+const (
+	ClassGlobalUnicast AddressClass = 0
+	ClassUnspecified   AddressClass = 1 << (iota - 1)
+	ClassLoopback
+	ClassPrivate
+	ClassLinkLocal
+	ClassSiteLocal // deprecated, RFC 3879
+	ClassMulticast
+	ClassDocumentation
+	ClassBenchmarking
+	ClassCarrierGradeNAT
+	ClassIPv4Mapped
+	ClassUniqueLocal
+	Class6to4
+	ClassTeredo
+	ClassE
+	ClassReserved
+)
+
+// Multicast scope bits, OR'd onto ClassMulticast to report the RFC 4291
+// section 2.7 scope of an IPv6 multicast address. They occupy a separate
+// range of bits from the class bits above so the two can be tested
+// independently.
+const (
+	ScopeInterfaceLocal AddressClass = 1 << (16 + iota)
+	ScopeLinkLocal
+	ScopeAdminLocal
+	ScopeSiteLocal
+	ScopeOrgLocal
+	ScopeGlobal
+)
+
+func (c AddressClass) String() string {
+	switch c &^ (ScopeInterfaceLocal | ScopeLinkLocal | ScopeAdminLocal | ScopeSiteLocal | ScopeOrgLocal | ScopeGlobal) {
+	case ClassGlobalUnicast:
+		return "global-unicast"
+	case ClassUnspecified:
+		return "unspecified"
+	case ClassLoopback:
+		return "loopback"
+	case ClassPrivate:
+		return "private"
+	case ClassLinkLocal:
+		return "link-local"
+	case ClassSiteLocal:
+		return "site-local"
+	case ClassMulticast:
+		return "multicast"
+	case ClassDocumentation:
+		return "documentation"
+	case ClassBenchmarking:
+		return "benchmarking"
+	case ClassCarrierGradeNAT:
+		return "carrier-grade-nat"
+	case ClassIPv4Mapped:
+		return "ipv4-mapped"
+	case ClassUniqueLocal:
+		return "unique-local"
+	case Class6to4:
+		return "6to4"
+	case ClassTeredo:
+		return "teredo"
+	case ClassE:
+		return "class-e"
+	case ClassReserved:
+		return "reserved"
+	}
+	return "unknown"
+}
+
+// mustPrefixBlock parses a CIDR literal known to be valid at init time.
+func mustPrefixBlock(cidr string) *IPAddress {
+	addr, err := NewIPAddressString(cidr).ToAddress()
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}
+
+var classificationRanges = []struct {
+	block *IPAddress
+	class AddressClass
+}{
+	{mustPrefixBlock("0.0.0.0/32"), ClassUnspecified},
+	{mustPrefixBlock("127.0.0.0/8"), ClassLoopback},
+	{mustPrefixBlock("10.0.0.0/8"), ClassPrivate},
+	{mustPrefixBlock("172.16.0.0/12"), ClassPrivate},
+	{mustPrefixBlock("192.168.0.0/16"), ClassPrivate},
+	{mustPrefixBlock("169.254.0.0/16"), ClassLinkLocal},
+	{mustPrefixBlock("100.64.0.0/10"), ClassCarrierGradeNAT},
+	{mustPrefixBlock("192.0.2.0/24"), ClassDocumentation},
+	{mustPrefixBlock("198.51.100.0/24"), ClassDocumentation},
+	{mustPrefixBlock("203.0.113.0/24"), ClassDocumentation},
+	{mustPrefixBlock("198.18.0.0/15"), ClassBenchmarking},
+	{mustPrefixBlock("240.0.0.0/4"), ClassE},
+	{mustPrefixBlock("224.0.0.0/4"), ClassMulticast},
+
+	{mustPrefixBlock("::/128"), ClassUnspecified},
+	{mustPrefixBlock("::1/128"), ClassLoopback},
+	{mustPrefixBlock("::ffff:0:0/96"), ClassIPv4Mapped},
+	{mustPrefixBlock("fe80::/10"), ClassLinkLocal},
+	{mustPrefixBlock("fec0::/10"), ClassSiteLocal},
+	{mustPrefixBlock("fc00::/7"), ClassUniqueLocal},
+	{mustPrefixBlock("2001:db8::/32"), ClassDocumentation},
+	{mustPrefixBlock("2001:2::/48"), ClassBenchmarking},
+	{mustPrefixBlock("2001::/32"), ClassTeredo},
+	{mustPrefixBlock("2002::/16"), Class6to4},
+	{mustPrefixBlock("ff00::/8"), ClassMulticast},
+}
+
+// Classify reports which IANA special-purpose registry entry this address's
+// lower bound falls into, returning ClassGlobalUnicast if none of the known
+// special-purpose ranges apply. For an IPv6 multicast address, the RFC 4291
+// scope is additionally OR'd in as one of the Scope* bits.
+func (addr *IPAddress) Classify() AddressClass {
+	if addr == nil {
+		return ClassReserved
+	}
+	addr = addr.init().GetLower()
+	val := addrValue(addr)
+	for _, entry := range classificationRanges {
+		if entry.block.GetIPVersion() != addr.GetIPVersion() {
+			continue
+		}
+		if val.Cmp(addrValue(entry.block.GetLower())) >= 0 && val.Cmp(addrValue(entry.block.GetUpper())) <= 0 {
+			class := entry.class
+			if class == ClassMulticast {
+				if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+					class |= multicastScope(ipv6Addr)
+				}
+			}
+			return class
+		}
+	}
+	return ClassGlobalUnicast
+}
+
+// multicastScope decodes the RFC 4291 section 2.7 scope nibble (the low 4
+// bits of an IPv6 multicast address's second byte) into its Scope* bit, or 0
+// if the scope value is reserved or not yet assigned.
+func multicastScope(addr *IPv6Address) AddressClass {
+	switch addr.GetSegment(0).GetSegmentValue() & 0xf {
+	case 1:
+		return ScopeInterfaceLocal
+	case 2:
+		return ScopeLinkLocal
+	case 4:
+		return ScopeAdminLocal
+	case 5:
+		return ScopeSiteLocal
+	case 8:
+		return ScopeOrgLocal
+	case 0xe:
+		return ScopeGlobal
+	}
+	return 0
+}
+
+// IsSpecialPurpose returns whether Classify reports anything other than
+// ClassGlobalUnicast for this address.
+func (addr *IPAddress) IsSpecialPurpose() bool {
+	return addr.Classify() != ClassGlobalUnicast
+}
+
+// Tribool is a tri-valued boolean returned by the classification predicates
+// below (IsGlobal, IsShared, and the rest): TriUnknown means addr is a
+// multi-valued wildcard or CIDR block that straddles the boundary of the
+// range in question, so neither TriTrue nor TriFalse can be given without
+// being wrong for part of the range.
+type Tribool int
+
+const (
+	TriFalse Tribool = iota
+	TriTrue
+	TriUnknown
+)
+
+func (t Tribool) String() string {
+	switch t {
+	case TriFalse:
+		return "false"
+	case TriTrue:
+		return "true"
+	}
+	return "unknown"
+}
+
+// Bool reports t as a plain bool, treating TriUnknown as false: a range that
+// only partially matches should not be reported as a match.
+func (t Tribool) Bool() bool {
+	return t == TriTrue
+}
+
+func (t Tribool) negate() Tribool {
+	switch t {
+	case TriTrue:
+		return TriFalse
+	case TriFalse:
+		return TriTrue
+	}
+	return TriUnknown
+}
+
+// classifyTri reports whether addr's full range (its GetLower to GetUpper
+// span) lies entirely within a single classificationRanges entry whose
+// class satisfies match (TriTrue), overlaps no such entry (TriFalse), or
+// neither (TriUnknown).  A range covered only by several matching entries
+// together, and by no one of them alone, is reported as TriUnknown.
+// Entries for the wrong IP version are skipped, same as Classify.
+func classifyTri(addr *IPAddress, match func(AddressClass) bool) Tribool {
+	if addr == nil {
+		return TriFalse
+	}
+	addr = addr.init()
+	lower := addrValue(addr.GetLower())
+	upper := addrValue(addr.GetUpper())
+	anyOverlap := false
+	for _, entry := range classificationRanges {
+		if entry.block.GetIPVersion() != addr.GetIPVersion() || !match(entry.class) {
+			continue
+		}
+		blockLower := addrValue(entry.block.GetLower())
+		blockUpper := addrValue(entry.block.GetUpper())
+		if upper.Cmp(blockLower) < 0 || lower.Cmp(blockUpper) > 0 {
+			continue
+		}
+		if lower.Cmp(blockLower) >= 0 && upper.Cmp(blockUpper) <= 0 {
+			return TriTrue
+		}
+		anyOverlap = true
+	}
+	if anyOverlap {
+		return TriUnknown
+	}
+	return TriFalse
+}
+
+// IsGlobal reports whether addr falls outside every IANA special-purpose
+// range recognized by Classify, ie whether it is globally routable. It is
+// range-aware: a wildcard or CIDR block straddling a special-purpose
+// boundary (eg 10.0.0.0/7, half RFC 1918 private and half global) reports
+// TriUnknown rather than guessing from one bound alone.
+func (addr *IPAddress) IsGlobal() Tribool {
+	return classifyTri(addr, func(AddressClass) bool { return true }).negate()
+}
+
+// IsShared reports whether addr falls in the RFC 6598 Shared Address Space
+// (100.64.0.0/10), used by carrier-grade NAT.
+func (addr *IPAddress) IsShared() Tribool {
+	return classifyTri(addr, func(c AddressClass) bool { return c == ClassCarrierGradeNAT })
+}
+
+// IsBenchmarking reports whether addr falls in the RFC 2544/RFC 5180
+// benchmarking ranges (198.18.0.0/15, 2001:2::/48).
+func (addr *IPAddress) IsBenchmarking() Tribool {
+	return classifyTri(addr, func(c AddressClass) bool { return c == ClassBenchmarking })
+}
+
+// IsDocumentation reports whether addr falls in one of the ranges reserved
+// for documentation and examples (192.0.2.0/24, 198.51.100.0/24,
+// 203.0.113.0/24, 2001:db8::/32).
+func (addr *IPAddress) IsDocumentation() Tribool {
+	return classifyTri(addr, func(c AddressClass) bool { return c == ClassDocumentation })
+}
+
+// IsReserved reports whether addr falls in the IPv4 "Class E" range
+// (240.0.0.0/4) reserved by the IETF for future use.
+func (addr *IPAddress) IsReserved() Tribool {
+	return classifyTri(addr, func(c AddressClass) bool { return c == ClassE })
+}
+
+// IsUniqueLocal reports whether addr falls in the RFC 4193 Unique Local
+// Address range (fc00::/7).
+func (addr *IPAddress) IsUniqueLocal() Tribool {
+	return classifyTri(addr, func(c AddressClass) bool { return c == ClassUniqueLocal })
+}
+
+// IsUnicastLinkLocal reports whether addr falls in a unicast link-local
+// range (169.254.0.0/16, fe80::/10). It does not match link-local-scoped
+// multicast; use Classify and test for ScopeLinkLocal for that.
+func (addr *IPAddress) IsUnicastLinkLocal() Tribool {
+	return classifyTri(addr, func(c AddressClass) bool { return c == ClassLinkLocal })
+}
+
+// IsIPv4Mapped reports whether addr falls in the IPv4-mapped IPv6 range
+// (::ffff:0:0/96, RFC 4291 section 2.5.5.2). It is always TriFalse for an
+// IPv4 address. This tri-valued form lives on IPAddress rather than
+// IPv6Address because IPv6Address already has a plain bool IsIPv4Mapped
+// (ipv4mapped.go) predating this classification surface.
+func (addr *IPAddress) IsIPv4Mapped() Tribool {
+	return classifyTri(addr, func(c AddressClass) bool { return c == ClassIPv4Mapped })
+}
+
+// IsGlobal is the *IPv4Address counterpart of (*IPAddress).IsGlobal.
+func (addr *IPv4Address) IsGlobal() Tribool { return addr.ToIPAddress().IsGlobal() }
+
+// IsShared is the *IPv4Address counterpart of (*IPAddress).IsShared.
+func (addr *IPv4Address) IsShared() Tribool { return addr.ToIPAddress().IsShared() }
+
+// IsBenchmarking is the *IPv4Address counterpart of (*IPAddress).IsBenchmarking.
+func (addr *IPv4Address) IsBenchmarking() Tribool { return addr.ToIPAddress().IsBenchmarking() }
+
+// IsDocumentation is the *IPv4Address counterpart of (*IPAddress).IsDocumentation.
+func (addr *IPv4Address) IsDocumentation() Tribool { return addr.ToIPAddress().IsDocumentation() }
+
+// IsReserved is the *IPv4Address counterpart of (*IPAddress).IsReserved.
+func (addr *IPv4Address) IsReserved() Tribool { return addr.ToIPAddress().IsReserved() }
+
+// IsUniqueLocal is the *IPv4Address counterpart of (*IPAddress).IsUniqueLocal;
+// always TriFalse, since unique local addresses are an IPv6-only concept.
+func (addr *IPv4Address) IsUniqueLocal() Tribool { return addr.ToIPAddress().IsUniqueLocal() }
+
+// IsUnicastLinkLocal is the *IPv4Address counterpart of
+// (*IPAddress).IsUnicastLinkLocal.
+func (addr *IPv4Address) IsUnicastLinkLocal() Tribool { return addr.ToIPAddress().IsUnicastLinkLocal() }
+
+// IsGlobal is the *IPv6Address counterpart of (*IPAddress).IsGlobal.
+func (addr *IPv6Address) IsGlobal() Tribool { return addr.ToIPAddress().IsGlobal() }
+
+// IsShared is the *IPv6Address counterpart of (*IPAddress).IsShared; always
+// TriFalse, since Shared Address Space is an IPv4-only concept.
+func (addr *IPv6Address) IsShared() Tribool { return addr.ToIPAddress().IsShared() }
+
+// IsBenchmarking is the *IPv6Address counterpart of (*IPAddress).IsBenchmarking.
+func (addr *IPv6Address) IsBenchmarking() Tribool { return addr.ToIPAddress().IsBenchmarking() }
+
+// IsDocumentation is the *IPv6Address counterpart of (*IPAddress).IsDocumentation.
+func (addr *IPv6Address) IsDocumentation() Tribool { return addr.ToIPAddress().IsDocumentation() }
+
+// IsReserved is the *IPv6Address counterpart of (*IPAddress).IsReserved;
+// always TriFalse, since the 240.0.0.0/4 Class E range is IPv4-only.
+func (addr *IPv6Address) IsReserved() Tribool { return addr.ToIPAddress().IsReserved() }
+
+// IsUniqueLocal is the *IPv6Address counterpart of (*IPAddress).IsUniqueLocal.
+func (addr *IPv6Address) IsUniqueLocal() Tribool { return addr.ToIPAddress().IsUniqueLocal() }
+
+// IsUnicastLinkLocal is the *IPv6Address counterpart of
+// (*IPAddress).IsUnicastLinkLocal.
+func (addr *IPv6Address) IsUnicastLinkLocal() Tribool { return addr.ToIPAddress().IsUnicastLinkLocal() }
+
+// Entry is one row of a SpecialPurposeRegistry: a special-purpose prefix
+// together with its name and any descriptive tags (eg "source",
+// "destination", "forwardable"), mirroring the columns of IANA's
+// special-purpose address registries.
+type Entry struct {
+	Prefix *IPAddress
+	Name   string
+	Tags   []string
+}
+
+// SpecialPurposeRegistry holds special-purpose address prefixes and lets a
+// caller register additional, organization-specific entries alongside the
+// standard IANA ones that back Classify.
+type SpecialPurposeRegistry struct {
+	entries []Entry
+}
+
+// NewSpecialPurposeRegistry returns a registry pre-populated with the same
+// IANA special-purpose entries that back Classify.
+func NewSpecialPurposeRegistry() *SpecialPurposeRegistry {
+	reg := &SpecialPurposeRegistry{entries: make([]Entry, 0, len(classificationRanges))}
+	for _, r := range classificationRanges {
+		reg.entries = append(reg.entries, Entry{Prefix: r.block, Name: r.class.String()})
+	}
+	return reg
+}
+
+// Register adds a user-defined entry to the registry. Entries are matched by
+// Lookup in registration order, and the IANA defaults are registered first,
+// so a matching custom entry comes after any matching default entry in
+// Lookup's result.
+func (reg *SpecialPurposeRegistry) Register(prefix *IPAddress, name string, tags []string) {
+	reg.entries = append(reg.entries, Entry{Prefix: prefix, Name: name, Tags: tags})
+}
+
+// Lookup returns every registered entry whose prefix contains addr, in
+// registration order.
+func (reg *SpecialPurposeRegistry) Lookup(addr *IPAddress) []Entry {
+	addr = addr.init()
+	var result []Entry
+	for _, e := range reg.entries {
+		if e.Prefix.Contains(addr) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
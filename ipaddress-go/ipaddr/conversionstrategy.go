@@ -0,0 +1,193 @@
+package ipaddr
+
+// This file fulfills the promise in IPAddressNetwork's doc comment "to
+// provide your own IP address conversion between IPv4 and IPv6": a
+// pluggable ConversionStrategy, with built-in implementations for the three
+// conversions in common use, wired through IPv4AddressNetwork and
+// IPv6AddressNetwork so callers can swap the strategy per network instead of
+// being stuck with the hard-coded IPv4-mapped form used by ToIPv4MappedIPv6
+// and Unmap.
+
+// ConversionStrategy converts between IPv4 and IPv6 addresses. ToIPv4
+// reports false if v6 does not represent an embedded IPv4 address under this
+// strategy.
+type ConversionStrategy interface {
+	ToIPv4(v6 *IPv6Address) (*IPv4Address, bool)
+	ToIPv6(v4 *IPv4Address) *IPv6Address
+}
+
+// ipv4MappedStrategy implements the IPv4-mapped form, ::ffff:a.b.c.d, as
+// defined by RFC 4291 section 2.5.5.2. This is the default strategy.
+type ipv4MappedStrategy struct{}
+
+func (ipv4MappedStrategy) ToIPv4(v6 *IPv6Address) (*IPv4Address, bool) {
+	if !v6.IsIPv4Mapped() {
+		return nil, false
+	}
+	addr, err := NewIPv4AddressFromIP(v6.GetBytes()[12:])
+	if err != nil {
+		return nil, false
+	}
+	return addr, true
+}
+
+func (ipv4MappedStrategy) ToIPv6(v4 *IPv4Address) *IPv6Address {
+	return v4.ToIPv4MappedIPv6()
+}
+
+// IPv4MappedStrategy is the default ConversionStrategy, using the IPv4-mapped
+// form ::ffff:a.b.c.d.
+var IPv4MappedStrategy ConversionStrategy = ipv4MappedStrategy{}
+
+// ipv4CompatStrategy implements the deprecated IPv4-compatible form,
+// ::a.b.c.d, as defined by RFC 4291 section 2.5.5.1. It is still needed when
+// parsing legacy data that uses this form.
+type ipv4CompatStrategy struct{}
+
+func (ipv4CompatStrategy) ToIPv4(v6 *IPv6Address) (*IPv4Address, bool) {
+	if !v6.isIPv4Compatible() {
+		return nil, false
+	}
+	addr, err := NewIPv4AddressFromIP(v6.GetBytes()[12:])
+	if err != nil {
+		return nil, false
+	}
+	return addr, true
+}
+
+func (ipv4CompatStrategy) ToIPv6(v4 *IPv4Address) *IPv6Address {
+	addr, _ := NewIPv6AddressFromIP(append(make([]byte, 12), v4.GetBytes()...))
+	return addr
+}
+
+// IPv4CompatStrategy is the deprecated IPv4-compatible ConversionStrategy,
+// using the form ::a.b.c.d.
+var IPv4CompatStrategy ConversionStrategy = ipv4CompatStrategy{}
+
+// nat64ValidPrefixLengths are the prefix lengths RFC 6052 section 2.2
+// permits for the NAT64 well-known and network-specific prefixes.
+var nat64ValidPrefixLengths = map[BitCount]bool{32: true, 40: true, 48: true, 56: true, 64: true, 96: true}
+
+// nat64Strategy implements RFC 6052 IPv4-embedded IPv6 addresses, under a
+// configurable prefix (by default the well-known prefix 64:ff9b::/96, but a
+// network-specific prefix of length 32, 40, 48, 56, 64, or 96 may be used
+// instead).
+type nat64Strategy struct {
+	prefix    *IPv6Address
+	prefixLen BitCount
+}
+
+// NewNAT64Strategy returns a ConversionStrategy embedding IPv4 addresses
+// under prefix, whose prefix length must be one of 32, 40, 48, 56, 64, or 96
+// per RFC 6052 section 2.2.
+func NewNAT64Strategy(prefix *IPv6Address) (ConversionStrategy, AddressValueException) {
+	prefix = prefix.init()
+	prefixLen := prefix.GetNetworkPrefixLength()
+	if prefixLen == nil || !nat64ValidPrefixLengths[prefixLen.Len()] {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixLength.invalid"}}
+	}
+	return &nat64Strategy{prefix: prefix.ToPrefixBlockLen(prefixLen.Len()), prefixLen: prefixLen.Len()}, nil
+}
+
+// NAT64WellKnownStrategy is the NAT64 ConversionStrategy under RFC 6052's
+// well-known prefix 64:ff9b::/96.
+var NAT64WellKnownStrategy ConversionStrategy = &nat64Strategy{
+	prefix:    mustPrefixBlock("64:ff9b::/96").ToIPv6Address(),
+	prefixLen: 96,
+}
+
+// embeddedByteIndices returns the byte offsets in a 16-byte RFC 6052 address
+// of the 4 embedded IPv4 bytes for the given prefix length, skipping the
+// reserved all-zero byte 8 for prefix lengths other than /96.
+func embeddedByteIndices(prefixLen BitCount) (indices [IPv4ByteCount]int) {
+	pos := int(prefixLen / 8)
+	if prefixLen == 96 {
+		pos = 12
+	}
+	for i := range indices {
+		if pos == 8 {
+			pos++
+		}
+		indices[i] = pos
+		pos++
+	}
+	return
+}
+
+func (s *nat64Strategy) ToIPv4(v6 *IPv6Address) (*IPv4Address, bool) {
+	v6 = v6.init()
+	if !s.prefix.Contains(v6.ToIPAddress()) {
+		return nil, false
+	}
+	bytes := v6.GetBytes()
+	v4Bytes := make([]byte, IPv4ByteCount)
+	for i, idx := range embeddedByteIndices(s.prefixLen) {
+		v4Bytes[i] = bytes[idx]
+	}
+	addr, err := NewIPv4AddressFromIP(v4Bytes)
+	if err != nil {
+		return nil, false
+	}
+	return addr, true
+}
+
+func (s *nat64Strategy) ToIPv6(v4 *IPv4Address) *IPv6Address {
+	bytes := make([]byte, IPv6ByteCount)
+	copy(bytes, s.prefix.GetBytes())
+	v4Bytes := v4.GetBytes()
+	for i, idx := range embeddedByteIndices(s.prefixLen) {
+		bytes[idx] = v4Bytes[i]
+	}
+	addr, _ := NewIPv6AddressFromIP(bytes)
+	return addr
+}
+
+// SetConversionStrategy installs strategy as the IPv4<->IPv6 conversion
+// strategy consulted by ToIPv4 and ToIPv6 on this network. A nil network
+// field defaults to IPv4MappedStrategy.
+func (network *IPv6AddressNetwork) SetConversionStrategy(strategy ConversionStrategy) {
+	network.wkMu.Lock()
+	defer network.wkMu.Unlock()
+	network.conversionStrategy = strategy
+}
+
+// GetConversionStrategy returns this network's IPv4<->IPv6 conversion
+// strategy, defaulting to IPv4MappedStrategy if none was set.
+func (network *IPv6AddressNetwork) GetConversionStrategy() ConversionStrategy {
+	network.wkMu.Lock()
+	defer network.wkMu.Unlock()
+	if network.conversionStrategy == nil {
+		return IPv4MappedStrategy
+	}
+	return network.conversionStrategy
+}
+
+// ToIPv4 converts v6 to an IPv4 address using this network's conversion
+// strategy, reporting false if v6 does not represent an embedded IPv4
+// address under that strategy.
+func (network *IPv6AddressNetwork) ToIPv4(v6 *IPv6Address) (*IPv4Address, bool) {
+	return network.GetConversionStrategy().ToIPv4(v6)
+}
+
+// SetConversionStrategy is SetConversionStrategy for IPv4AddressNetwork.
+func (network *IPv4AddressNetwork) SetConversionStrategy(strategy ConversionStrategy) {
+	network.wkMu.Lock()
+	defer network.wkMu.Unlock()
+	network.conversionStrategy = strategy
+}
+
+// GetConversionStrategy is GetConversionStrategy for IPv4AddressNetwork.
+func (network *IPv4AddressNetwork) GetConversionStrategy() ConversionStrategy {
+	network.wkMu.Lock()
+	defer network.wkMu.Unlock()
+	if network.conversionStrategy == nil {
+		return IPv4MappedStrategy
+	}
+	return network.conversionStrategy
+}
+
+// ToIPv6 converts v4 to an IPv6 address using this network's conversion
+// strategy.
+func (network *IPv4AddressNetwork) ToIPv6(v4 *IPv4Address) *IPv6Address {
+	return network.GetConversionStrategy().ToIPv6(v4)
+}
@@ -0,0 +1,79 @@
+package ipaddr
+
+// Cursor provides bidirectional traversal over an ordered list of addresses,
+// such as the prefix blocks produced by IPSet.Prefixes or the individual
+// addresses within an AddressSection, complementing the forward-only iterators
+// returned by Iterator/PrefixIterator.
+type Cursor struct {
+	items []*IPAddress
+	pos   int // index of the next item HasNext would return; 0 before the start
+}
+
+// NewCursor creates a Cursor over the given addresses, positioned before the
+// first element.
+func NewCursor(items []*IPAddress) *Cursor {
+	return &Cursor{items: items, pos: 0}
+}
+
+// NewSectionCursor creates a Cursor over every individual address contained in
+// section, in order from lowest to highest.
+func NewSectionCursor(section *AddressSection) *Cursor {
+	items := make([]*IPAddress, 0, section.GetCount().Int64())
+	iter := section.Iterator()
+	for iter.HasNext() {
+		sec := iter.Next()
+		addr := &Address{addressInternal{section: sec, cache: &addressCache{}}}
+		items = append(items, addr.ToIPAddress())
+	}
+	return NewCursor(items)
+}
+
+// HasNext returns whether there is a next element to move to.
+func (c *Cursor) HasNext() bool {
+	return c.pos < len(c.items)
+}
+
+// HasPrevious returns whether there is a previous element to move to.
+func (c *Cursor) HasPrevious() bool {
+	return c.pos > 0
+}
+
+// Next advances the cursor and returns the element it moved to, or nil if
+// HasNext is false.
+func (c *Cursor) Next() *IPAddress {
+	if !c.HasNext() {
+		return nil
+	}
+	item := c.items[c.pos]
+	c.pos++
+	return item
+}
+
+// Previous moves the cursor backward and returns the element it moved to, or
+// nil if HasPrevious is false.
+func (c *Cursor) Previous() *IPAddress {
+	if !c.HasPrevious() {
+		return nil
+	}
+	c.pos--
+	return c.items[c.pos]
+}
+
+// Peek returns the element that Next would return, without advancing the
+// cursor, or nil if there is none.
+func (c *Cursor) Peek() *IPAddress {
+	if !c.HasNext() {
+		return nil
+	}
+	return c.items[c.pos]
+}
+
+// Reset moves the cursor back to the position before the first element.
+func (c *Cursor) Reset() {
+	c.pos = 0
+}
+
+// Len returns the total number of elements in the cursor.
+func (c *Cursor) Len() int {
+	return len(c.items)
+}
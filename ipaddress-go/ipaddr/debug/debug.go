@@ -0,0 +1,250 @@
+// Package debug reads module-specific runtime toggles from the IPADDRDEBUG
+// environment variable, modeled on the Go standard library's
+// internal/godebug facility.  IPADDRDEBUG is a comma-separated list of
+// key=value settings, eg "IPADDRDEBUG=ipv4octal=1,strictarpa=1".
+package debug
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	once     sync.Once
+	mu       sync.RWMutex
+	settings map[string]string
+)
+
+func parse() {
+	settings = make(map[string]string)
+	for _, kv := range strings.Split(os.Getenv("IPADDRDEBUG"), ",") {
+		if kv == "" {
+			continue
+		}
+		if eq := strings.IndexByte(kv, '='); eq >= 0 {
+			settings[kv[:eq]] = kv[eq+1:]
+		} else {
+			settings[kv] = "1"
+		}
+	}
+}
+
+// Get returns the raw string value of the named IPADDRDEBUG setting, and
+// whether it was present.
+func Get(name string) (string, bool) {
+	once.Do(parse)
+	mu.RLock()
+	defer mu.RUnlock()
+	v, ok := settings[name]
+	return v, ok
+}
+
+// SetDebug overrides the named IPADDRDEBUG setting for the remainder of the
+// process, regardless of what (if anything) was present in the environment
+// variable at init. It exists so tests can exercise both sides of a knob,
+// eg the cache-enabled and cache-disabled branches of IsSinglePrefixBlock,
+// without forking a subprocess with a different IPADDRDEBUG.
+func SetDebug(name, val string) {
+	once.Do(parse)
+	mu.Lock()
+	defer mu.Unlock()
+	settings[name] = val
+}
+
+// Bool returns the named setting interpreted as a boolean, defaulting to
+// def if the setting is absent or not a valid boolean.
+func Bool(name string, def bool) bool {
+	v, ok := Get(name)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+// Knob names recognized by this module's parser and formatter. Each is read
+// lazily via Bool/Get so a test can still override os.Setenv before first use.
+const (
+	// IPv4Octal, when true, parses IPv4 octets with a leading "0" as octal
+	// rather than rejecting them or reading them as decimal.
+	IPv4Octal = "ipv4octal"
+
+	// IPv6ZoneUnicode, when true, allows non-ASCII characters in an IPv6
+	// zone identifier rather than restricting it to RFC 6874's token
+	// grammar.
+	IPv6ZoneUnicode = "ipv6zoneunicode"
+
+	// ZeroHostCompress, when false, disables formatting a zero-host prefix
+	// block's host portion with "::"/"0" compression shortcuts.
+	ZeroHostCompress = "zerohostcompress"
+
+	// StrictARPA, when true, rejects reverse-DNS PTR names that are not
+	// fully expanded to their nibble/octet boundary.
+	StrictARPA = "strictarpa"
+
+	// StrictPrefix, when true, rejects a parsed address carrying a network
+	// prefix length whose host bits are not all zero, ie one that is not
+	// itself a prefix block.
+	StrictPrefix = "strictprefix"
+
+	// IPv4Mapped controls which embedded-IPv4 IPv6 forms count as
+	// convertible to IPv4: "mapped" (the default) recognizes only
+	// IPv4-mapped addresses (::ffff:a.b.c.d); "strict" additionally
+	// excludes forms IsIPv4Convertible would otherwise accept; any other
+	// value, eg "compat", additionally recognizes the deprecated
+	// IPv4-compatible form (::a.b.c.d).
+	IPv4Mapped = "ipv4mapped"
+
+	// ConvertV4V6, when true, makes FromNetIPAddr eagerly unmap an
+	// IPv4-in-IPv6 (Is4In6) netip.Addr to an IPv4Address rather than
+	// preserving its 16-byte mapped form. Defaults to false, preserving
+	// this module's existing documented behavior.
+	ConvertV4V6 = "convertv4v6"
+
+	// Cache, when false, disables the atomic-pointer valueCache writes
+	// consulted by IsSinglePrefixBlock, GetPrefixLenForSingleBlock, and
+	// GetMinPrefixLenForBlock, so every call recomputes from scratch.
+	// Useful for isolating cache-hit benefit in benchmarks and for
+	// deterministic allocation tests.
+	Cache = "cache"
+
+	// StrictParse, when true, rejects historically-tolerated inputs that
+	// the default parser accepts for backward compatibility.
+	StrictParse = "strictparse"
+
+	// ZeroPrefix selects zero-length-prefix semantics: "strict" treats a
+	// /0 prefix the same as any other prefix length; "lenient" (the
+	// default) applies this module's existing historical leniencies
+	// around /0.
+	ZeroPrefix = "zeroprefix"
+
+	// Fmt selects the default Format behavior: "go" (the default) or
+	// "java", matching the formatting conventions of this module's Java
+	// counterpart.
+	Fmt = "fmt"
+
+	// RangeSeparator selects the character written between the lower and
+	// upper bounds of a ranged segment: "-" (the default, eg "1-2.3.4.5")
+	// or "~" (eg "1~2.3.4.5").
+	RangeSeparator = "rangeseparator"
+
+	// LeadingZeros selects how a formatted segment's leading zeros are
+	// handled on output: "lenient" (the default) reproduces this module's
+	// existing historical behavior, while "strict" rejects formatting any
+	// division whose configured leading zeros would be ambiguous with
+	// octal notation.
+	LeadingZeros = "leadingzeros"
+)
+
+// IsIPv4OctalEnabled reports whether leading-zero IPv4 octets should parse
+// as octal, per the ipv4octal knob.
+func IsIPv4OctalEnabled() bool {
+	return Bool(IPv4Octal, false)
+}
+
+// IsIPv6ZoneUnicodeEnabled reports whether non-ASCII IPv6 zone identifiers
+// should be accepted, per the ipv6zoneunicode knob.
+func IsIPv6ZoneUnicodeEnabled() bool {
+	return Bool(IPv6ZoneUnicode, false)
+}
+
+// IsZeroHostCompressEnabled reports whether zero-host compression shortcuts
+// should be used when formatting, per the zerohostcompress knob.
+func IsZeroHostCompressEnabled() bool {
+	return Bool(ZeroHostCompress, true)
+}
+
+// IsStrictARPAEnabled reports whether reverse-DNS PTR names must be fully
+// expanded to their nibble/octet boundary, per the strictarpa knob.
+func IsStrictARPAEnabled() bool {
+	return Bool(StrictARPA, false)
+}
+
+// IsStrictPrefixEnabled reports whether a parsed address with non-zero host
+// bits under its network prefix length should be rejected, per the
+// strictprefix knob.
+func IsStrictPrefixEnabled() bool {
+	return Bool(StrictPrefix, false)
+}
+
+// IPv4MappedMode returns the raw value of the ipv4mapped knob, defaulting to
+// "mapped" if unset.
+func IPv4MappedMode() string {
+	v, ok := Get(IPv4Mapped)
+	if !ok {
+		return "mapped"
+	}
+	return v
+}
+
+// IsConvertV4V6Enabled reports whether FromNetIPAddr should eagerly unmap an
+// IPv4-in-IPv6 netip.Addr to an IPv4Address, per the convertv4v6 knob.
+func IsConvertV4V6Enabled() bool {
+	return Bool(ConvertV4V6, false)
+}
+
+// IsCacheEnabled reports whether the atomic-pointer valueCache writes
+// consulted by IsSinglePrefixBlock and related methods should be used, per
+// the cache knob.
+func IsCacheEnabled() bool {
+	return Bool(Cache, true)
+}
+
+// IsStrictParseEnabled reports whether historically-tolerated inputs should
+// be rejected, per the strictparse knob.
+func IsStrictParseEnabled() bool {
+	return Bool(StrictParse, false)
+}
+
+// IsZeroPrefixStrict reports whether a /0 prefix should be treated the same
+// as any other prefix length ("strict"), as opposed to this module's
+// historical lenient /0 handling, per the zeroprefix knob.
+func IsZeroPrefixStrict() bool {
+	v, ok := Get(ZeroPrefix)
+	return ok && v == "strict"
+}
+
+// FormatMode returns the raw value of the fmt knob, defaulting to "go".
+func FormatMode() string {
+	v, ok := Get(Fmt)
+	if !ok || (v != "java" && v != "go") {
+		return "go"
+	}
+	return v
+}
+
+// RangeSeparatorChar returns the rune formatted between a ranged segment's
+// lower and upper bounds, per the rangeseparator knob, defaulting to '-'.
+func RangeSeparatorChar() rune {
+	v, ok := Get(RangeSeparator)
+	if !ok || v != "~" {
+		return '-'
+	}
+	return '~'
+}
+
+// IsLeadingZerosStrict reports whether formatting a division with ambiguous
+// leading zeros should be rejected rather than tolerated, per the
+// leadingzeros knob.
+func IsLeadingZerosStrict() bool {
+	v, ok := Get(LeadingZeros)
+	return ok && v == "strict"
+}
+
+// Snapshot returns every IPADDRDEBUG setting read at init, for introspection
+// by ipaddr.Debug(). The returned map is a copy; mutating it has no effect.
+func Snapshot() map[string]string {
+	once.Do(parse)
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make(map[string]string, len(settings))
+	for k, v := range settings {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,27 @@
+package ipaddr
+
+import "github.com/seancfoley/ipaddress/ipaddress-go/ipaddr/debug"
+
+// Debug returns every IPADDRDEBUG setting read at process start, keyed by
+// knob name, for introspection by operators and diagnostics tooling. The
+// returned map is a copy; mutating it has no effect on parsing or
+// conversion behavior.
+func Debug() map[string]string {
+	return debug.Snapshot()
+}
+
+// SetDefault overrides the named runtime knob (one of the debug package's
+// constants, eg debug.RangeSeparator or debug.LeadingZeros) for the
+// remainder of the process, letting a caller flip parsing and formatting
+// behavior globally without re-threading an options struct through every
+// call site. It is a thin wrapper over debug.SetDebug: this module reads
+// its runtime knobs from the single IPADDRDEBUG environment variable via
+// the ipaddr/debug package, so SetDefault updates that same knob set rather
+// than introducing a second, competing toggle system with its own env var.
+// Like debug.SetDebug, the underlying settings map is guarded by a
+// sync.RWMutex seeded once from the environment, so concurrent readers in
+// getCachedBytes and elsewhere never observe a partially-initialized knob
+// set.
+func SetDefault(name, value string) {
+	debug.SetDebug(name, value)
+}
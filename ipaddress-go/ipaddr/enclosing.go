@@ -0,0 +1,105 @@
+package ipaddr
+
+// EnclosingBlocks returns the chain of successively larger CIDR prefix
+// blocks enclosing this address or subnet, starting with the smallest
+// enclosing block strictly larger than this one and ending with the
+// all-addresses block ("0.0.0.0/0" or "::/0").  This is the same
+// one-block-at-a-time enlargement used to build the address trees in the
+// trie walk tests, promoted here as a public API.
+func (addr *IPAddress) EnclosingBlocks() []*IPAddress {
+	addr = addr.init()
+	var result []*IPAddress
+	current := addr
+	if addr.GetNetworkPrefixLength() == nil {
+		// a full-length prefix is the same size as addr, so it is not listed
+		current = enlargeBlock(addr)
+	}
+	for {
+		next := enlargeBlock(current)
+		if next == nil {
+			break
+		}
+		result = append(result, next)
+		if next.GetNetworkPrefixLength() != nil && next.GetNetworkPrefixLength().Len() == 0 {
+			break
+		}
+		current = next
+	}
+	return result
+}
+
+// EnlargeSubnet returns the smallest prefix block strictly containing this
+// address or subnet, enlarging one segment's worth of bits at a time.  If
+// this address is already the all-addresses block ("0.0.0.0/0" or "::/0"),
+// it is returned unchanged.  This is the single-step building block behind
+// EnclosingBlocks.
+func (addr *IPAddress) EnlargeSubnet() *IPAddress {
+	addr = addr.init()
+	enlarged := enlargeBlock(addr)
+	if enlarged == nil {
+		return addr
+	}
+	return enlarged
+}
+
+// ToEnclosingPrefixBlock is an alias for EnlargeSubnet, named to match the
+// ToXxx convention used by this package's other prefix-block conversions.
+func (addr *IPAddress) ToEnclosingPrefixBlock() *IPAddress {
+	return addr.EnlargeSubnet()
+}
+
+// EnlargeSubnet parses this address string and returns the smallest prefix
+// block strictly containing the result, per IPAddress.EnlargeSubnet.
+func (str *IPAddressString) EnlargeSubnet() (*IPAddress, AddressStringException) {
+	addr, err := str.ToAddress()
+	if err != nil {
+		return nil, err
+	}
+	return addr.EnlargeSubnet(), nil
+}
+
+// enlargeBlock returns the smallest prefix block strictly larger than addr,
+// or nil if addr is already the all-addresses block.
+func enlargeBlock(addr *IPAddress) *IPAddress {
+	prefix := addr.GetNetworkPrefixLength()
+	if prefix == nil {
+		return addr.SetPrefixLen(addr.GetBitCount())
+	}
+	prefLen := prefix.Len()
+	if prefLen == 0 {
+		return nil
+	}
+	adjustment := ((prefLen - 1) % addr.GetBitsPerSegment()) + 1
+	enlarged, _ := addr.SetPrefixLenZeroed(prefLen - adjustment)
+	if enlarged.GetLower().IsZeroHost() {
+		enlarged = enlarged.ToPrefixBlock()
+	}
+	return enlarged
+}
+
+// SmallestCommonPrefixBlock returns the smallest CIDR prefix block that
+// contains this address and every address in others.
+func (addr *IPAddress) SmallestCommonPrefixBlock(others ...*IPAddress) *IPAddress {
+	current := addr.init()
+	for _, other := range others {
+		if other == nil {
+			continue
+		}
+		current = smallestCommonPrefixBlockOf(current, other.init())
+	}
+	return current
+}
+
+func smallestCommonPrefixBlockOf(a, b *IPAddress) *IPAddress {
+	if a.Contains(b) {
+		return a
+	}
+	lower := a.GetLower()
+	for prefLen := a.GetBitCount(); prefLen >= 0; prefLen-- {
+		block := lower.ToPrefixBlockLen(prefLen)
+		if block.Contains(a) && block.Contains(b) {
+			return block
+		}
+	}
+	return a
+}
@@ -0,0 +1,204 @@
+package ipaddr
+
+// This file adds EUI-64 conversion and IPv6 modified-EUI-64 interface
+// identifier derivation to MACAddressSection, per RFC 4291 section 2.5.1:
+// a 48-bit MAC address becomes a 64-bit interface identifier by inserting
+// 0xFF, 0xFE between its OUI and NIC halves and flipping the
+// universal/local bit (bit 0x02) of the first octet.
+
+// ToEUI64 converts between the 48-bit (EUI-48/MAC) and 64-bit (EUI-64) forms
+// of section. If asMAC is true, section must already be in 8-segment EUI-64
+// form with 0xFF, 0xFE as its middle two segments (segments 3 and 4); these
+// are removed to produce the 6-segment MAC form. If asMAC is false, section
+// must be in 6-segment MAC form; 0xFF, 0xFE is inserted between segments 2
+// and 3 to produce the 8-segment EUI-64 form. A section already in the
+// requested form is returned unchanged. Ranged segments are preserved
+// segment-for-segment; only the exact-match check on the inserted bytes
+// themselves requires a single value.
+func (section *MACAddressSection) ToEUI64(asMAC bool) (*MACAddressSection, IncompatibleAddressError) {
+	segCount := section.GetSegmentCount()
+	if asMAC {
+		if segCount == MediaAccessControlSegmentCount {
+			return section, nil
+		}
+		if segCount != ExtendedUniqueIdentifier64SegmentCount {
+			return nil, &incompatibleAddressError{addressError: addressError{key: "ipaddress.error.invalid.mac.address"}}
+		}
+		mid1 := section.GetSegment(3)
+		mid2 := section.GetSegment(4)
+		if mid1.GetSegmentValue() != 0xff || mid1.GetUpperSegmentValue() != 0xff ||
+			mid2.GetSegmentValue() != 0xfe || mid2.GetUpperSegmentValue() != 0xfe {
+			return nil, &incompatibleAddressError{addressError: addressError{key: "ipaddress.error.invalid.mac.address"}}
+		}
+		vals := func(i int) SegInt {
+			if i < 3 {
+				return section.GetSegment(i).GetSegmentValue()
+			}
+			return section.GetSegment(i + 2).GetSegmentValue()
+		}
+		upperVals := func(i int) SegInt {
+			if i < 3 {
+				return section.GetSegment(i).GetUpperSegmentValue()
+			}
+			return section.GetSegment(i + 2).GetUpperSegmentValue()
+		}
+		return NewMACSectionFromRange(vals, upperVals, MediaAccessControlSegmentCount), nil
+	}
+	if segCount == ExtendedUniqueIdentifier64SegmentCount {
+		return section, nil
+	}
+	if segCount != MediaAccessControlSegmentCount {
+		return nil, &incompatibleAddressError{addressError: addressError{key: "ipaddress.error.invalid.mac.address"}}
+	}
+	vals := func(i int) SegInt {
+		switch {
+		case i < 3:
+			return section.GetSegment(i).GetSegmentValue()
+		case i == 3:
+			return 0xff
+		case i == 4:
+			return 0xfe
+		default:
+			return section.GetSegment(i - 2).GetSegmentValue()
+		}
+	}
+	upperVals := func(i int) SegInt {
+		switch {
+		case i < 3:
+			return section.GetSegment(i).GetUpperSegmentValue()
+		case i == 3:
+			return 0xff
+		case i == 4:
+			return 0xfe
+		default:
+			return section.GetSegment(i - 2).GetUpperSegmentValue()
+		}
+	}
+	return NewMACSectionFromRange(vals, upperVals, ExtendedUniqueIdentifier64SegmentCount), nil
+}
+
+// flipUniversalLocalBit returns section with the universal/local bit
+// (0x02) of its first segment flipped, as the last step of deriving a
+// modified EUI-64 identifier. It rejects section if that first segment is a
+// range whose bound values disagree on the bit being flipped, since then a
+// uniform flip would not describe the same set of addresses as a single
+// contiguous range.
+func (section *MACAddressSection) flipUniversalLocalBit() (*MACAddressSection, IncompatibleAddressError) {
+	first := section.GetSegment(0)
+	lowerVal := first.GetSegmentValue()
+	upperVal := first.GetUpperSegmentValue()
+	if lowerVal&0x02 != upperVal&0x02 {
+		return nil, &incompatibleAddressError{addressError: addressError{key: "ipaddress.error.invalid.mac.address.range"}}
+	}
+	segCount := section.GetSegmentCount()
+	vals := func(i int) SegInt {
+		if i == 0 {
+			return lowerVal ^ 0x02
+		}
+		return section.GetSegment(i).GetSegmentValue()
+	}
+	upperVals := func(i int) SegInt {
+		if i == 0 {
+			return upperVal ^ 0x02
+		}
+		return section.GetSegment(i).GetUpperSegmentValue()
+	}
+	return NewMACSectionFromRange(vals, upperVals, segCount), nil
+}
+
+// interfaceIDSegmentVals returns SegmentValueProviders yielding the four
+// 16-bit IPv6 segment values (and their upper bounds) packed from the eight
+// 8-bit segments of an EUI-64-form MACAddressSection, most significant byte
+// first within each pair.
+func interfaceIDSegmentVals(eui64 *MACAddressSection) (vals, upperVals SegmentValueProvider) {
+	vals = func(i int) SegInt {
+		hi := eui64.GetSegment(i * 2).GetSegmentValue()
+		lo := eui64.GetSegment(i*2 + 1).GetSegmentValue()
+		return hi<<8 | lo
+	}
+	upperVals = func(i int) SegInt {
+		hi := eui64.GetSegment(i * 2).GetUpperSegmentValue()
+		lo := eui64.GetSegment(i*2 + 1).GetUpperSegmentValue()
+		return hi<<8 | lo
+	}
+	return
+}
+
+// ToInterfaceIdentifier derives the 64-bit modified EUI-64 interface
+// identifier IPv6AddressSection for section, per RFC 4291 section 2.5.1:
+// section is first widened to EUI-64 form via ToEUI64(false) if it is a
+// 6-segment MAC section, then its first octet's universal/local bit is
+// flipped. section's own prefix length, if any, carries over unchanged,
+// since both it and the result are measured in bits from the same start.
+func (section *MACAddressSection) ToInterfaceIdentifier() (*IPv6AddressSection, IncompatibleAddressError) {
+	eui64, err := section.ToEUI64(false)
+	if err != nil {
+		return nil, err
+	}
+	flipped, err := eui64.flipUniversalLocalBit()
+	if err != nil {
+		return nil, err
+	}
+	idVals, idUpperVals := interfaceIDSegmentVals(flipped)
+	vals := func(i int) SegInt {
+		if i < 4 {
+			return 0
+		}
+		return idVals(i - 4)
+	}
+	upperVals := func(i int) SegInt {
+		if i < 4 {
+			return 0
+		}
+		return idUpperVals(i - 4)
+	}
+	var prefixLength PrefixLen
+	if idPrefLen := section.GetPrefixLen(); idPrefLen != nil {
+		prefixLength = ToPrefixLen(IPv6BitCount/2 + idPrefLen.Len())
+	}
+	addr, _ := NewIPv6AddressFromPrefixedRange(vals, upperVals, prefixLength)
+	return addr.GetTrailingSection(4), nil
+}
+
+// linkLocalPrefixVals supplies the fixed fe80::/64 network segments (the
+// first four IPv6 segments) for ToLinkLocalIPv6.
+func linkLocalPrefixVals(i int) SegInt {
+	if i == 0 {
+		return 0xfe80
+	}
+	return 0
+}
+
+// ToLinkLocalIPv6 derives section's IPv6 link-local address, fe80::/64
+// together with section's modified EUI-64 interface identifier, per
+// ToInterfaceIdentifier. If section carries a prefix length, the result's
+// prefix length is 64 plus that length, since the interface identifier
+// occupies the address's low 64 bits.
+func (section *MACAddressSection) ToLinkLocalIPv6() (*IPv6Address, IncompatibleAddressError) {
+	eui64, err := section.ToEUI64(false)
+	if err != nil {
+		return nil, err
+	}
+	flipped, err := eui64.flipUniversalLocalBit()
+	if err != nil {
+		return nil, err
+	}
+	idVals, idUpperVals := interfaceIDSegmentVals(flipped)
+	vals := func(i int) SegInt {
+		if i < 4 {
+			return linkLocalPrefixVals(i)
+		}
+		return idVals(i - 4)
+	}
+	upperVals := func(i int) SegInt {
+		if i < 4 {
+			return linkLocalPrefixVals(i)
+		}
+		return idUpperVals(i - 4)
+	}
+	var prefixLength PrefixLen
+	if idPrefLen := section.GetPrefixLen(); idPrefLen != nil {
+		prefixLength = ToPrefixLen(IPv6BitCount/2 + idPrefLen.Len())
+	}
+	return NewIPv6AddressFromPrefixedRange(vals, upperVals, prefixLength)
+}
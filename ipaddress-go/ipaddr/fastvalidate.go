@@ -0,0 +1,188 @@
+package ipaddr
+
+import "strings"
+
+// IsValidIPv4 reports whether s is a syntactically valid dotted-decimal
+// IPv4 address, optionally followed by a "/" prefix length, without
+// constructing an IPAddressString or IPAddress. It is a hand-rolled,
+// single-pass scan intended for hot paths — such as per-line log filtering
+// — that only need a yes/no answer; NewIPAddressString(s).ToAddress()
+// remains the entrypoint for callers that need the parsed address, a
+// detailed parse error, or one of the legacy inet_aton octal/hex/collapsed
+// forms that this fast path intentionally does not recognize.
+func IsValidIPv4(s string) bool {
+	addrPart, ok := splitPrefixLen(s, IPv4BitCount)
+	if !ok {
+		return false
+	}
+	end, ok := scanIPv4(addrPart)
+	return ok && end == len(addrPart)
+}
+
+// IsValidIPv6 reports whether s is a syntactically valid IPv6 address in
+// standard colon-hex notation — including "::" compression, a trailing
+// embedded IPv4 suffix, and a "%"-separated zone ID — optionally followed
+// by a "/" prefix length, without constructing an IPAddressString or
+// IPAddress.
+func IsValidIPv6(s string) bool {
+	addrZonePart, ok := splitPrefixLen(s, IPv6BitCount)
+	if !ok {
+		return false
+	}
+	if idx := strings.IndexByte(addrZonePart, IPv6ZoneSeparator); idx >= 0 {
+		if !validZone(addrZonePart[idx+1:]) {
+			return false
+		}
+		addrZonePart = addrZonePart[:idx]
+	}
+	return validIPv6Address(addrZonePart)
+}
+
+// IsValidIP reports whether s is valid per IsValidIPv4 or IsValidIPv6.
+func IsValidIP(s string) bool {
+	return IsValidIPv4(s) || IsValidIPv6(s)
+}
+
+// scanIPv4 scans a dotted-decimal IPv4 address, with no leading zeros
+// beyond a single "0" octet, starting at the beginning of s, returning the
+// index just past the last octet and whether the scan succeeded. It does
+// not itself require the scan to reach the end of s, since it is reused to
+// validate an embedded IPv4 suffix within an IPv6 address.
+func scanIPv4(s string) (end int, ok bool) {
+	i, octets := 0, 0
+	for {
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		digits := i - start
+		if digits == 0 || digits > 3 || (digits > 1 && s[start] == '0') {
+			return 0, false
+		}
+		val := 0
+		for j := start; j < i; j++ {
+			val = val*10 + int(s[j]-'0')
+		}
+		if val > 255 {
+			return 0, false
+		}
+		octets++
+		if i < len(s) && s[i] == '.' {
+			if octets == 4 {
+				return 0, false
+			}
+			i++
+			continue
+		}
+		break
+	}
+	if octets != 4 {
+		return 0, false
+	}
+	return i, true
+}
+
+// splitPrefixLen returns the portion of s before an optional "/" prefix
+// length, having validated that the prefix length, if present, is a plain
+// decimal integer no greater than maxBits.
+func splitPrefixLen(s string, maxBits int) (addrPart string, ok bool) {
+	slash := strings.IndexByte(s, '/')
+	if slash < 0 {
+		return s, true
+	}
+	plen := s[slash+1:]
+	if len(plen) == 0 || (len(plen) > 1 && plen[0] == '0') {
+		return "", false
+	}
+	val := 0
+	for i := 0; i < len(plen); i++ {
+		if plen[i] < '0' || plen[i] > '9' {
+			return "", false
+		}
+		val = val*10 + int(plen[i]-'0')
+		if val > maxBits {
+			return "", false
+		}
+	}
+	return s[:slash], true
+}
+
+// validZone reports whether zone is a zone identifier containing
+// none of the characters that terminate one (a prefix-length slash or
+// another zone separator); an empty zone, as in "::1%", is also accepted
+// per this module's lenient zone parsing.
+func validZone(zone string) bool {
+	return strings.IndexByte(zone, '/') < 0 && strings.IndexByte(zone, IPv6ZoneSeparator) < 0
+}
+
+// validIPv6Address validates s as an IPv6 address body (no zone or prefix
+// length), allowing at most one "::" run and, in its final colon-hex field,
+// an embedded IPv4 address in place of the last two 16-bit groups.
+func validIPv6Address(s string) bool {
+	if s == "::" {
+		return true
+	}
+	doubleColon := strings.Index(s, "::")
+	if doubleColon < 0 {
+		groups, ok := countIPv6Groups(s)
+		return ok && groups == 8
+	}
+	if strings.Index(s[doubleColon+2:], "::") >= 0 {
+		return false
+	}
+	leftGroups, leftOK := countIPv6Groups(s[:doubleColon])
+	rightGroups, rightOK := countIPv6Groups(s[doubleColon+2:])
+	if !leftOK || !rightOK {
+		return false
+	}
+	return leftGroups+rightGroups <= 7
+}
+
+// countIPv6Groups counts the colon-hex groups in s, where s is either a
+// full address body or one side of a "::" split, treating an embedded IPv4
+// suffix in the final field as two groups. An empty s (as on either side of
+// a leading/trailing "::") counts zero groups.
+func countIPv6Groups(s string) (count int, ok bool) {
+	if s == "" {
+		return 0, true
+	}
+	i := 0
+	for {
+		start := i
+		for i < len(s) && s[i] != ':' {
+			i++
+		}
+		field := s[start:i]
+		isLast := i == len(s)
+		if field == "" {
+			return 0, false
+		}
+		if strings.IndexByte(field, '.') >= 0 {
+			if !isLast {
+				return 0, false
+			}
+			if end, ok := scanIPv4(field); !ok || end != len(field) {
+				return 0, false
+			}
+			count += 2
+		} else {
+			if len(field) > 4 {
+				return 0, false
+			}
+			for j := 0; j < len(field); j++ {
+				if !isHexDigit(field[j]) {
+					return 0, false
+				}
+			}
+			count++
+		}
+		if isLast {
+			return count, true
+		}
+		i++ // skip ':'
+	}
+}
+
+func isHexDigit(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'f' || b >= 'A' && b <= 'F'
+}
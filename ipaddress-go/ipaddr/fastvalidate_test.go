@@ -0,0 +1,48 @@
+package ipaddr
+
+import "testing"
+
+// FuzzIsValidIP checks that IsValidIPv4/IsValidIPv6 never report an address
+// as valid unless NewIPAddressString agrees and parses it as the matching
+// IP version; the fast path is a sound pre-filter, not necessarily complete
+// for every exotic form (eg legacy inet_aton octal/hex/collapsed octets)
+// the full parser accepts.
+func FuzzIsValidIP(f *testing.F) {
+	seeds := []string{
+		"1.2.3.4",
+		"1.2.3.4/24",
+		"0.0.0.0",
+		"255.255.255.255",
+		"01.2.3.4",
+		"::",
+		"::1",
+		"2001:db8::1",
+		"2001:db8::1/64",
+		"::ffff:1.2.3.4",
+		"fe80::1%eth0",
+		"fe80::1%eth0/64",
+		"::1%",
+		"1::2::3",
+		"",
+		"not an address",
+		"1.2.3.4.5",
+		"12345::",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		if IsValidIPv4(s) {
+			addr, err := NewIPAddressString(s).ToAddress()
+			if err != nil || addr == nil || !addr.IsIPv4() {
+				t.Fatalf("IsValidIPv4(%q) = true but full parser disagreed: %v", s, err)
+			}
+		}
+		if IsValidIPv6(s) {
+			addr, err := NewIPAddressString(s).ToAddress()
+			if err != nil || addr == nil || !addr.IsIPv6() {
+				t.Fatalf("IsValidIPv6(%q) = true but full parser disagreed: %v", s, err)
+			}
+		}
+	})
+}
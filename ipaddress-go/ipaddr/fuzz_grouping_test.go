@@ -0,0 +1,126 @@
+package ipaddr
+
+import (
+	"testing"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr/debug"
+)
+
+// addressSectionOf returns addr's underlying AddressSection, dispatching on
+// IP version, or nil if addr is nil or not a recognized version.
+func addressSectionOf(addr *IPAddress) *AddressSection {
+	if v4 := addr.ToIPv4Address(); v4 != nil {
+		return v4.GetSection().ToAddressSection()
+	} else if v6 := addr.ToIPv6Address(); v6 != nil {
+		return v6.GetSection().ToAddressSection()
+	}
+	return nil
+}
+
+// FuzzGroupingInvariants fuzzes the prefix-block and cache-consulting
+// methods of AddressSection (backed by addressDivisionGroupingInternal),
+// modeled on the net/netip fuzz suite, checking invariants that must hold
+// regardless of input: a single prefix block contains its own assigned
+// prefix; a discovered single-block prefix length, reapplied, yields a
+// single prefix block again; the atomic valueCache and the uncached
+// recomputation path (toggled via ipaddr/debug's cache knob) agree; and the
+// lower/upper values round-trip through reconstruction from raw bytes.
+func FuzzGroupingInvariants(f *testing.F) {
+	seeds := []string{
+		"1.2.3.4",
+		"1.2.3.4/24",
+		"0.0.0.0/0",
+		"255.255.255.255/32",
+		"10.0.0.0/8",
+		"::",
+		"::1",
+		"2001:db8::/32",
+		"2001:db8::1/128",
+		"fe80::1%eth0/64",
+		"::ffff:1.2.3.4/112",
+	}
+	for _, s := range seeds {
+		f.Add(s, byte(0))
+	}
+	f.Fuzz(func(t *testing.T, s string, prefixByte byte) {
+		addr, err := NewIPAddressString(s).ToAddress()
+		if err != nil || addr == nil {
+			return
+		}
+		section := addressSectionOf(addr)
+		if section == nil {
+			return
+		}
+		bitCount := section.GetBitCount()
+		prefixLen := BitCount(prefixByte) % (bitCount + 1)
+
+		// IsSinglePrefixBlock() => ContainsPrefixBlock(*own assigned prefix)
+		if section.IsSinglePrefixBlock() {
+			ownPrefLen := section.GetNetworkPrefixLength()
+			if ownPrefLen != nil && !section.ContainsPrefixBlock(ownPrefLen.Len()) {
+				t.Fatalf("%q: IsSinglePrefixBlock true but does not contain its own prefix block /%d", s, ownPrefLen.Len())
+			}
+		}
+
+		// GetPrefixLenForSingleBlock() != nil => re-prefixing to it yields a
+		// single prefix block
+		if pl := section.GetPrefixLenForSingleBlock(); pl != nil {
+			reprefixed := addr.ToPrefixBlockLen(pl.Len())
+			if reprefSection := addressSectionOf(reprefixed); reprefSection != nil && !reprefSection.IsSinglePrefixBlock() {
+				t.Fatalf("%q: GetPrefixLenForSingleBlock returned /%d but re-prefixing did not yield a single prefix block", s, pl.Len())
+			}
+		}
+
+		// the cached and uncached code paths must agree
+		debug.SetDebug(debug.Cache, "true")
+		cachedSingle := section.IsSinglePrefixBlock()
+		cachedMin := section.GetMinPrefixLenForBlock()
+		cachedForBlock := section.GetPrefixLenForSingleBlock()
+		debug.SetDebug(debug.Cache, "false")
+		uncachedSingle := section.IsSinglePrefixBlock()
+		uncachedMin := section.GetMinPrefixLenForBlock()
+		uncachedForBlock := section.GetPrefixLenForSingleBlock()
+		debug.SetDebug(debug.Cache, "true")
+		if cachedSingle != uncachedSingle {
+			t.Fatalf("%q: IsSinglePrefixBlock cached=%v uncached=%v", s, cachedSingle, uncachedSingle)
+		}
+		if cachedMin != uncachedMin {
+			t.Fatalf("%q: GetMinPrefixLenForBlock cached=%d uncached=%d", s, cachedMin, uncachedMin)
+		}
+		if cachedForBlock.Compare(uncachedForBlock) != 0 {
+			t.Fatalf("%q: GetPrefixLenForSingleBlock cached=%v uncached=%v", s, cachedForBlock, uncachedForBlock)
+		}
+
+		// ContainsPrefixBlock must not panic for any prefix length in range
+		_ = section.ContainsPrefixBlock(prefixLen)
+		_ = section.ContainsSinglePrefixBlock(prefixLen)
+
+		// GetValue/GetUpperValue bytes reconstruct the same lower/upper
+		// addresses
+		byteCount := bitCount / 8
+		lowerBytes := section.GetValue().FillBytes(make([]byte, byteCount))
+		upperBytes := section.GetUpperValue().FillBytes(make([]byte, byteCount))
+		var lower, upper *IPAddress
+		if v4 := addr.ToIPv4Address(); v4 != nil {
+			lowerAddr, lerr := NewIPv4AddressFromIP(lowerBytes)
+			upperAddr, uerr := NewIPv4AddressFromIP(upperBytes)
+			if lerr != nil || uerr != nil {
+				t.Fatalf("%q: failed to reconstruct IPv4 from bytes: %v %v", s, lerr, uerr)
+			}
+			lower, upper = lowerAddr.ToIPAddress(), upperAddr.ToIPAddress()
+		} else if v6 := addr.ToIPv6Address(); v6 != nil {
+			lowerAddr, lerr := NewIPv6AddressFromIP(lowerBytes)
+			upperAddr, uerr := NewIPv6AddressFromIP(upperBytes)
+			if lerr != nil || uerr != nil {
+				t.Fatalf("%q: failed to reconstruct IPv6 from bytes: %v %v", s, lerr, uerr)
+			}
+			lower, upper = lowerAddr.ToIPAddress(), upperAddr.ToIPAddress()
+		}
+		if lower != nil && lower.GetLower().String() != addr.GetLower().String() {
+			t.Fatalf("%q: reconstructed lower %q != %q", s, lower.GetLower().String(), addr.GetLower().String())
+		}
+		if upper != nil && upper.GetLower().String() != addr.GetUpper().String() {
+			t.Fatalf("%q: reconstructed upper %q != %q", s, upper.GetLower().String(), addr.GetUpper().String())
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package ipaddr
+
+import "testing"
+
+// FuzzIPv6 fuzzes the full IPv6 parse/format/re-parse round trip: every
+// formatter output of this type that NewIPAddressString parses back must
+// give an equal address, including zone and prefix length; outputs that do
+// not parse are skipped. This
+// is meant to catch asymmetries between the many IPv6 formatters, the way
+// net/netip's fuzz tests catch them for netip.Addr.
+func FuzzIPv6(f *testing.F) {
+	seeds := []string{
+		"::",
+		"::1",
+		"2001:db8::1",
+		"2001:db8::1/64",
+		"::ffff:1.2.3.4",
+		"64:ff9b::192.0.2.33",
+		"fe80::1%eth0",
+		"fe80::1%25eth0",
+		"FE80:0000:0000:0000:0000:0000:0000:0001",
+		"2001:db8:0:0:1:0:0:1",
+		"::0.0.0.0",
+		"1::",
+		"::%25eth0/64",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		addr, err := NewIPAddressString(s).ToAddress()
+		if err != nil || addr == nil {
+			return
+		}
+		ipv6Addr := addr.ToIPv6Address()
+		if ipv6Addr == nil {
+			return
+		}
+
+		formatters := []func() string{
+			ipv6Addr.ToCanonicalString,
+			ipv6Addr.ToNormalizedString,
+			ipv6Addr.ToCompressedString,
+			ipv6Addr.ToFullString,
+			ipv6Addr.ToReverseDNSString,
+			ipv6Addr.ToSegmentedBinaryString,
+			ipv6Addr.ToMixedString,
+			ipv6Addr.ToUNCString,
+		}
+		for _, format := range formatters {
+			out := format()
+			reparsed, rtErr := NewIPAddressString(out).ToAddress()
+			if rtErr != nil || reparsed == nil {
+				// UNC and mixed forms aren't necessarily re-parseable by
+				// NewIPAddressString itself; skip formats that aren't
+				// intended as round-trippable address strings.
+				continue
+			}
+			reparsedV6 := reparsed.ToIPv6Address()
+			if reparsedV6 == nil {
+				t.Fatalf("format %q of %q re-parsed as non-IPv6", out, ipv6Addr.String())
+			}
+			if reparsedV6.GetLower().String() != ipv6Addr.GetLower().String() {
+				t.Fatalf("format %q of %q round-tripped to %q", out, ipv6Addr.String(), reparsedV6.String())
+			}
+			if reparsedV6.GetZone() != ipv6Addr.GetZone() {
+				t.Fatalf("format %q of %q lost zone: %q != %q", out, ipv6Addr.String(), reparsedV6.GetZone(), ipv6Addr.GetZone())
+			}
+		}
+	})
+}
+
+// FuzzIPv4 is FuzzIPv6's IPv4 companion.
+func FuzzIPv4(f *testing.F) {
+	seeds := []string{
+		"1.2.3.4",
+		"1.2.3.4/24",
+		"0.0.0.0",
+		"255.255.255.255",
+		"1.2.3.4-10",
+		"1.2.3.*",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		addr, err := NewIPAddressString(s).ToAddress()
+		if err != nil || addr == nil {
+			return
+		}
+		ipv4Addr := addr.ToIPv4Address()
+		if ipv4Addr == nil {
+			return
+		}
+		out := ipv4Addr.String()
+		reparsed, rtErr := NewIPAddressString(out).ToAddress()
+		if rtErr != nil || reparsed == nil {
+			t.Fatalf("string form %q of %q failed to re-parse: %v", out, ipv4Addr.String(), rtErr)
+		}
+		if reparsed.GetLower().String() != ipv4Addr.GetLower().String() {
+			t.Fatalf("string form %q of %q round-tripped to %q", out, ipv4Addr.String(), reparsed.GetLower().String())
+		}
+	})
+}
+
+// FuzzHost is FuzzIPv6's host-string companion, fuzzing ParseHostPort's
+// service-endpoint strings rather than bare address strings.
+func FuzzHost(f *testing.F) {
+	seeds := []string{
+		"1.2.3.4:80",
+		"[::1]:80",
+		"[::1%eth0]:80",
+		"[::1]",
+		"1.2.3.4",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		hp, err := ParseHostPort(s)
+		if err != nil || hp == nil {
+			return
+		}
+		out := hp.ToEndpointString()
+		reparsed, rtErr := ParseHostPort(out)
+		if rtErr != nil || reparsed == nil {
+			t.Fatalf("host:port %q failed to re-parse from %q: %v", s, out, rtErr)
+		}
+		if reparsed.Address.GetLower().String() != hp.Address.GetLower().String() {
+			t.Fatalf("host:port %q round-tripped to different address: %q != %q", s, reparsed.Address.String(), hp.Address.String())
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package ipaddr
+
+import "testing"
+
+// FuzzParseIPAddress exercises NewIPAddressString against arbitrary input,
+// checking that a successfully parsed address round-trips through its own
+// string form, and that prefix block derivation never panics.
+func FuzzParseIPAddress(f *testing.F) {
+	seeds := []string{
+		"1.2.3.4",
+		"1.2.3.4/24",
+		"0.0.0.0",
+		"255.255.255.255",
+		"::",
+		"::1",
+		"2001:db8::1",
+		"2001:db8::1/64",
+		"::ffff:1.2.3.4",
+		"fe80::1%eth0",
+		"",
+		"not an address",
+		"1.2.3.4.5",
+		"12345::",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		addr, err := NewIPAddressString(s).ToAddress()
+		if err != nil || addr == nil {
+			return
+		}
+		// a successfully parsed address must round-trip through its own
+		// canonical string representation
+		roundTripped, rtErr := NewIPAddressString(addr.String()).ToAddress()
+		if rtErr != nil || roundTripped == nil {
+			t.Fatalf("address %q failed to round-trip: %v", addr.String(), rtErr)
+		}
+		if roundTripped.GetLower().String() != addr.GetLower().String() {
+			t.Fatalf("round-trip mismatch: %q != %q", roundTripped.GetLower().String(), addr.GetLower().String())
+		}
+
+		// prefix block derivation must not panic regardless of input
+		_ = addr.ToPrefixBlockLen(ipVersionBitCount(addr.GetIPVersion()) / 2)
+	})
+}
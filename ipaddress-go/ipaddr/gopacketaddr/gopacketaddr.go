@@ -0,0 +1,91 @@
+// Package gopacketaddr bridges this module's rich IPv4Address/IPv6Address
+// subnet and range types to github.com/google/gopacket, the de-facto
+// packet-processing library, so a pcap-driven pipeline can read layers.IPv4/
+// layers.IPv6 headers and filter or aggregate by our subnet/range semantics
+// without reparsing the net.IP byte slices gopacket exposes.
+//
+// gopacket has no "AddressDecoder" interface of its own; DecodeIPAddress
+// below plays that role, converting the net.IP gopacket already parsed into
+// an *ipaddr.IPAddress for use with IPAddress.Contains and friends.
+package gopacketaddr
+
+import (
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+)
+
+// FromIPv4Layer reads the source and destination addresses of an IPv4 header
+// directly from its underlying SrcIP/DstIP byte slices, into src and dst.
+func FromIPv4Layer(ip4 *layers.IPv4) (src, dst *ipaddr.IPv4Address, err ipaddr.AddressValueException) {
+	src, err = ipaddr.NewIPv4AddressFromIP(ip4.SrcIP)
+	if err != nil {
+		return nil, nil, err
+	}
+	dst, err = ipaddr.NewIPv4AddressFromIP(ip4.DstIP)
+	if err != nil {
+		return nil, nil, err
+	}
+	return src, dst, nil
+}
+
+// FromIPv6Layer reads the source and destination addresses of an IPv6 header
+// directly from its underlying SrcIP/DstIP byte slices, into src and dst.
+func FromIPv6Layer(ip6 *layers.IPv6) (src, dst *ipaddr.IPv6Address, err ipaddr.AddressValueException) {
+	src, err = ipaddr.NewIPv6AddressFromIP(ip6.SrcIP)
+	if err != nil {
+		return nil, nil, err
+	}
+	dst, err = ipaddr.NewIPv6AddressFromIP(ip6.DstIP)
+	if err != nil {
+		return nil, nil, err
+	}
+	return src, dst, nil
+}
+
+// Endpoint converts addr to a gopacket.Endpoint of EndpointIPv4, usable with
+// gopacket.Flow and gopacket's assembler/reassembler stream keys.
+func Endpoint(addr *ipaddr.IPv4Address) gopacket.Endpoint {
+	return layers.NewIPEndpoint(addr.GetIP())
+}
+
+// EndpointV6 converts addr to a gopacket.Endpoint of EndpointIPv6.
+func EndpointV6(addr *ipaddr.IPv6Address) gopacket.Endpoint {
+	return layers.NewIPEndpoint(addr.GetIP())
+}
+
+// NewFlow builds a gopacket.Flow from src to dst, usable to index gopacket
+// assemblers and reassemblers by this module's address type instead of the
+// raw net.IP bytes gopacket would otherwise require.
+func NewFlow(src, dst *ipaddr.IPv4Address) (gopacket.Flow, error) {
+	return gopacket.FlowFromEndpoints(Endpoint(src), Endpoint(dst))
+}
+
+// NewFlowV6 is NewFlow for IPv6 addresses.
+func NewFlowV6(src, dst *ipaddr.IPv6Address) (gopacket.Flow, error) {
+	return gopacket.FlowFromEndpoints(EndpointV6(src), EndpointV6(dst))
+}
+
+// DecodeIPAddress converts a gopacket-parsed net.IP, such as layers.IPv4.SrcIP
+// or layers.IPv6.DstIP, into an *ipaddr.IPAddress, so a filtering pipeline can
+// match it against a subnet or range via IPAddress.Contains without
+// reparsing it from a string.
+func DecodeIPAddress(ip []byte) (*ipaddr.IPAddress, ipaddr.AddressValueException) {
+	switch len(ip) {
+	case ipaddr.IPv4ByteCount:
+		addr, err := ipaddr.NewIPv4AddressFromIP(ip)
+		if err != nil {
+			return nil, err
+		}
+		return addr.ToIPAddress(), nil
+	case ipaddr.IPv6ByteCount:
+		addr, err := ipaddr.NewIPv6AddressFromIP(ip)
+		if err != nil {
+			return nil, err
+		}
+		return addr.ToIPAddress(), nil
+	default:
+		addr, err := ipaddr.NewIPv4AddressFromIP(ip)
+		return addr.ToIPAddress(), err
+	}
+}
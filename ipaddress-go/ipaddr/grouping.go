@@ -5,6 +5,8 @@ import (
 	"math/big"
 	"sync/atomic"
 	"unsafe"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr/debug"
 )
 
 func createGrouping(divs []*AddressDivision, prefixLength PrefixLen, addrType addrType) *AddressDivisionGrouping {
@@ -261,7 +263,7 @@ func (grouping *addressDivisionGroupingInternal) isAddressSection() bool {
 //	return true
 //}
 
-//func (grouping *addressDivisionGroupingInternal) CompareSize(other AddressDivisionSeries) int { // the getCount() is optimized which is why we do not defer to the method in addressDivisionGroupingBase
+// func (grouping *addressDivisionGroupingInternal) CompareSize(other AddressDivisionSeries) int { // the getCount() is optimized which is why we do not defer to the method in addressDivisionGroupingBase
 func (grouping *addressDivisionGroupingInternal) compareSize(other StandardDivisionGroupingType) int { // the getCount() is optimized which is why we do not defer to the method in addressDivisionGroupingBase
 	if other == nil || other.ToAddressDivisionGrouping() == nil {
 		// our size is 1 or greater, other 0
@@ -598,7 +600,7 @@ func (grouping *addressDivisionGroupingInternal) IsSinglePrefixBlock() bool { //
 		return prefLen != nil && grouping.ContainsSinglePrefixBlock(*prefLen)
 	}
 	cache := grouping.cache
-	if cache == nil {
+	if cache == nil || !debug.IsCacheEnabled() {
 		return calc()
 	}
 	res := cache.isSinglePrefixBlock
@@ -643,7 +645,7 @@ func (grouping *addressDivisionGroupingInternal) GetMinPrefixLenForBlock() BitCo
 		return totalPrefix
 	}
 	cache := grouping.cache
-	if cache == nil {
+	if cache == nil || !debug.IsCacheEnabled() {
 		return calc()
 	}
 	res := cache.minPrefix
@@ -681,7 +683,7 @@ func (grouping *addressDivisionGroupingInternal) GetPrefixLenForSingleBlock() Pr
 		return cacheBitCount(totalPrefix)
 	}
 	cache := grouping.cache
-	if cache == nil {
+	if cache == nil || !debug.IsCacheEnabled() {
 		return calc()
 	}
 	res := cache.equivalentPrefix
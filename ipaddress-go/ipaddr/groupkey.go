@@ -0,0 +1,141 @@
+package ipaddr
+
+// GroupKey is a canonical, stable key identifying the network group an
+// address belongs to, as produced by IPAddress.GroupKey. Two addresses in
+// the same group, per the prefix lengths and tunneling rules in effect when
+// the keys were computed, compare equal as GroupKey values.
+type GroupKey string
+
+// String returns the key as a plain string.
+func (k GroupKey) String() string {
+	return string(k)
+}
+
+// GroupKeyOptions configures IPAddress.GroupKey's bucketing behavior.
+type GroupKeyOptions struct {
+	// IPv4PrefixLen is the prefix length IPv4 addresses, including ones
+	// recovered from a tunneled IPv6 form, are masked to before keying.
+	// Zero means the default of 16.
+	IPv4PrefixLen BitCount
+
+	// IPv6PrefixLen is the prefix length IPv6 unicast addresses are masked
+	// to before keying. Zero means the default of 32.
+	IPv6PrefixLen BitCount
+
+	// UnwrapTunneled, when true, recognizes Teredo (RFC 4380, 2001::/32),
+	// 6to4 (RFC 3056, 2002::/16), ISATAP (RFC 5214), and the NAT64
+	// well-known prefix (RFC 6052, 64:ff9b::/96) and keys the address by its
+	// embedded IPv4 address instead of its IPv6 network prefix. Defaults to
+	// false; use DefaultGroupKeyOptions for a ready-to-use set with this
+	// enabled.
+	UnwrapTunneled bool
+}
+
+// DefaultGroupKeyOptions is the configuration used by GroupKey when called
+// with the zero value: IPv4 masked to /16, IPv6 unicast masked to /32, and
+// tunneled-address unwrapping enabled, mirroring the bucketing a peer-address
+// book uses to keep one autonomous system from dominating a peer set.
+var DefaultGroupKeyOptions = GroupKeyOptions{
+	IPv4PrefixLen:  16,
+	IPv6PrefixLen:  32,
+	UnwrapTunneled: true,
+}
+
+// nat64WellKnownPrefix is the /96 NAT64 translation prefix of RFC 6052
+// section 2.1, embedding an IPv4 address in its low 32 bits.
+var nat64WellKnownPrefix = mustPrefixBlock("64:ff9b::/96")
+
+// GroupKey returns a canonical key bucketing addr by network group: an IPv4
+// address, or an IPv4 address recovered from a recognized tunneling scheme,
+// is masked to opts.IPv4PrefixLen; any other IPv6 address is masked to
+// opts.IPv6PrefixLen. Calling GroupKey with the zero GroupKeyOptions value
+// uses DefaultGroupKeyOptions, not a degenerate /0 mask. A zero prefix
+// length field always selects that field's default, so a /0 mask cannot be
+// requested.
+func (addr *IPAddress) GroupKey(opts GroupKeyOptions) GroupKey {
+	if opts == (GroupKeyOptions{}) {
+		opts = DefaultGroupKeyOptions
+	}
+	v4PrefLen := opts.IPv4PrefixLen
+	if v4PrefLen == 0 {
+		v4PrefLen = 16
+	}
+	v6PrefLen := opts.IPv6PrefixLen
+	if v6PrefLen == 0 {
+		v6PrefLen = 32
+	}
+
+	addr = addr.init()
+	if ipv4Addr := addr.ToIPv4Address(); ipv4Addr != nil {
+		return ipv4GroupKey(ipv4Addr.ToIPAddress(), v4PrefLen)
+	}
+
+	ipv6Addr := addr.ToIPv6Address()
+	if ipv6Addr == nil {
+		return ""
+	}
+	if opts.UnwrapTunneled {
+		if embedded := extractTunneledIPv4(ipv6Addr); embedded != nil {
+			return ipv4GroupKey(embedded, v4PrefLen)
+		}
+	}
+	masked := addr.ToPrefixBlockLen(v6PrefLen)
+	return GroupKey("6:" + masked.GetLower().String())
+}
+
+// ipv4GroupKey masks addr to prefLen and formats it as a GroupKey under the
+// "4:" namespace shared by plain IPv4 addresses and ones recovered from a
+// tunneled IPv6 form, so the two bucket together.
+func ipv4GroupKey(addr *IPAddress, prefLen BitCount) GroupKey {
+	masked := addr.ToPrefixBlockLen(prefLen)
+	return GroupKey("4:" + masked.GetLower().String())
+}
+
+// extractTunneledIPv4 recovers the IPv4 address embedded in addr by Teredo,
+// 6to4, ISATAP, or the RFC 6052/6145 NAT64 well-known prefix, returning nil
+// if addr matches none of them.
+func extractTunneledIPv4(addr *IPv6Address) *IPAddress {
+	class := addr.ToIPAddress().Classify()
+	switch {
+	case class == ClassTeredo:
+		// RFC 4380 section 4: the client's IPv4 address occupies the low 32
+		// bits, obscured by XOR with 0xffffffff.
+		bytes := addr.GetBytes()[12:16]
+		obscured := make([]byte, 4)
+		for i, b := range bytes {
+			obscured[i] = b ^ 0xff
+		}
+		return bytesToIPv4(obscured)
+	case class == Class6to4:
+		// RFC 3056 section 2: the IPv4 address occupies bits 16-47.
+		return bytesToIPv4(addr.GetBytes()[2:6])
+	case nat64WellKnownPrefix.Contains(addr.ToIPAddress()):
+		// RFC 6052 section 2.2: the IPv4 address occupies the low 32 bits.
+		return bytesToIPv4(addr.GetBytes()[12:16])
+	case isISATAP(addr):
+		// RFC 5214 section 6.1: the interface identifier is 0000:5EFE:a.b.c.d
+		// or, for a non-unicast-capable link layer, 0200:5EFE:a.b.c.d.
+		return bytesToIPv4(addr.GetBytes()[12:16])
+	}
+	return nil
+}
+
+// isISATAP reports whether addr's interface identifier (the low 64 bits)
+// follows the ISATAP pattern, 0000:5EFE:a.b.c.d or 0200:5EFE:a.b.c.d.
+func isISATAP(addr *IPv6Address) bool {
+	iid := addr.GetBytes()[8:12]
+	if iid[2] != 0x5e || iid[3] != 0xfe {
+		return false
+	}
+	return (iid[0] == 0 || iid[0] == 2) && iid[1] == 0
+}
+
+// bytesToIPv4 builds an IPAddress from a 4-byte slice, returning nil if the
+// bytes do not form a valid IPv4 address.
+func bytesToIPv4(bytes []byte) *IPAddress {
+	addr, err := NewIPv4AddressFromIP(bytes)
+	if err != nil {
+		return nil
+	}
+	return addr.ToIPAddress()
+}
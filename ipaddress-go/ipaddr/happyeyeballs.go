@@ -0,0 +1,60 @@
+package ipaddr
+
+// SortCandidates reorders addrs in place to follow the RFC 8305 (Happy Eyeballs
+// v2) candidate ordering: candidates interleave by address family, alternating
+// IPv6 and IPv4 starting with whichever family appears first in addrs, so that
+// a caller attempting connections in order tries both families roughly in
+// parallel rather than exhausting one family before trying the other.
+func SortCandidates(addrs []*IPAddress) []*IPAddress {
+	if len(addrs) < 2 {
+		return addrs
+	}
+	var v4, v6, other []*IPAddress
+	firstFamilyIsV6 := false
+	for i, addr := range addrs {
+		if addr.IsIPv6() {
+			v6 = append(v6, addr)
+			if i == 0 {
+				firstFamilyIsV6 = true
+			}
+		} else if addr.IsIPv4() {
+			v4 = append(v4, addr)
+		} else {
+			other = append(other, addr)
+		}
+	}
+	first, second := v4, v6
+	if firstFamilyIsV6 {
+		first, second = v6, v4
+	}
+	result := make([]*IPAddress, 0, len(addrs))
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			result = append(result, first[i])
+		}
+		if i < len(second) {
+			result = append(result, second[i])
+		}
+	}
+	result = append(result, other...)
+	copy(addrs, result)
+	return addrs
+}
+
+// Candidates returns the addresses in rng's span (treated as a flat list of
+// candidates the way a resolver would return A/AAAA results), in ascending
+// order.  Since a sequential range is a single address family, Happy
+// Eyeballs interleaving would not change that order;
+// the function exists so callers working with ranges from multiple lookups
+// can combine and re-sort their candidates with SortCandidates.
+func (rng *IPAddressSeqRange) Candidates() []*IPAddress {
+	if rng == nil {
+		return nil
+	}
+	var result []*IPAddress
+	iter := rng.Iterator()
+	for iter.HasNext() {
+		result = append(result, iter.Next())
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package ipaddr
+
+import "testing"
+
+// TestSortCandidatesInterleaves checks that SortCandidates interleaves IPv6
+// and IPv4 addresses, starting with whichever family appeared first in the
+// input, per RFC 8305.
+func TestSortCandidatesInterleaves(t *testing.T) {
+	addrs := []*IPAddress{
+		mustAddress("2001:db8::1"),
+		mustAddress("1.2.3.4"),
+		mustAddress("2001:db8::2"),
+		mustAddress("1.2.3.5"),
+		mustAddress("2001:db8::3"),
+	}
+	got := SortCandidates(addrs)
+	want := []string{
+		"2001:db8::1",
+		"1.2.3.4",
+		"2001:db8::2",
+		"1.2.3.5",
+		"2001:db8::3",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d addresses, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("position %d: got %q, want %q", i, got[i].String(), want[i])
+		}
+	}
+}
+
+// TestSortCandidatesFirstFamilyIPv4 checks that the interleave instead starts
+// with IPv4 when the input's first address is IPv4.
+func TestSortCandidatesFirstFamilyIPv4(t *testing.T) {
+	addrs := []*IPAddress{
+		mustAddress("1.2.3.4"),
+		mustAddress("2001:db8::1"),
+		mustAddress("1.2.3.5"),
+	}
+	got := SortCandidates(addrs)
+	want := []string{"1.2.3.4", "2001:db8::1", "1.2.3.5"}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("position %d: got %q, want %q", i, got[i].String(), want[i])
+		}
+	}
+}
+
+// TestSortCandidatesSingle checks that a slice with fewer than two addresses
+// is returned unchanged.
+func TestSortCandidatesSingle(t *testing.T) {
+	addrs := []*IPAddress{mustAddress("1.2.3.4")}
+	got := SortCandidates(addrs)
+	if len(got) != 1 || got[0].String() != "1.2.3.4" {
+		t.Errorf("got %v, want unchanged single-element slice", got)
+	}
+}
+
+// TestSeqRangeCandidates checks that Candidates enumerates every address in
+// a small sequential range, in order.
+func TestSeqRangeCandidates(t *testing.T) {
+	lower := mustAddress("1.2.3.0")
+	upper := mustAddress("1.2.3.3")
+	rng := lower.SpanWithRange(upper)
+	got := rng.Candidates()
+	want := []string{"1.2.3.0", "1.2.3.1", "1.2.3.2", "1.2.3.3"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d candidates, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("position %d: got %q, want %q", i, got[i].String(), want[i])
+		}
+	}
+}
+
+// mustAddress parses s, failing the test via panic if it is not a valid
+// address; used to keep the table-driven tests above concise.
+func mustAddress(s string) *IPAddress {
+	addr, err := NewIPAddressString(s).ToAddress()
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}
@@ -0,0 +1,102 @@
+package ipaddr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// HostPort represents a parsed service endpoint: an IP address plus an
+// optional port, as used in the service-endpoint forms accepted by Windows'
+// RtlIpv4StringToAddressEx/RtlIpv6StringToAddressEx: "1.2.3.4:80",
+// "[::1]:80", "[::1%eth0]:80", and the bracketed-no-port form "[::1]".  An
+// unbracketed IPv6 address never carries a port, since the colons would be
+// ambiguous with the address itself.
+type HostPort struct {
+	Address *IPAddress
+	Port    Port
+}
+
+// ParseHostPort parses s as a service-endpoint string.  A bracketed address
+// ("[...]") may be optionally followed by ":port"; an unbracketed address
+// may be followed by ":port" only when the address is IPv4, since an
+// unbracketed IPv6 address is itself colon-separated.
+func ParseHostPort(s string) (*HostPort, AddressStringException) {
+	if strings.HasPrefix(s, "[") {
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return nil, &addressStringError{addressError{key: "ipaddress.error.invalidHostPort"}}
+		}
+		inner := s[1:end]
+		addr, err := ParseZonedIPv6Address(inner)
+		if err != nil {
+			return nil, err
+		}
+		rest := s[end+1:]
+		if rest == "" {
+			return &HostPort{Address: addr.ToIPAddress()}, nil
+		}
+		if !strings.HasPrefix(rest, ":") {
+			return nil, &addressStringError{addressError{key: "ipaddress.error.invalidHostPort"}}
+		}
+		port, portErr := parsePort(rest[1:])
+		if portErr != nil {
+			return nil, portErr
+		}
+		return &HostPort{Address: addr.ToIPAddress(), Port: port}, nil
+	}
+
+	if idx := strings.LastIndexByte(s, ':'); idx >= 0 && strings.Count(s, ":") == 1 {
+		addrStr, portStr := s[:idx], s[idx+1:]
+		str := NewIPAddressString(addrStr)
+		addr, err := str.ToAddress()
+		if err != nil {
+			return nil, err
+		}
+		if addr.ToIPv6Address() != nil {
+			return nil, &addressStringError{addressError{key: "ipaddress.error.invalidHostPort"}}
+		}
+		port, portErr := parsePort(portStr)
+		if portErr != nil {
+			return nil, portErr
+		}
+		return &HostPort{Address: addr, Port: port}, nil
+	}
+
+	str := NewIPAddressString(s)
+	addr, err := str.ToAddress()
+	if err != nil {
+		return nil, err
+	}
+	return &HostPort{Address: addr}, nil
+}
+
+func parsePort(s string) (Port, AddressStringException) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > 65535 {
+		return nil, &addressStringError{addressError{key: "ipaddress.error.invalidPort"}}
+	}
+	return ToPort(PortNum(n)), nil
+}
+
+// ToEndpointString renders this address with the given port appended,
+// bracketing the address when it is IPv6 so the port's colon is
+// unambiguous, eg "1.2.3.4:80" or "[::1%eth0]:80".
+func (hp *HostPort) ToEndpointString() string {
+	if hp.Port == nil {
+		return hp.ToBracketedString()
+	}
+	return hp.ToBracketedString() + ":" + hp.Port.String()
+}
+
+// ToBracketedString renders this address alone, bracketing it when it is
+// IPv6, eg "[::1%eth0]" or "1.2.3.4".
+func (hp *HostPort) ToBracketedString() string {
+	if ipv6Addr := hp.Address.ToIPv6Address(); ipv6Addr != nil {
+		base := ipv6Addr.WithoutZone().ToCanonicalString()
+		if ipv6Addr.HasZone() {
+			base += string(IPv6ZoneSeparator) + string(ipv6Addr.zone)
+		}
+		return "[" + base + "]"
+	}
+	return hp.Address.String()
+}
@@ -0,0 +1,189 @@
+package ipaddr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Inet_AtonMode is a bitmask of the legacy inet_aton textual forms
+// ParseInetAton accepts beyond the standard 4-octet decimal dotted-quad,
+// replacing what would otherwise be a handful of separate boolean options.
+type Inet_AtonMode uint8
+
+const (
+	// Strict4Octet accepts only four decimal octets, eg "192.0.2.1", the
+	// same grammar NewIPAddressString requires.
+	Strict4Octet Inet_AtonMode = 0
+	// AllowJoined accepts 1-, 2-, or 3-part forms, eg "192.2.1" or
+	// "0xc0000201", where the final part absorbs however many trailing
+	// octets the earlier parts did not.
+	AllowJoined Inet_AtonMode = 1 << (iota - 1)
+	// AllowOctal accepts a "0"-prefixed part as octal, eg "0300" for 192.
+	AllowOctal
+	// AllowHex accepts a "0x"-prefixed part as hexadecimal, eg "0xc0" for 192.
+	AllowHex
+)
+
+// GlibcCompatible enables every form glibc's inet_aton accepts: joined,
+// octal, and hexadecimal parts together.
+const GlibcCompatible = AllowJoined | AllowOctal | AllowHex
+
+// IPv4AddressStringParameters controls which inet_aton textual forms
+// ParseInetAton accepts.
+type IPv4AddressStringParameters struct {
+	Mode Inet_AtonMode
+}
+
+// InetAtonSegment records how one dot-separated part of a parsed inet_aton
+// string contributed to the resulting address: its numeric value, the
+// radix (8, 10, or 16) it was written in, and how many of the address's
+// four 8-bit octets it represents (more than one only for the final part
+// of a joined form).
+type InetAtonSegment struct {
+	Value        SegInt
+	Radix        int
+	JoinedOctets int
+}
+
+// InetAtonParseResult is the outcome of ParseInetAton: the resulting
+// address, plus the per-part textual detail needed to reproduce its exact
+// input form with ToInetAtonJoinedString. That detail is carried here
+// rather than on IPv4Address itself because IPv4Address, like this
+// package's other address container types, is a fixed-layout wrapper
+// around a shared internal representation with no room for per-instance
+// parse metadata.
+type InetAtonParseResult struct {
+	Address  *IPv4Address
+	segments []InetAtonSegment
+}
+
+// SegmentCount returns the number of dot-separated parts in the parsed
+// input, which is 4 for a standard dotted-quad and fewer for a joined form.
+func (r *InetAtonParseResult) SegmentCount() int {
+	return len(r.segments)
+}
+
+// SegmentRadix returns the radix (8, 10, or 16) the i'th input part was
+// written in.
+func (r *InetAtonParseResult) SegmentRadix(i int) int {
+	return r.segments[i].Radix
+}
+
+// ParseInetAton parses s as one of glibc's inet_aton textual forms: 1- to
+// 4-part dotted octets, each decimal, octal ("0"-prefixed), or hexadecimal
+// ("0x"-prefixed), with the final part absorbing whichever octets the
+// earlier parts did not — as permitted by params.Mode.
+func ParseInetAton(s string, params IPv4AddressStringParameters) (*InetAtonParseResult, AddressStringException) {
+	parts := strings.Split(s, string(IPv4SegmentSeparator))
+	if len(parts) == 0 || len(parts) > IPv4SegmentCount {
+		return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+	}
+	if len(parts) != IPv4SegmentCount && params.Mode&AllowJoined == 0 {
+		return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+	}
+
+	segments := make([]InetAtonSegment, len(parts))
+	var value uint32
+	for i, part := range parts {
+		val, radix, err := parseInetAtonPart(part, params.Mode)
+		if err != nil {
+			return nil, err
+		}
+		octets := 1
+		if i == len(parts)-1 {
+			octets = IPv4SegmentCount - (len(parts) - 1)
+		}
+		if val > uint64(1)<<uint(8*octets)-1 {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+		}
+		value = value<<uint(8*octets) | uint32(val)
+		segments[i] = InetAtonSegment{Value: SegInt(val), Radix: radix, JoinedOctets: octets}
+	}
+
+	addr := NewIPv4AddressFromValues(func(segmentIndex int) SegInt {
+		shift := uint(8 * (IPv4SegmentCount - 1 - segmentIndex))
+		return SegInt((value >> shift) & 0xff)
+	})
+	return &InetAtonParseResult{Address: addr, segments: segments}, nil
+}
+
+// parseInetAtonPart parses one dot-separated part of an inet_aton string,
+// detecting its radix from the "0x"/"0" prefix conventions and rejecting
+// forms not permitted by mode.
+func parseInetAtonPart(part string, mode Inet_AtonMode) (val uint64, radix int, err AddressStringException) {
+	switch {
+	case len(part) > 1 && (part[:2] == "0x" || part[:2] == "0X"):
+		if mode&AllowHex == 0 {
+			return 0, 0, &addressStringError{addressError: addressError{key: "ipaddress.error.ipv4.leading.zero"}}
+		}
+		n, convErr := strconv.ParseUint(part[2:], 16, 64)
+		if convErr != nil {
+			return 0, 0, &addressStringError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+		}
+		return n, 16, nil
+	case len(part) > 1 && part[0] == '0':
+		if mode&AllowOctal == 0 {
+			return 0, 0, &addressStringError{addressError: addressError{key: "ipaddress.error.ipv4.leading.zero"}}
+		}
+		n, convErr := strconv.ParseUint(part, 8, 64)
+		if convErr != nil {
+			return 0, 0, &addressStringError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+		}
+		return n, 8, nil
+	default:
+		n, convErr := strconv.ParseUint(part, 10, 64)
+		if convErr != nil {
+			return 0, 0, &addressStringError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+		}
+		return n, 10, nil
+	}
+}
+
+// ToInetAtonJoinedString renders addr as a dotted inet_aton form with
+// joinedSegments trailing octets merged into its final part, each part
+// written in the given radix (8, 10, or 16), eg radix 16 and
+// joinedSegments 4 renders "192.0.2.1" as "0xc0000201".
+func (addr *IPv4Address) ToInetAtonJoinedString(radix int, joinedSegments int) (string, AddressValueException) {
+	addr = addr.init()
+	if joinedSegments < 1 || joinedSegments > IPv4SegmentCount {
+		return "", &addressValueError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+	}
+	if radix != 8 && radix != 10 && radix != 16 {
+		return "", &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.radix"}}
+	}
+
+	leading := IPv4SegmentCount - joinedSegments
+	var b strings.Builder
+	for i := 0; i < leading; i++ {
+		if i > 0 {
+			b.WriteByte(IPv4SegmentSeparator)
+		}
+		b.WriteString(formatInetAtonPart(uint64(addr.GetSegment(i).GetSegmentValue()), radix))
+	}
+
+	var joined uint64
+	for i := leading; i < IPv4SegmentCount; i++ {
+		joined = joined<<8 | uint64(addr.GetSegment(i).GetSegmentValue())
+	}
+	if leading > 0 {
+		b.WriteByte(IPv4SegmentSeparator)
+	}
+	b.WriteString(formatInetAtonPart(joined, radix))
+	return b.String(), nil
+}
+
+// formatInetAtonPart renders val in radix with the conventional inet_aton
+// prefix for octal ("0") and hexadecimal ("0x").
+func formatInetAtonPart(val uint64, radix int) string {
+	switch radix {
+	case 16:
+		return "0x" + strconv.FormatUint(val, 16)
+	case 8:
+		if val == 0 {
+			return "0"
+		}
+		return "0" + strconv.FormatUint(val, 8)
+	default:
+		return strconv.FormatUint(val, 10)
+	}
+}
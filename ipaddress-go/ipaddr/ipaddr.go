@@ -1,6 +1,9 @@
 package ipaddr
 
-import "unsafe"
+import (
+	"net"
+	"unsafe"
+)
 
 type IPVersion string
 
@@ -132,8 +135,6 @@ func init() {
 	}
 }
 
-//
-//
 // IPAddress represents an IPAddress, either IPv4 or IPv6.
 // Only the zero-value IPAddress can be neither IPv4 or IPv6.
 // The zero value has no segments, which is not compatible with zero value for ivp4 or ipv6.
@@ -168,6 +169,14 @@ func (addr *IPAddress) GetUpper() *IPAddress {
 	return addr.getUpper().ToIPAddress()
 }
 
+// GetNetIP returns addr's lower value as a net.IPAddr, with its zone if
+// any. The result is computed once and cached; the returned net.IP is
+// always a fresh clone, so callers may freely modify it.
+func (addr *IPAddress) GetNetIP() net.IPAddr {
+	addr = addr.init()
+	return addr.getNetIP()
+}
+
 func (addr *IPAddress) ToPrefixBlock() *IPAddress {
 	addr = addr.init()
 	prefixLength := addr.GetNetworkPrefixLength()
@@ -0,0 +1,462 @@
+package ipaddr
+
+import (
+	"math/big"
+	"sync"
+)
+
+// This file adds a stateful address allocation pool on top of an IPAddress
+// subnet, similar in spirit to Docker libnetwork's address allocator: given
+// a CIDR, a Pool hands out individual addresses or accepts specific ones,
+// and tracks what is free using a run-length encoded bitmap so that sparse
+// allocation out of a huge subnet, eg an IPv6 /64, doesn't require a bit per
+// address up front.
+
+// ipamBlockHostBits bounds how many host bits a single internal bitmap
+// tracks directly; a subnet with more host bits than this is split into
+// 1<<ipamBlockHostBits-sized sub-blocks, each with its own bitmap, created
+// lazily as addresses within it are requested or released. This keeps a
+// sparsely-used IPv6 /64 (64 host bits) to a handful of mostly-empty bitmaps
+// rather than one bitmap sized to 2^64.
+const ipamBlockHostBits = 32
+
+// ipamRun is one run of consecutive bits of the same value within an
+// ipamBitmap, the "(head, []run{bits, count})" scheme: a bitmap is just a
+// sequence of these runs, and setting or clearing a bit splits or merges the
+// runs around it rather than touching every bit in between.
+type ipamRun struct {
+	used  bool
+	count uint64
+}
+
+// ipamBitmap is a compressed bitmap of size bits, represented as a sequence
+// of runs of consecutive equal bits, so that a bitmap that is almost
+// entirely free (or almost entirely used) stays to a handful of runs
+// regardless of how large size is.
+type ipamBitmap struct {
+	size uint64
+	runs []ipamRun
+}
+
+func newIpamBitmap(size uint64) *ipamBitmap {
+	bm := &ipamBitmap{size: size}
+	if size > 0 {
+		bm.runs = []ipamRun{{used: false, count: size}}
+	}
+	return bm
+}
+
+func (bm *ipamBitmap) get(i uint64) bool {
+	var pos uint64
+	for _, r := range bm.runs {
+		if i < pos+r.count {
+			return r.used
+		}
+		pos += r.count
+	}
+	return false
+}
+
+// set marks bit i as used or free, splitting the run containing i if
+// necessary, then merging the result with its neighbors if they now share
+// the same value.
+func (bm *ipamBitmap) set(i uint64, used bool) {
+	if i >= bm.size {
+		return
+	}
+	var pos uint64
+	for idx, r := range bm.runs {
+		if i >= pos+r.count {
+			pos += r.count
+			continue
+		}
+		if r.used == used {
+			return
+		}
+		before := i - pos
+		after := r.count - before - 1
+		replacement := make([]ipamRun, 0, 3)
+		if before > 0 {
+			replacement = append(replacement, ipamRun{used: r.used, count: before})
+		}
+		replacement = append(replacement, ipamRun{used: used, count: 1})
+		if after > 0 {
+			replacement = append(replacement, ipamRun{used: r.used, count: after})
+		}
+		merged := make([]ipamRun, 0, len(bm.runs)-1+len(replacement))
+		merged = append(merged, bm.runs[:idx]...)
+		merged = append(merged, replacement...)
+		merged = append(merged, bm.runs[idx+1:]...)
+		bm.runs = merged
+		newIdx := idx
+		if before > 0 {
+			newIdx++
+		}
+		bm.mergeAround(newIdx)
+		return
+	}
+}
+
+// mergeAround merges the run at idx with its neighbors if they carry the
+// same value, collapsing what set may have just split back apart.
+func (bm *ipamBitmap) mergeAround(idx int) {
+	if idx > 0 && bm.runs[idx-1].used == bm.runs[idx].used {
+		bm.runs[idx-1].count += bm.runs[idx].count
+		bm.runs = append(bm.runs[:idx], bm.runs[idx+1:]...)
+		idx--
+	}
+	if idx+1 < len(bm.runs) && bm.runs[idx].used == bm.runs[idx+1].used {
+		bm.runs[idx].count += bm.runs[idx+1].count
+		bm.runs = append(bm.runs[:idx+1], bm.runs[idx+2:]...)
+	}
+}
+
+// firstFree scans runs, not bits, for the first free index, giving
+// RequestAddress an O(runs) lookup instead of an O(size) one.
+func (bm *ipamBitmap) firstFree() (uint64, bool) {
+	var pos uint64
+	for _, r := range bm.runs {
+		if !r.used {
+			return pos, true
+		}
+		pos += r.count
+	}
+	return 0, false
+}
+
+func (bm *ipamBitmap) countFree() uint64 {
+	var n uint64
+	for _, r := range bm.runs {
+		if !r.used {
+			n += r.count
+		}
+	}
+	return n
+}
+
+func putUint64(buf []byte, v uint64) []byte {
+	for shift := 56; shift >= 0; shift -= 8 {
+		buf = append(buf, byte(v>>uint(shift)))
+	}
+	return buf
+}
+
+func takeUint64(data []byte) (uint64, []byte, bool) {
+	if len(data) < 8 {
+		return 0, data, false
+	}
+	var v uint64
+	for i := 0; i < 8; i++ {
+		v = v<<8 | uint64(data[i])
+	}
+	return v, data[8:], true
+}
+
+// marshalBinary encodes bm as its bit count followed by each run as a
+// used-flag byte and an 8-byte count.
+func (bm *ipamBitmap) marshalBinary() []byte {
+	out := putUint64(make([]byte, 0, 16+len(bm.runs)*9), bm.size)
+	out = putUint64(out, uint64(len(bm.runs)))
+	for _, r := range bm.runs {
+		flag := byte(0)
+		if r.used {
+			flag = 1
+		}
+		out = append(out, flag)
+		out = putUint64(out, r.count)
+	}
+	return out
+}
+
+func unmarshalIpamBitmap(data []byte) (*ipamBitmap, AddressValueException) {
+	size, data, ok := takeUint64(data)
+	if !ok {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	runCount, data, ok := takeUint64(data)
+	if !ok {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	bm := &ipamBitmap{size: size, runs: make([]ipamRun, 0, runCount)}
+	for i := uint64(0); i < runCount; i++ {
+		if len(data) < 1 {
+			return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		used := data[0] != 0
+		data = data[1:]
+		var count uint64
+		count, data, ok = takeUint64(data)
+		if !ok {
+			return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		bm.runs = append(bm.runs, ipamRun{used: used, count: count})
+	}
+	return bm, nil
+}
+
+// Pool is a stateful address allocation pool carved out of an IPv4 or IPv6
+// CIDR. It hands out individual addresses with RequestAddress, accepts
+// requests for specific addresses with RequestSpecific, and tracks released
+// addresses for reuse, all backed by run-length encoded bitmaps so that a
+// sparsely-allocated subnet does not require a bit in memory per address.
+//
+// A Pool is safe for concurrent use by multiple goroutines.
+type Pool struct {
+	mu        sync.Mutex
+	version   IPVersion
+	base      *IPAddress
+	baseIndex *big.Int
+	hostBits  BitCount
+	total     *big.Int
+	blocks    map[string]*ipamBitmap
+}
+
+// NewPool builds a Pool covering every address of cidr, a prefix block such
+// as 10.0.0.0/8 or 2001:db8::/32. The network and, for IPv4 subnets of more
+// than two addresses, broadcast addresses are reserved automatically and
+// will never be handed out by RequestAddress.
+func NewPool(cidr *IPAddress) (*Pool, AddressValueException) {
+	cidr = cidr.init()
+	prefLen := cidr.GetNetworkPrefixLength()
+	if prefLen == nil {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixLength.required"}}
+	}
+	version := cidr.GetIPVersion()
+	addrBits := BitCount(ipVersionBitCount(version))
+	hostBits := addrBits - prefLen.Len()
+	p := &Pool{
+		version:   version,
+		base:      cidr.ToPrefixBlockLen(prefLen.Len()),
+		baseIndex: addrValue(cidr.ToPrefixBlockLen(prefLen.Len()).GetLower()),
+		hostBits:  hostBits,
+		total:     new(big.Int).Lsh(bigOne(), uint(hostBits)),
+		blocks:    make(map[string]*ipamBitmap),
+	}
+	if version == IPv4 && hostBits >= 2 {
+		p.mu.Lock()
+		p.markUsedLocked(big.NewInt(0))
+		p.markUsedLocked(new(big.Int).Sub(p.total, bigOne()))
+		p.mu.Unlock()
+	}
+	return p, nil
+}
+
+// blockHostBits is the number of host bits tracked by a single bitmap; the
+// remaining, outer host bits select which lazily-created bitmap to use.
+func (p *Pool) blockHostBits() BitCount {
+	if p.hostBits <= ipamBlockHostBits {
+		return p.hostBits
+	}
+	return ipamBlockHostBits
+}
+
+func (p *Pool) blockSize() uint64 {
+	return uint64(1) << uint(p.blockHostBits())
+}
+
+// blockFor returns the bitmap tracking offset (a host-bits index into the
+// pool), creating it on first use, along with the offset within that block.
+func (p *Pool) blockFor(offset *big.Int) (*ipamBitmap, uint64) {
+	blockSize := p.blockSize()
+	blockSizeBig := new(big.Int).SetUint64(blockSize)
+	blockIndex := new(big.Int).Div(offset, blockSizeBig)
+	within := new(big.Int).Mod(offset, blockSizeBig)
+	key := blockIndex.String()
+	bm := p.blocks[key]
+	if bm == nil {
+		size := blockSize
+		// the last block of a pool whose size isn't a multiple of
+		// blockSize is shorter than blockSize
+		remaining := new(big.Int).Sub(p.total, new(big.Int).Mul(blockIndex, blockSizeBig))
+		if remaining.IsUint64() && remaining.Uint64() < size {
+			size = remaining.Uint64()
+		}
+		bm = newIpamBitmap(size)
+		p.blocks[key] = bm
+	}
+	return bm, within.Uint64()
+}
+
+func (p *Pool) markUsedLocked(offset *big.Int) {
+	bm, within := p.blockFor(offset)
+	bm.set(within, true)
+}
+
+func (p *Pool) markFreeLocked(offset *big.Int) {
+	bm, within := p.blockFor(offset)
+	bm.set(within, false)
+}
+
+func (p *Pool) addrAt(offset *big.Int) *IPAddress {
+	return valueToAddr(new(big.Int).Add(p.baseIndex, offset), p.version)
+}
+
+func (p *Pool) offsetOf(addr *IPAddress) (*big.Int, AddressValueException) {
+	if addr.GetIPVersion() != p.version || !p.base.Contains(addr) {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	}
+	offset := new(big.Int).Sub(addrValue(addr), p.baseIndex)
+	if offset.Sign() < 0 || offset.Cmp(p.total) >= 0 {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.address.out.of.range"}}
+	}
+	return offset, nil
+}
+
+// RequestAddress returns the first free address in the pool, marking it
+// used. It returns an error if the pool is exhausted.
+func (p *Pool) RequestAddress() (*IPAddress, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	blockSizeBig := new(big.Int).SetUint64(p.blockSize())
+	// blocks are created lazily, so an never-touched block is entirely
+	// free; walk block indices from 0 and take the first free bit of the
+	// first block, touched or not, that has one: the lowest free address.
+	blockCount := new(big.Int).Add(new(big.Int).Div(new(big.Int).Sub(p.total, bigOne()), blockSizeBig), bigOne())
+	for blockIndex := big.NewInt(0); blockIndex.Cmp(blockCount) < 0; blockIndex.Add(blockIndex, bigOneConst()) {
+		key := blockIndex.String()
+		bm := p.blocks[key]
+		var within uint64
+		var ok bool
+		if bm == nil {
+			within, ok = 0, true
+		} else {
+			within, ok = bm.firstFree()
+		}
+		if !ok {
+			continue
+		}
+		offset := new(big.Int).Add(new(big.Int).Mul(blockIndex, blockSizeBig), new(big.Int).SetUint64(within))
+		p.markUsedLocked(offset)
+		return p.addrAt(offset), nil
+	}
+	return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.address.pool.exhausted"}}
+}
+
+// RequestSpecific marks addr used, returning an error if it is outside the
+// pool or already allocated.
+func (p *Pool) RequestSpecific(addr *IPAddress) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	offset, err := p.offsetOf(addr)
+	if err != nil {
+		return err
+	}
+	bm, within := p.blockFor(offset)
+	if bm.get(within) {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.address.already.allocated"}}
+	}
+	bm.set(within, true)
+	return nil
+}
+
+// Release marks addr free again, making it eligible for reuse by a future
+// RequestAddress or RequestSpecific call. Releasing an address not
+// currently allocated is a no-op.
+func (p *Pool) Release(addr *IPAddress) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	offset, err := p.offsetOf(addr)
+	if err != nil {
+		return err
+	}
+	p.markFreeLocked(offset)
+	return nil
+}
+
+// Available returns the number of addresses in the pool not currently
+// allocated.
+func (p *Pool) Available() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var free uint64
+	trackedSize := big.NewInt(0)
+	for _, bm := range p.blocks {
+		free += bm.countFree()
+		trackedSize.Add(trackedSize, new(big.Int).SetUint64(bm.size))
+	}
+	// addresses outside every created bitmap are all free
+	untracked := new(big.Int).Sub(p.total, trackedSize)
+	if untracked.Sign() > 0 && untracked.IsUint64() {
+		free += untracked.Uint64()
+	}
+	return free
+}
+
+// Range returns a new Pool restricted to the addresses between low and high,
+// inclusive, both of which must lie within the receiver's subnet. The
+// returned Pool tracks its own allocation state independently of the
+// receiver; it is a fresh suballocator scoped to that range, not a shared
+// view onto it.
+func (p *Pool) Range(low, high *IPAddress) (*Pool, AddressValueException) {
+	if low.GetIPVersion() != p.version || high.GetIPVersion() != p.version {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	}
+	if !p.base.Contains(low) || !p.base.Contains(high) {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.address.out.of.range"}}
+	}
+	lowVal, highVal := addrValue(low), addrValue(high)
+	if lowVal.Cmp(highVal) > 0 {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.address.out.of.range"}}
+	}
+	return &Pool{
+		version:   p.version,
+		base:      p.base,
+		baseIndex: lowVal,
+		hostBits:  p.hostBits,
+		total:     new(big.Int).Add(new(big.Int).Sub(highVal, lowVal), bigOne()),
+		blocks:    make(map[string]*ipamBitmap),
+	}, nil
+}
+
+// MarshalBinary encodes the pool's full allocation state - which addresses
+// are used or free - so it can be persisted and later restored with
+// UnmarshalBinary. The underlying CIDR itself is not encoded; the caller is
+// expected to reconstruct the Pool with NewPool and then call
+// UnmarshalBinary on it.
+func (p *Pool) MarshalBinary() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := putUint64(make([]byte, 0, 16), uint64(len(p.blocks)))
+	for key, bm := range p.blocks {
+		keyBytes := []byte(key)
+		out = putUint64(out, uint64(len(keyBytes)))
+		out = append(out, keyBytes...)
+		bmBytes := bm.marshalBinary()
+		out = putUint64(out, uint64(len(bmBytes)))
+		out = append(out, bmBytes...)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary restores allocation state encoded by MarshalBinary into
+// the receiver, which must already have been created with NewPool against
+// the same CIDR.
+func (p *Pool) UnmarshalBinary(data []byte) error {
+	blockCount, data, ok := takeUint64(data)
+	if !ok {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	blocks := make(map[string]*ipamBitmap, blockCount)
+	for i := uint64(0); i < blockCount; i++ {
+		keyLen, rest, ok := takeUint64(data)
+		if !ok || uint64(len(rest)) < keyLen {
+			return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		key := string(rest[:keyLen])
+		data = rest[keyLen:]
+		bmLen, rest, ok := takeUint64(data)
+		if !ok || uint64(len(rest)) < bmLen {
+			return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		bm, err := unmarshalIpamBitmap(rest[:bmLen])
+		if err != nil {
+			return err
+		}
+		data = rest[bmLen:]
+		blocks[key] = bm
+	}
+	p.mu.Lock()
+	p.blocks = blocks
+	p.mu.Unlock()
+	return nil
+}
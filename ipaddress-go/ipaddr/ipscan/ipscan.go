@@ -0,0 +1,251 @@
+// Package ipscan provides a line-oriented IP address scanner for ingesting
+// firewall, proxy, and access logs, replacing the ad-hoc combination of a
+// bufio.Scanner and ipaddr.NewIPAddressString that such ingestion typically
+// reaches for. LineScanner parses one address per line; MultiScanner fans
+// that parsing across goroutines while preserving line order; and Dispatch
+// routes each parsed address to a handler registered by CIDR, backed by the
+// ipaddr/trie package.
+package ipscan
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr/trie"
+)
+
+// ValidationOptions controls how LineScanner and MultiScanner parse each
+// line. The zero value parses under ipaddr.DefaultParseOptions and returns
+// every malformed line as an error from Next.
+type ValidationOptions struct {
+	// Parse controls the strictness ipaddr.ParseIPAddressStrict applies to
+	// each line.
+	Parse ipaddr.ParseOptions
+	// SkipInvalid, if true, silently skips lines that fail to parse instead
+	// of returning them as an error.
+	SkipInvalid bool
+}
+
+// LineScanner reads one IP address per line from an io.Reader, in the style
+// of bufio.Scanner, blank lines are skipped.
+type LineScanner struct {
+	scanner *bufio.Scanner
+	opts    ValidationOptions
+	lineNum int
+}
+
+// NewLineScanner returns a LineScanner reading lines from r under opts. A
+// nil opts is equivalent to the zero ValidationOptions.
+func NewLineScanner(r io.Reader, opts *ValidationOptions) *LineScanner {
+	if opts == nil {
+		opts = &ValidationOptions{}
+	}
+	return &LineScanner{scanner: bufio.NewScanner(r), opts: *opts}
+}
+
+// Next parses and returns the next non-blank line's address, 1-based line
+// number, and raw line bytes. It returns io.EOF once the underlying reader
+// is exhausted, or any error bufio.Scanner itself encountered. A line that
+// fails to parse is returned as (nil, lineNum, rawBytes, parseErr) unless
+// opts.SkipInvalid is set, in which case it is skipped.
+func (s *LineScanner) Next() (addr *ipaddr.IPAddress, lineNum int, rawBytes []byte, err error) {
+	for s.scanner.Scan() {
+		s.lineNum++
+		raw := append([]byte(nil), s.scanner.Bytes()...)
+		line := strings.TrimSpace(string(raw))
+		if line == "" {
+			continue
+		}
+		parsed, parseErr := ipaddr.ParseIPAddressStrict(line, s.opts.Parse)
+		if parseErr != nil {
+			if s.opts.SkipInvalid {
+				continue
+			}
+			return nil, s.lineNum, raw, parseErr
+		}
+		return parsed, s.lineNum, raw, nil
+	}
+	if scanErr := s.scanner.Err(); scanErr != nil {
+		return nil, s.lineNum, nil, scanErr
+	}
+	return nil, s.lineNum, nil, io.EOF
+}
+
+// Record is one line's parse outcome as produced by MultiScanner.
+type Record struct {
+	Addr    *ipaddr.IPAddress
+	LineNum int
+	Raw     []byte
+	Err     error
+}
+
+// MultiScanner parses lines from an io.Reader across a pool of goroutines,
+// restoring the original line order on output so that concurrent parsing is
+// transparent to the caller.
+type MultiScanner struct {
+	Workers int
+}
+
+// NewMultiScanner returns a MultiScanner that parses with the given number
+// of worker goroutines, at least 1.
+func NewMultiScanner(workers int) *MultiScanner {
+	if workers < 1 {
+		workers = 1
+	}
+	return &MultiScanner{Workers: workers}
+}
+
+// Scan reads lines from r, parses them across m.Workers goroutines under
+// opts, and returns a channel delivering one Record per non-blank line in
+// original line order. The channel is closed once r is exhausted or ctx is
+// canceled.
+func (m *MultiScanner) Scan(ctx context.Context, r io.Reader, opts *ValidationOptions) <-chan Record {
+	if opts == nil {
+		opts = &ValidationOptions{}
+	}
+	type job struct {
+		lineNum int
+		raw     []byte
+	}
+	jobs := make(chan job)
+	unordered := make(chan Record)
+	out := make(chan Record)
+
+	var wg sync.WaitGroup
+	wg.Add(m.Workers)
+	for i := 0; i < m.Workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				rec := Record{LineNum: j.lineNum, Raw: j.raw}
+				// blank lines still go to reorder so the line sequence has no gaps
+				if line := strings.TrimSpace(string(j.raw)); line != "" {
+					rec.Addr, rec.Err = ipaddr.ParseIPAddressStrict(line, opts.Parse)
+				}
+				select {
+				case unordered <- rec:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		scanner := bufio.NewScanner(r)
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			raw := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case jobs <- job{lineNum: lineNum, raw: raw}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(unordered)
+	}()
+	go reorder(ctx, unordered, out)
+	return out
+}
+
+// reorder buffers Records arriving out of order on in and emits them on out
+// strictly by ascending LineNum, since concurrent workers may finish in any
+// order.
+func reorder(ctx context.Context, in <-chan Record, out chan<- Record) {
+	defer close(out)
+	pending := make(map[int]Record)
+	next := 1
+	flush := func() bool {
+		for {
+			rec, ok := pending[next]
+			if !ok {
+				return true
+			}
+			delete(pending, next)
+			next++
+			if strings.TrimSpace(string(rec.Raw)) == "" {
+				continue
+			}
+			select {
+			case out <- rec:
+			case <-ctx.Done():
+				return false
+			}
+		}
+	}
+	for {
+		select {
+		case rec, ok := <-in:
+			if !ok {
+				flush()
+				return
+			}
+			pending[rec.LineNum] = rec
+			if !flush() {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Handler processes one address dispatched by Dispatch.
+type Handler func(addr *ipaddr.IPAddress, lineNum int, raw []byte)
+
+// Dispatch routes addresses read from a log stream to handlers registered
+// by CIDR, resolving each address's handler via longest-prefix match in an
+// ipaddr/trie.AssociativeTrie.
+type Dispatch struct {
+	trie *trie.AssociativeTrie[Handler]
+}
+
+// NewDispatch returns a Dispatch with no registered handlers.
+func NewDispatch() *Dispatch {
+	return &Dispatch{trie: trie.NewAssociativeTrie[Handler]()}
+}
+
+// OnMatch registers handler to be invoked for every address Run dispatches
+// that falls within cidr, eg "10.0.0.0/8" or "fe80::/10". A narrower cidr
+// registered later takes precedence over a broader one already registered,
+// per the trie's longest-prefix-match lookup.
+func (d *Dispatch) OnMatch(cidr string, handler Handler) error {
+	prefix, err := ipaddr.NewIPAddressString(cidr).ToAddress()
+	if err != nil {
+		return err
+	}
+	d.trie.Add(prefix, handler)
+	return nil
+}
+
+// Run reads lines from r via a LineScanner configured with opts, invoking
+// the handler registered for each successfully parsed address's longest
+// matching CIDR, if any, until r is exhausted or ctx is canceled. Lines
+// that fail to parse are silently skipped; configure opts.SkipInvalid to
+// control only whether LineScanner itself treats them as terminal errors.
+func (d *Dispatch) Run(ctx context.Context, r io.Reader, opts *ValidationOptions) error {
+	scanner := NewLineScanner(r, opts)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		addr, lineNum, raw, err := scanner.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			continue
+		}
+		if handler, ok := d.trie.Get(addr); ok {
+			handler(addr, lineNum, raw)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package ipaddr
+
+import (
+	"math/big"
+	"sort"
+)
+
+// ipSetRange is a single inclusive [lo,hi] range of addresses of one IP version,
+// represented as the 128-bit (or 32-bit, for IPv4) integer value of the address.
+type ipSetRange struct {
+	lo, hi  *big.Int
+	version IPVersion
+}
+
+// IPSetBuilder accumulates addresses, ranges, and prefix blocks of either IP
+// version and produces an immutable, coalesced IPSet via Build.  IPv4 and
+// IPv6 may be freely mixed; the two versions are always kept and coalesced
+// separately internally, since their numeric address values overlap.  The
+// zero value is an empty builder ready to use.  A builder is not safe for
+// concurrent use.
+type IPSetBuilder struct {
+	ranges []ipSetRange
+}
+
+func (b *IPSetBuilder) addRange(lo, hi *big.Int, version IPVersion) {
+	b.ranges = append(b.ranges, ipSetRange{lo, hi, version})
+}
+
+// Add adds a single address, or all addresses covered by a subnet, to the set.
+func (b *IPSetBuilder) Add(addr *IPAddress) {
+	if addr == nil {
+		return
+	}
+	addr = addr.init()
+	b.addRange(addrValue(addr.GetLower()), addrValue(addr.GetUpper()), addr.GetIPVersion())
+}
+
+// AddRange adds every address in the given sequential range to the set.
+func (b *IPSetBuilder) AddRange(rng *IPAddressSeqRange) {
+	if rng == nil {
+		return
+	}
+	b.addRange(addrValue(rng.GetLower()), addrValue(rng.GetUpper()), rng.GetIPVersion())
+}
+
+// AddPrefix adds the prefix block covering addr's network prefix.
+func (b *IPSetBuilder) AddPrefix(addr *IPAddress) {
+	if addr == nil {
+		return
+	}
+	b.Add(addr.init().ToPrefixBlock())
+}
+
+// RemoveRange removes every address in the given sequential range from the set
+// being built so far.
+func (b *IPSetBuilder) RemoveRange(rng *IPAddressSeqRange) {
+	if rng == nil || len(b.ranges) == 0 {
+		return
+	}
+	built := b.Build()
+	removed := built.difference(ipSetRange{addrValue(rng.GetLower()), addrValue(rng.GetUpper()), rng.GetIPVersion()})
+	b.ranges = removed.ranges
+}
+
+// Remove removes a single address, or all addresses covered by a subnet, from
+// the set being built so far.
+func (b *IPSetBuilder) Remove(addr *IPAddress) {
+	if addr == nil {
+		return
+	}
+	addr = addr.init()
+	built := b.Build()
+	removed := built.difference(ipSetRange{addrValue(addr.GetLower()), addrValue(addr.GetUpper()), addr.GetIPVersion()})
+	b.ranges = removed.ranges
+}
+
+// RemovePrefix removes the prefix block covering addr's network prefix from
+// the set being built so far.
+func (b *IPSetBuilder) RemovePrefix(addr *IPAddress) {
+	if addr == nil {
+		return
+	}
+	b.Remove(addr.init().ToPrefixBlock())
+}
+
+// Build returns an immutable IPSet containing the sorted, coalesced union of
+// everything added to the builder so far.  The builder remains usable afterward.
+func (b *IPSetBuilder) Build() *IPSet {
+	return newIPSet(b.ranges)
+}
+
+// IPSet is an immutable set of addresses, possibly spanning both IPv4 and
+// IPv6, represented internally as a sorted slice of disjoint, coalesced
+// [lo,hi] ranges: every IPv4 range precedes every IPv6 range, each block
+// independently sorted and coalesced, so the two address spaces' numeric
+// values, which overlap, are never compared against each other.
+type IPSet struct {
+	ranges  []ipSetRange
+	v4Count int // ranges[:v4Count] are IPv4, ranges[v4Count:] are IPv6
+}
+
+// newIPSet builds an IPSet from a, possibly unsorted, possibly mixed-version
+// slice of ranges, establishing the v4-block-then-v6-block invariant.
+func newIPSet(in []ipSetRange) *IPSet {
+	var v4, v6 []ipSetRange
+	for _, r := range in {
+		if r.version == IPv6 {
+			v6 = append(v6, r)
+		} else {
+			v4 = append(v4, r)
+		}
+	}
+	v4 = coalesce(v4)
+	ranges := append(v4, coalesce(v6)...)
+	return &IPSet{ranges: ranges, v4Count: len(v4)}
+}
+
+func coalesce(in []ipSetRange) []ipSetRange {
+	if len(in) == 0 {
+		return nil
+	}
+	sorted := make([]ipSetRange, len(in))
+	copy(sorted, in)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].lo.Cmp(sorted[j].lo) < 0 })
+	result := make([]ipSetRange, 0, len(sorted))
+	cur := sorted[0]
+	for _, r := range sorted[1:] {
+		// adjacent or overlapping ranges merge into one
+		if r.lo.Cmp(new(big.Int).Add(cur.hi, bigOne())) <= 0 {
+			if r.hi.Cmp(cur.hi) > 0 {
+				cur.hi = r.hi
+			}
+			continue
+		}
+		result = append(result, cur)
+		cur = r
+	}
+	return append(result, cur)
+}
+
+// block returns the sub-slice of set.ranges holding the given version, relying
+// on the v4-block-then-v6-block invariant.
+func (set *IPSet) block(version IPVersion) []ipSetRange {
+	if version == IPv6 {
+		return set.ranges[set.v4Count:]
+	}
+	return set.ranges[:set.v4Count]
+}
+
+// Contains returns whether every address represented by addr is a member of the set.
+func (set *IPSet) Contains(addr *IPAddress) bool {
+	if set == nil || addr == nil {
+		return false
+	}
+	addr = addr.init()
+	ranges := set.block(addr.GetIPVersion())
+	lo, hi := addrValue(addr.GetLower()), addrValue(addr.GetUpper())
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].hi.Cmp(lo) >= 0 })
+	return i < len(ranges) && ranges[i].lo.Cmp(lo) <= 0 && ranges[i].hi.Cmp(hi) >= 0
+}
+
+// ContainsPrefix returns whether every address in prefix's CIDR block is a
+// member of the set; it is equivalent to Contains(prefix), spelled to match
+// the name used by similar IPSet types in other address libraries.
+func (set *IPSet) ContainsPrefix(prefix *IPAddress) bool {
+	return set.Contains(prefix)
+}
+
+// Ranges returns the disjoint sequential ranges making up this set, in
+// ascending order, every IPv4 range before every IPv6 range.
+func (set *IPSet) Ranges() []*IPAddressSeqRange {
+	if set == nil {
+		return nil
+	}
+	result := make([]*IPAddressSeqRange, len(set.ranges))
+	for i, r := range set.ranges {
+		result[i] = valueToAddr(r.lo, r.version).SpanWithRange(valueToAddr(r.hi, r.version))
+	}
+	return result
+}
+
+// Prefixes returns the minimal list of CIDR prefix blocks whose union exactly
+// equals this set, using the standard largest-aligned-block algorithm: repeatedly
+// emit the largest CIDR block whose network address is >= lo and whose broadcast
+// address is <= hi, then advance lo past that block.
+func (set *IPSet) Prefixes() []*IPAddress {
+	if set == nil {
+		return nil
+	}
+	var result []*IPAddress
+	for _, r := range set.ranges {
+		bitCount := ipVersionBitCount(r.version)
+		lo := new(big.Int).Set(r.lo)
+		for lo.Cmp(r.hi) <= 0 {
+			maxSize := trailingZeroBits(lo, bitCount)
+			for {
+				blockSize := new(big.Int).Lsh(bigOne(), uint(maxSize))
+				blockEnd := new(big.Int).Sub(new(big.Int).Add(lo, blockSize), bigOne())
+				if blockEnd.Cmp(r.hi) <= 0 {
+					break
+				}
+				maxSize--
+			}
+			prefLen := bitCount - maxSize
+			result = append(result, valueToAddr(lo, r.version).ToPrefixBlockLen(prefLen))
+			blockSize := new(big.Int).Lsh(bigOne(), uint(maxSize))
+			lo.Add(lo, blockSize)
+		}
+	}
+	return result
+}
+
+// trailingZeroBits returns the number of trailing zero bits in v, capped at bitCount.
+func trailingZeroBits(v *big.Int, bitCount int) int {
+	if v.Sign() == 0 {
+		return bitCount
+	}
+	count := 0
+	for count < bitCount && v.Bit(count) == 0 {
+		count++
+	}
+	return count
+}
+
+// Union returns the set containing every address in either set or other.
+func (set *IPSet) Union(other *IPSet) *IPSet {
+	if set == nil {
+		return other
+	}
+	if other == nil {
+		return set
+	}
+	combined := append(append([]ipSetRange{}, set.ranges...), other.ranges...)
+	return newIPSet(combined)
+}
+
+// Intersect returns the set containing every address in both set and other.
+func (set *IPSet) Intersect(other *IPSet) *IPSet {
+	if set == nil || other == nil {
+		return &IPSet{}
+	}
+	var result []ipSetRange
+	for _, version := range [...]IPVersion{IPv4, IPv6} {
+		result = append(result, intersectBlock(set.block(version), other.block(version))...)
+	}
+	return newIPSet(result)
+}
+
+func intersectBlock(a, b []ipSetRange) []ipSetRange {
+	var result []ipSetRange
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		ra, rb := a[i], b[j]
+		lo := maxBig(ra.lo, rb.lo)
+		hi := minBig(ra.hi, rb.hi)
+		if lo.Cmp(hi) <= 0 {
+			result = append(result, ipSetRange{lo, hi, ra.version})
+		}
+		if ra.hi.Cmp(rb.hi) < 0 {
+			i++
+		} else {
+			j++
+		}
+	}
+	return result
+}
+
+// Difference returns the set containing every address in set but not in other.
+func (set *IPSet) Difference(other *IPSet) *IPSet {
+	if set == nil {
+		return &IPSet{}
+	}
+	if other == nil {
+		return set
+	}
+	result := set
+	for _, r := range other.ranges {
+		result = result.difference(r)
+	}
+	return result
+}
+
+// difference removes every address in remove from set, comparing only
+// against ranges of the same IP version as remove.
+func (set *IPSet) difference(remove ipSetRange) *IPSet {
+	var result []ipSetRange
+	for _, r := range set.ranges {
+		if r.version != remove.version || remove.hi.Cmp(r.lo) < 0 || remove.lo.Cmp(r.hi) > 0 {
+			result = append(result, r)
+			continue
+		}
+		if remove.lo.Cmp(r.lo) > 0 {
+			result = append(result, ipSetRange{r.lo, new(big.Int).Sub(remove.lo, bigOne()), r.version})
+		}
+		if remove.hi.Cmp(r.hi) < 0 {
+			result = append(result, ipSetRange{new(big.Int).Add(remove.hi, bigOne()), r.hi, r.version})
+		}
+	}
+	return newIPSet(result)
+}
+
+// Complement returns the set of every address not a member of this set, for
+// each IP version actually represented in this set; a version with no ranges
+// at all is treated as absent and contributes nothing to the complement,
+// rather than the complement ambiguously claiming the other version's entire
+// address space.
+func (set *IPSet) Complement() *IPSet {
+	if set == nil {
+		return &IPSet{}
+	}
+	var result []ipSetRange
+	for _, version := range [...]IPVersion{IPv4, IPv6} {
+		block := set.block(version)
+		if len(block) == 0 {
+			continue
+		}
+		full := &IPSet{ranges: []ipSetRange{{big.NewInt(0), maxValueForVersion(version), version}}}
+		if version == IPv6 {
+			full.v4Count = 0
+		} else {
+			full.v4Count = 1
+		}
+		for _, r := range block {
+			full = full.difference(r)
+		}
+		result = append(result, full.ranges...)
+	}
+	return newIPSet(result)
+}
+
+func addrValue(addr *IPAddress) *big.Int {
+	return new(big.Int).SetBytes(addr.GetBytes())
+}
+
+func valueToAddr(v *big.Int, version IPVersion) *IPAddress {
+	byteCount := IPv4ByteCount
+	if version == IPv6 {
+		byteCount = IPv6ByteCount
+	}
+	bytes := make([]byte, byteCount)
+	v.FillBytes(bytes)
+	if version == IPv6 {
+		addr, _ := NewIPv6AddressFromIP(bytes)
+		return addr.ToIPAddress()
+	}
+	addr, _ := NewIPv4AddressFromIP(bytes)
+	return addr.ToIPAddress()
+}
+
+func ipVersionBitCount(version IPVersion) int {
+	if version == IPv6 {
+		return IPv6BitCount
+	}
+	return IPv4BitCount
+}
+
+func maxValueForVersion(version IPVersion) *big.Int {
+	return new(big.Int).Sub(new(big.Int).Lsh(bigOne(), uint(ipVersionBitCount(version))), bigOne())
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+func minBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
@@ -218,6 +218,15 @@ func (addr *IPv4Address) CopyUpperBytes(bytes net.IP) net.IP {
 	return addr.init().section.CopyUpperBytes(bytes)
 }
 
+func (addr *IPv4Address) ToZeroHost() (*IPv4Address, IncompatibleAddressException) {
+	res, err := addr.init().toZeroHost()
+	return res.ToIPv4Address(), err
+}
+
+func (addr *IPv4Address) ContainsSinglePrefixBlock(prefixLen BitCount) bool {
+	return addr.init().ipAddressInternal.ContainsSinglePrefixBlock(prefixLen)
+}
+
 func (addr *IPv4Address) ToSequentialRange() *IPv4AddressSeqRange {
 	if addr == nil {
 		return nil
@@ -0,0 +1,196 @@
+package ipaddr
+
+import "github.com/seancfoley/ipaddress/ipaddress-go/ipaddr/debug"
+
+// ipv4MappedPrefix is the 96-bit ::ffff:0:0/96 prefix used to embed an IPv4
+// address inside an IPv6 address, as defined by RFC 4291 section 2.5.5.2.
+var ipv4MappedPrefix = [12]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff}
+
+// ipv4CompatPrefix is the 96-bit ::/96 prefix, with all-zero marker bits,
+// used by the deprecated IPv4-compatible IPv6 form of RFC 4291 section
+// 2.5.5.1, eg ::1.2.3.4.
+var ipv4CompatPrefix = [12]byte{}
+
+// ToIPv4MappedIPv6 returns the IPv4-mapped IPv6 address ::ffff:a.b.c.d
+// corresponding to this IPv4 address.
+func (addr *IPv4Address) ToIPv4MappedIPv6() *IPv6Address {
+	addr = addr.init()
+	bytes := make([]byte, 0, IPv6ByteCount)
+	bytes = append(bytes, ipv4MappedPrefix[:]...)
+	bytes = addr.CopyBytes(bytes)
+	mapped, _ := NewIPv6AddressFromIP(bytes)
+	return mapped
+}
+
+// IsIPv4Mapped returns whether this address is an IPv4-mapped IPv6 address, ie
+// one of the form ::ffff:0:0/96, which wraps an embedded IPv4 address.
+func (addr *IPv6Address) IsIPv4Mapped() bool {
+	addr = addr.init()
+	bytes := addr.GetBytes()
+	for i, b := range ipv4MappedPrefix {
+		if bytes[i] != b {
+			return false
+		}
+	}
+	upperBytes := addr.GetUpperBytes()
+	for i, b := range ipv4MappedPrefix {
+		if upperBytes[i] != b {
+			return false
+		}
+	}
+	return true
+}
+
+// IsIPv4Convertible returns whether this address should be treated as holding
+// an embedded IPv4 address, per the ipv4mapped debug knob: the default
+// "mapped" mode, the "strict" mode and any other value besides "compat"
+// recognize only IsIPv4Mapped addresses; "compat" additionally recognizes
+// the deprecated IPv4-compatible form (::a.b.c.d, excluding :: and ::1).
+func (addr *IPv6Address) IsIPv4Convertible() bool {
+	addr = addr.init()
+	if addr.IsIPv4Mapped() {
+		return true
+	}
+	if debug.IPv4MappedMode() != "compat" {
+		return false
+	}
+	return addr.isIPv4Compatible()
+}
+
+// isIPv4Compatible reports whether addr is a deprecated IPv4-compatible IPv6
+// address (::a.b.c.d), excluding the unspecified (::) and loopback (::1)
+// addresses, which share the same ::/96 prefix but do not represent an
+// embedded IPv4 address.
+func (addr *IPv6Address) isIPv4Compatible() bool {
+	return isIPv4CompatibleBytes(addr.GetBytes()) && isIPv4CompatibleBytes(addr.GetUpperBytes())
+}
+
+// isIPv4CompatibleBytes reports whether a single 16-byte address (lower or
+// upper bound) falls in the deprecated IPv4-compatible ::/96 range,
+// excluding :: and ::1.
+func isIPv4CompatibleBytes(bytes []byte) bool {
+	for i, b := range ipv4CompatPrefix {
+		if bytes[i] != b {
+			return false
+		}
+	}
+	embedded := bytes[12:]
+	return !(embedded[0] == 0 && embedded[1] == 0 && embedded[2] == 0 && (embedded[3] == 0 || embedded[3] == 1))
+}
+
+// IsIPv4Compatible reports whether addr is a deprecated IPv4-compatible IPv6
+// address (::a.b.c.d, RFC 4291 section 2.5.5.1), excluding the unspecified
+// (::) and loopback (::1) addresses. It is tri-valued: if addr is a
+// multi-valued range whose lower bound is IPv4-compatible and whose upper
+// bound is not (or vice versa), it reports TriUnknown rather than guessing
+// from one bound alone.
+func (addr *IPv6Address) IsIPv4Compatible() Tribool {
+	addr = addr.init()
+	lowerIn := isIPv4CompatibleBytes(addr.GetBytes())
+	upperIn := isIPv4CompatibleBytes(addr.GetUpperBytes())
+	switch {
+	case lowerIn && upperIn:
+		return TriTrue
+	case !lowerIn && !upperIn:
+		return TriFalse
+	}
+	return TriUnknown
+}
+
+// IsIPv4Compatible is the *IPAddress counterpart of
+// (*IPv6Address).IsIPv4Compatible; always TriFalse for an IPv4 address.
+func (addr *IPAddress) IsIPv4Compatible() Tribool {
+	if addr == nil {
+		return TriFalse
+	}
+	addr = addr.init()
+	if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+		return ipv6Addr.IsIPv4Compatible()
+	}
+	return TriFalse
+}
+
+// IsIPv4Compatible is the *IPv4Address counterpart of
+// (*IPv6Address).IsIPv4Compatible; always TriFalse, since an IPv4 address is
+// never itself an IPv4-compatible IPv6 address.
+func (addr *IPv4Address) IsIPv4Compatible() Tribool {
+	return TriFalse
+}
+
+// To4In6Mapped returns the embedded IPv4 address if addr is an IPv4-mapped
+// IPv6 address (IsIPv4Mapped), or nil otherwise.
+func (addr *IPv6Address) To4In6Mapped() *IPv4Address {
+	addr = addr.init()
+	if !addr.IsIPv4Mapped() {
+		return nil
+	}
+	ipv4Addr, err := NewIPv4AddressFromIP(addr.GetBytes()[12:])
+	if err != nil {
+		return nil
+	}
+	return ipv4Addr
+}
+
+// To4In6Mapped is the *IPAddress counterpart of
+// (*IPv6Address).To4In6Mapped; it returns nil for an IPv4 address.
+func (addr *IPAddress) To4In6Mapped() *IPv4Address {
+	if addr == nil {
+		return nil
+	}
+	addr = addr.init()
+	if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+		return ipv6Addr.To4In6Mapped()
+	}
+	return nil
+}
+
+// ToCanonical is an alias for Unmap, kept for parity with the naming used by
+// Rust's std::net::IpAddr::to_canonical and Python's
+// ipaddress.IPv6Address.ipv4_mapped: it collapses an IPv4-mapped IPv6
+// address to its embedded IPv4 form, and returns every other address
+// unchanged.
+func (addr *IPv6Address) ToCanonical() *IPAddress {
+	return addr.Unmap()
+}
+
+// ToCanonical is the *IPAddress counterpart of (*IPv6Address).ToCanonical.
+func (addr *IPAddress) ToCanonical() *IPAddress {
+	return addr.Unmap()
+}
+
+// Is4In6 reports whether this address is an IPv4-mapped IPv6 address.  It is an
+// alias for IsIPv4Mapped kept for parity with the naming used by net/netip's
+// Addr.Is4In6.
+func (addr *IPv6Address) Is4In6() bool {
+	return addr.IsIPv4Mapped()
+}
+
+// Unmap returns the embedded IPv4 address if this is an IPv4-mapped IPv6
+// address (IsIPv4Mapped returns true), matching the semantics of
+// net/netip's Addr.Unmap.  Otherwise, it returns this address unchanged,
+// wrapped as an IPAddress.
+func (addr *IPv6Address) Unmap() *IPAddress {
+	addr = addr.init()
+	if !addr.IsIPv4Mapped() {
+		return addr.ToIPAddress()
+	}
+	ipv4Addr, err := NewIPv4AddressFromIP(addr.GetBytes()[12:])
+	if err != nil {
+		return addr.ToIPAddress()
+	}
+	return ipv4Addr.ToIPAddress()
+}
+
+// Unmap returns the embedded IPv4 address if this address is an IPv4-mapped
+// IPv6 address, or this address unchanged otherwise.  For an IPv4 address it
+// always returns the receiver.
+func (addr *IPAddress) Unmap() *IPAddress {
+	if addr == nil {
+		return nil
+	}
+	addr = addr.init()
+	if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+		return ipv6Addr.Unmap()
+	}
+	return addr
+}
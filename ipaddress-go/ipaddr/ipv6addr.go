@@ -45,6 +45,7 @@ func NewIPv6Address(section *IPv6AddressSection) *IPv6Address {
 }
 
 func NewIPv6AddressZoned(section *IPv6AddressSection, zone Zone) *IPv6Address {
+	zone = canonicalZone(zone)
 	result := createAddress(section.ToAddressSection(), zone).ToIPv6Address()
 	if zone != noZone {
 		result.cache.stringCache = &stringCache{}
@@ -71,7 +72,7 @@ func NewIPv6AddressFromPrefixedIP(bytes []byte, prefixLength PrefixLen) (addr *I
 func NewIPv6AddressFromIPAddr(ipAddr net.IPAddr) (addr *IPv6Address, err AddressValueException) {
 	addr, err = NewIPv6AddressFromIP(ipAddr.IP)
 	if err == nil {
-		addr.zone = Zone(ipAddr.Zone)
+		addr.zone = canonicalZone(Zone(ipAddr.Zone))
 	}
 	return
 }
@@ -115,8 +116,6 @@ func initZeroIPv6() *IPv6Address {
 	return NewIPv6Address(section)
 }
 
-//
-//
 // IPv6Address is an IPv6 address, or a subnet of multiple IPv6 addresses.  Each segment can represent a single value or a range of values.
 // The zero value is ::
 type IPv6Address struct {
@@ -150,6 +149,18 @@ func (addr *IPv6Address) HasZone() bool {
 	return addr.zone != noZone
 }
 
+// ZoneStr returns the zone identifier as a string, or the empty string if
+// this address has no zone.
+func (addr *IPv6Address) ZoneStr() string {
+	return string(addr.init().zone)
+}
+
+// GetZone returns the address's zone identifier as a Zone, or noZone if this
+// address has no zone.
+func (addr *IPv6Address) GetZone() Zone {
+	return addr.init().zone
+}
+
 func (addr *IPv6Address) GetSection() *IPv6AddressSection {
 	return addr.init().section.ToIPv6AddressSection()
 }
@@ -160,8 +171,8 @@ func (addr *IPv6Address) GetTrailingSection(index int) *IPv6AddressSection {
 	return addr.GetSection().GetTrailingSection(index)
 }
 
-//// Gets the subsection from the series starting from the given index and ending just before the give endIndex
-//// The first segment is at index 0.
+// // Gets the subsection from the series starting from the given index and ending just before the give endIndex
+// // The first segment is at index 0.
 func (addr *IPv6Address) GetSubSection(index, endIndex int) *IPv6AddressSection {
 	return addr.GetSection().GetSubSection(index, endIndex)
 }
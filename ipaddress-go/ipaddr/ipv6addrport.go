@@ -0,0 +1,98 @@
+package ipaddr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IPv6AddressPort pairs an *IPv6Address with a port number, following the
+// model of netip.AddrPort but specialized to IPv6 so the zone identifier
+// always has an unambiguous bracketed home, eg "[2001:db8::1%eth0]:443".
+type IPv6AddressPort struct {
+	addr *IPv6Address
+	port uint16
+}
+
+// NewIPv6AddressPort pairs addr and port into an IPv6AddressPort.
+func NewIPv6AddressPort(addr *IPv6Address, port uint16) IPv6AddressPort {
+	return IPv6AddressPort{addr: addr, port: port}
+}
+
+// Address returns the address half of ap.
+func (ap IPv6AddressPort) Address() *IPv6Address {
+	return ap.addr
+}
+
+// Port returns the port half of ap.
+func (ap IPv6AddressPort) Port() uint16 {
+	return ap.port
+}
+
+// IsValid reports whether ap holds a non-nil address.
+func (ap IPv6AddressPort) IsValid() bool {
+	return ap.addr != nil
+}
+
+// String renders ap in the canonical bracketed form with zone, eg
+// "[2001:db8::1%eth0]:443", the same form accepted by ParseIPv6AddressPort.
+func (ap IPv6AddressPort) String() string {
+	if !ap.IsValid() {
+		return ""
+	}
+	base := ap.addr.WithoutZone().ToCanonicalString()
+	if ap.addr.HasZone() {
+		base += string(IPv6ZoneSeparator) + string(ap.addr.GetZone())
+	}
+	return "[" + base + "]:" + strconv.Itoa(int(ap.port))
+}
+
+// MarshalText implements encoding.TextMarshaler, using the same form as
+// String.
+func (ap IPv6AddressPort) MarshalText() ([]byte, error) {
+	if !ap.IsValid() {
+		return []byte{}, nil
+	}
+	return []byte(ap.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, using ParseIPv6AddressPort.
+func (ap *IPv6AddressPort) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*ap = IPv6AddressPort{}
+		return nil
+	}
+	parsed, err := ParseIPv6AddressPort(string(text))
+	if err != nil {
+		return err
+	}
+	*ap = parsed
+	return nil
+}
+
+// ParseIPv6AddressPort parses s, a bracketed "[address%zone]:port" string
+// such as "[2001:db8::1]:443" or "[2001:db8::1%eth0]:443", into an
+// IPv6AddressPort. The brackets are mandatory: an unbracketed
+// "address:port" string is ambiguous, since the address itself is
+// colon-separated, and is rejected.
+func ParseIPv6AddressPort(s string) (IPv6AddressPort, AddressStringException) {
+	if !strings.HasPrefix(s, "[") {
+		return IPv6AddressPort{}, &addressStringError{addressError: addressError{key: "ipaddress.error.invalidHostPort"}}
+	}
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return IPv6AddressPort{}, &addressStringError{addressError: addressError{key: "ipaddress.error.invalidHostPort"}}
+	}
+	addr, err := ParseZonedIPv6Address(s[1:end])
+	if err != nil {
+		return IPv6AddressPort{}, err
+	}
+	rest := s[end+1:]
+	if !strings.HasPrefix(rest, ":") {
+		return IPv6AddressPort{}, &addressStringError{addressError: addressError{key: "ipaddress.error.invalidHostPort"}}
+	}
+	portNum, convErr := strconv.Atoi(rest[1:])
+	if convErr != nil || portNum < 0 || portNum > 65535 {
+		return IPv6AddressPort{}, &addressStringError{addressError: addressError{key: "ipaddress.error.invalidPort"}}
+	}
+	return IPv6AddressPort{addr: addr, port: uint16(portNum)}, nil
+}
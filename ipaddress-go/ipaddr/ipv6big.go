@@ -0,0 +1,69 @@
+package ipaddr
+
+import "math/big"
+
+// GetValueBig is an alias for GetValue, named to match the big.Int-based
+// arithmetic API (AddBig, SubtractBig, CompareBig, NewIPv6AddressFromBig).
+func (addr *IPv6Address) GetValueBig() *big.Int {
+	return addr.GetValue()
+}
+
+// GetUpperValueBig is an alias for GetUpperValue, named to match the
+// big.Int-based arithmetic API.
+func (addr *IPv6Address) GetUpperValueBig() *big.Int {
+	return addr.GetUpperValue()
+}
+
+// NewIPv6AddressFromBig creates an IPv6Address from a 128-bit unsigned
+// value.  It returns an error if value is negative or does not fit in 128
+// bits.
+func NewIPv6AddressFromBig(value *big.Int) (*IPv6Address, AddressValueException) {
+	if value.Sign() < 0 || value.BitLen() > IPv6BitCount {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+	}
+	bytes := make([]byte, IPv6ByteCount)
+	value.FillBytes(bytes)
+	return NewIPv6AddressFromIP(bytes)
+}
+
+// AddBig returns the address that is value addresses higher than this one,
+// taking the fast path of native int64 arithmetic (via Increment) when this
+// address's full range fits in the low 64 bits and value fits in an int64,
+// and falling back
+// to big.Int otherwise.  It returns nil on overflow past
+// ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff.
+func (addr *IPv6Address) AddBig(value *big.Int) *IPv6Address {
+	addr = addr.init()
+	if fits64(addr) && value.IsInt64() {
+		return addr.Increment(value.Int64())
+	}
+	return addr.IncrementBig(value)
+}
+
+// SubtractBig returns the address that is value addresses lower than this
+// one.  It returns nil on underflow below ::.
+func (addr *IPv6Address) SubtractBig(value *big.Int) *IPv6Address {
+	return addr.AddBig(new(big.Int).Neg(value))
+}
+
+// CompareBig compares the numeric value of this address's lower boundary
+// against value, returning -1, 0, or 1.
+func (addr *IPv6Address) CompareBig(value *big.Int) int {
+	return addr.init().GetValue().Cmp(value)
+}
+
+// Count returns the number of addresses represented by this subnet or
+// range, as a big.Int since an IPv6 /0 has 2^128 addresses, far beyond the
+// range of any fixed-width integer.
+func (addr *IPv6Address) Count() *big.Int {
+	addr = addr.init()
+	count := new(big.Int).Sub(addr.GetUpperValue(), addr.GetValue())
+	return count.Add(count, bigOne())
+}
+
+// fits64 returns whether addr's full range fits within the low 64 bits, ie
+// its upper 64 bits are all zero.
+func fits64(addr *IPv6Address) bool {
+	upper := addr.GetUpperValue()
+	return upper.BitLen() <= 64
+}
@@ -0,0 +1,126 @@
+package ipaddr
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestGetValueBigRoundTrip parses each address, fetches its big.Int value,
+// and checks it against an independently big.Int-parsed hex string, mirroring
+// the existing testIPv6Strings table's singleHex column.
+func TestGetValueBigRoundTrip(t *testing.T) {
+	tests := []struct {
+		addr string
+		hex  string
+	}{
+		{"::", "0"},
+		{"::1", "1"},
+		{"2001:db8::1", "20010db8000000000000000000000001"},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "ffffffffffffffffffffffffffffffff"},
+	}
+	for _, tt := range tests {
+		addr, err := NewIPAddressString(tt.addr).ToAddress()
+		if err != nil {
+			t.Fatalf("%q: failed to parse: %v", tt.addr, err)
+		}
+		want, ok := new(big.Int).SetString(tt.hex, 16)
+		if !ok {
+			t.Fatalf("%q: bad test hex %q", tt.addr, tt.hex)
+		}
+		got := addr.ToIPv6Address().GetValueBig()
+		if got.Cmp(want) != 0 {
+			t.Errorf("%q: GetValueBig got %x, want %x", tt.addr, got, want)
+		}
+	}
+}
+
+// TestNewIPv6AddressFromBig checks that NewIPv6AddressFromBig constructs the
+// expected address, and rejects negative and over-wide values.
+func TestNewIPv6AddressFromBig(t *testing.T) {
+	value, _ := new(big.Int).SetString("20010db8000000000000000000000001", 16)
+	addr, err := NewIPv6AddressFromBig(value)
+	if err != nil {
+		t.Fatalf("NewIPv6AddressFromBig: %v", err)
+	}
+	if want := "2001:db8::1"; addr.ToCanonicalString() != want {
+		t.Errorf("got %q, want %q", addr.ToCanonicalString(), want)
+	}
+
+	if _, err := NewIPv6AddressFromBig(big.NewInt(-1)); err == nil {
+		t.Error("negative value: expected an error, got none")
+	}
+	tooWide := new(big.Int).Lsh(big.NewInt(1), IPv6BitCount)
+	if _, err := NewIPv6AddressFromBig(tooWide); err == nil {
+		t.Error("2^128: expected an error, got none")
+	}
+}
+
+// TestAddSubtractBig checks AddBig/SubtractBig round trips both with a small
+// value and with a value too wide for an int64.  2001:db8::1 does not fit
+// in the low 64 bits, so both use the big.Int path.
+func TestAddSubtractBig(t *testing.T) {
+	addr, err := NewIPAddressString("2001:db8::1").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	ipv6 := addr.ToIPv6Address()
+
+	added := ipv6.AddBig(big.NewInt(5))
+	if want := "2001:db8::6"; added.ToCanonicalString() != want {
+		t.Errorf("AddBig(5): got %q, want %q", added.ToCanonicalString(), want)
+	}
+	back := added.SubtractBig(big.NewInt(5))
+	if back.ToCanonicalString() != ipv6.ToCanonicalString() {
+		t.Errorf("SubtractBig(5) after AddBig(5): got %q, want %q", back.ToCanonicalString(), ipv6.ToCanonicalString())
+	}
+
+	huge := new(big.Int).Lsh(big.NewInt(1), 100) // forces the big.Int fallback, well past int64
+	addedHuge := ipv6.AddBig(huge)
+	if addedHuge == nil {
+		t.Fatal("AddBig with a 100-bit increment: expected a result, got nil")
+	}
+	backHuge := addedHuge.SubtractBig(huge)
+	if backHuge.ToCanonicalString() != ipv6.ToCanonicalString() {
+		t.Errorf("SubtractBig after AddBig with a 100-bit increment: got %q, want %q", backHuge.ToCanonicalString(), ipv6.ToCanonicalString())
+	}
+}
+
+// TestCompareBig checks CompareBig's three-way comparison against this
+// address's lower boundary.
+func TestCompareBig(t *testing.T) {
+	addr, err := NewIPAddressString("2001:db8::10").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	ipv6 := addr.ToIPv6Address()
+	value := ipv6.GetValueBig()
+
+	if got := ipv6.CompareBig(value); got != 0 {
+		t.Errorf("CompareBig(self): got %d, want 0", got)
+	}
+	if got := ipv6.CompareBig(new(big.Int).Add(value, big.NewInt(1))); got >= 0 {
+		t.Errorf("CompareBig(self+1): got %d, want negative", got)
+	}
+	if got := ipv6.CompareBig(new(big.Int).Sub(value, big.NewInt(1))); got <= 0 {
+		t.Errorf("CompareBig(self-1): got %d, want positive", got)
+	}
+}
+
+// TestIPv6Count checks Count for a single address and for a small subnet.
+func TestIPv6Count(t *testing.T) {
+	single, err := NewIPAddressString("::1").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if got := single.ToIPv6Address().Count(); got.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("Count of a single address: got %v, want 1", got)
+	}
+
+	subnet, err := NewIPAddressString("2001:db8::/120").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if got := subnet.ToIPv6Address().Count(); got.Cmp(big.NewInt(256)) != 0 {
+		t.Errorf("Count of a /120: got %v, want 256", got)
+	}
+}
@@ -0,0 +1,22 @@
+package ipaddr
+
+import "fmt"
+
+// ToExpandedString renders this address as eight colon-separated groups of
+// exactly four lowercase hex digits, with no "::" zero-compression and no
+// leading-zero suppression, eg "2001:0db8:0000:0000:0000:0000:0000:0001".
+// This is useful for golden-string comparisons and for contexts, such as
+// fixed-width log fields, where a stable, unambiguous width matters more than
+// brevity.
+func (addr *IPv6Address) ToExpandedString() string {
+	addr = addr.init()
+	segs := addr.GetSegments()
+	result := make([]byte, 0, IPv6SegmentCount*5-1)
+	for i, seg := range segs {
+		if i > 0 {
+			result = append(result, IPv6SegmentSeparator)
+		}
+		result = append(result, []byte(fmt.Sprintf("%04x", seg.GetSegmentValue()))...)
+	}
+	return string(result)
+}
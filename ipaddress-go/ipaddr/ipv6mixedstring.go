@@ -0,0 +1,48 @@
+package ipaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToMixedString renders this address with its final
+// IPv6MixedReplacedSegmentCount segments (the trailing 32 bits) written as a
+// dotted-quad IPv4 address, and the leading IPv6MixedOriginalSegmentCount
+// segments written in lowercase hex without leading zeros, eg
+// "0:0:0:0:0:ffff:192.0.2.1" or "64:ff9b:0:0:0:0:192.0.2.33".  The leading
+// segments are not zero-compressed: no "::" is ever produced.
+func (addr *IPv6Address) ToMixedString() string {
+	addr = addr.init()
+	segs := addr.GetSegments()
+	var b strings.Builder
+	for i := 0; i < IPv6MixedOriginalSegmentCount; i++ {
+		if i > 0 {
+			b.WriteByte(IPv6SegmentSeparator)
+		}
+		b.WriteString(fmt.Sprintf("%x", segs[i].GetSegmentValue()))
+	}
+	b.WriteByte(IPv6SegmentSeparator)
+	for i := IPv6MixedOriginalSegmentCount; i < IPv6SegmentCount; i++ {
+		seg := segs[i].GetSegmentValue()
+		if i > IPv6MixedOriginalSegmentCount {
+			b.WriteByte('.')
+		}
+		b.WriteString(fmt.Sprintf("%d.%d", seg>>8, seg&0xff))
+	}
+	return b.String()
+}
+
+// ToUNCString renders this address in the Windows UNC path host form
+// accepted by the ".ipv6-literal.net" pseudo-domain: each ':' is replaced by
+// '-', any zone separator '%' is replaced by 's', and IPv6UncSuffix is
+// appended, eg "2001-db8--1.ipv6-literal.net" or
+// "fe80--1seth0.ipv6-literal.net".
+func (addr *IPv6Address) ToUNCString() string {
+	addr = addr.init()
+	host := addr.WithoutZone().ToCanonicalString()
+	host = strings.ReplaceAll(host, string(IPv6SegmentSeparator), "-")
+	if addr.HasZone() {
+		host += "s" + string(addr.GetZone())
+	}
+	return host + IPv6UncSuffix
+}
@@ -0,0 +1,325 @@
+package ipaddr
+
+import "sync"
+
+// IPAddressKey is a fixed-size, comparable value type that uniquely identifies a
+// single IPAddress (version, address bits, zone, and prefix length).  Unlike
+// IPAddress itself, which is pointer-heavy internally to support ranges and large
+// groupings, IPAddressKey is plain data and supports == and can be used directly
+// as a map key, eg map[IPAddressKey]V, without requiring a custom hash function.
+//
+// An IPAddressKey does not retain range information; ToKey on a multi-valued
+// address (a subnet with a range or wildcard segment) returns a key for the
+// lower bound of that address.
+type IPAddressKey struct {
+	lo, hi  uint64 // the address bits, hi unused for IPv4
+	zone    *string
+	version IPVersion
+	prefLen PrefixLen
+}
+
+// internedZones allows equal zone strings to share a single backing string,
+// so that two keys built from equal zones are == without a string compare.
+var internedZones sync.Map // map[string]*string
+
+func internZone(zone Zone) *string {
+	if zone == noZone {
+		return nil
+	}
+	s := string(zone)
+	if existing, ok := internedZones.Load(s); ok {
+		return existing.(*string)
+	}
+	actual, _ := internedZones.LoadOrStore(s, &s)
+	return actual.(*string)
+}
+
+// ToKey returns a comparable IPAddressKey representing the lower bound of this
+// address, suitable for use as a map key.
+func (addr *IPAddress) ToKey() IPAddressKey {
+	if addr == nil {
+		return IPAddressKey{}
+	}
+	addr = addr.init().GetLower()
+	key := IPAddressKey{
+		version: addr.GetIPVersion(),
+		prefLen: addr.GetNetworkPrefixLength(),
+	}
+	if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+		bytes := ipv6Addr.GetBytes()
+		key.hi = beUint64(bytes[:8])
+		key.lo = beUint64(bytes[8:])
+		key.zone = internZone(ipv6Addr.zone)
+	} else if ipv4Addr := addr.ToIPv4Address(); ipv4Addr != nil {
+		bytes := ipv4Addr.GetBytes()
+		key.lo = uint64(beUint32(bytes))
+	}
+	return key
+}
+
+// ToAddress reverses ToKey, reconstructing the IPAddress represented by this key.
+// The zero value IPAddressKey converts to nil.
+func (key IPAddressKey) ToAddress() *IPAddress {
+	switch key.version {
+	case IPv4:
+		var bytes [IPv4ByteCount]byte
+		putBeUint32(bytes[:], uint32(key.lo))
+		addr, err := NewIPv4AddressFromIP(bytes[:])
+		if err != nil {
+			return nil
+		}
+		ipAddr := addr.ToIPAddress()
+		if key.prefLen != nil {
+			ipAddr = ipAddr.SetPrefixLen(key.prefLen.Len())
+		}
+		return ipAddr
+	case IPv6:
+		var bytes [IPv6ByteCount]byte
+		putBeUint64(bytes[:8], key.hi)
+		putBeUint64(bytes[8:], key.lo)
+		addr, err := NewIPv6AddressFromIP(bytes[:])
+		if err != nil {
+			return nil
+		}
+		if key.zone != nil {
+			addr.zone = Zone(*key.zone)
+		}
+		ipAddr := addr.ToIPAddress()
+		if key.prefLen != nil {
+			ipAddr = ipAddr.SetPrefixLen(key.prefLen.Len())
+		}
+		return ipAddr
+	}
+	return nil
+}
+
+// addressKeyKind identifies the address family an AddressKey holds, since
+// Address (unlike IPAddress) carries no GetIPVersion of its own and can also
+// represent a MACAddress.
+type addressKeyKind uint8
+
+const (
+	addressKeyKindIPv4 addressKeyKind = iota + 1
+	addressKeyKindIPv6
+	addressKeyKindMAC
+)
+
+// AddressKey is the Address counterpart of IPAddressKey: a fixed-size,
+// comparable value type identifying a single Address (any of IPv4, IPv6, or
+// MAC), usable directly as a map key without a custom hash function. As
+// with IPAddressKey, it shares the same zone intern table, so two
+// IPv6-zoned AddressKey and IPAddressKey values built from equal zones
+// still compare their zone pointers rather than the zone text.
+//
+// An AddressKey does not retain range information; ToKey on a multi-valued
+// address returns a key for the lower bound of that address.
+type AddressKey struct {
+	lo, hi  uint64 // the address bits, hi unused for IPv4 and for 48-bit MAC
+	zone    *string
+	kind    addressKeyKind
+	bitLen  BitCount
+	prefLen PrefixLen
+}
+
+// ToKey returns a comparable AddressKey representing the lower bound of
+// this address, suitable for use as a map key.
+func (addr *Address) ToKey() AddressKey {
+	if addr == nil {
+		return AddressKey{}
+	}
+	addr = addr.init().GetLower()
+	key := AddressKey{
+		bitLen:  addr.section.GetBitCount(),
+		prefLen: addr.section.GetPrefixLen(),
+	}
+	bytes := addr.getBytes()
+	switch {
+	case addr.section.matchesIPv6Address():
+		key.kind = addressKeyKindIPv6
+		key.hi = beUint64(bytes[:8])
+		key.lo = beUint64(bytes[8:])
+		if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+			key.zone = internZone(ipv6Addr.zone)
+		}
+	case addr.section.matchesIPv4Address():
+		key.kind = addressKeyKindIPv4
+		key.lo = uint64(beUint32(bytes))
+	case addr.section.matchesMACAddress():
+		key.kind = addressKeyKindMAC
+		key.lo = beUintN(bytes)
+	}
+	return key
+}
+
+// ToAddress reverses ToKey, reconstructing the Address represented by this
+// key. The zero value AddressKey converts to nil.
+func (key AddressKey) ToAddress() *Address {
+	switch key.kind {
+	case addressKeyKindIPv4:
+		var bytes [IPv4ByteCount]byte
+		putBeUint32(bytes[:], uint32(key.lo))
+		addr, err := NewIPv4AddressFromIP(bytes[:])
+		if err != nil {
+			return nil
+		}
+		result := addr.ToAddress()
+		if key.prefLen != nil {
+			result = addr.ToIPAddress().SetPrefixLen(key.prefLen.Len()).ToAddress()
+		}
+		return result
+	case addressKeyKindIPv6:
+		var bytes [IPv6ByteCount]byte
+		putBeUint64(bytes[:8], key.hi)
+		putBeUint64(bytes[8:], key.lo)
+		addr, err := NewIPv6AddressFromIP(bytes[:])
+		if err != nil {
+			return nil
+		}
+		if key.zone != nil {
+			addr.zone = Zone(*key.zone)
+		}
+		result := addr.ToAddress()
+		if key.prefLen != nil {
+			result = addr.ToIPAddress().SetPrefixLen(key.prefLen.Len()).ToAddress()
+		}
+		return result
+	case addressKeyKindMAC:
+		segCount := int(key.bitLen / 8)
+		bytes := make([]byte, segCount)
+		putBeUintN(bytes, key.lo)
+		section, err := NewMACSectionFromBytes(bytes, segCount)
+		if err != nil {
+			return nil
+		}
+		if key.prefLen != nil {
+			section = section.SetPrefixLen(key.prefLen.Len())
+		}
+		return NewMACAddress(section).ToAddress()
+	}
+	return nil
+}
+
+// Is4 reports whether key holds an IPv4 address.
+func (key AddressKey) Is4() bool {
+	return key.kind == addressKeyKindIPv4
+}
+
+// Is6 reports whether key holds an IPv6 address.
+func (key AddressKey) Is6() bool {
+	return key.kind == addressKeyKindIPv6
+}
+
+// IsMAC reports whether key holds a MAC address.
+func (key AddressKey) IsMAC() bool {
+	return key.kind == addressKeyKindMAC
+}
+
+// BitLen returns the bit length of the address key holds: 32 for IPv4, 128
+// for IPv6, or 48/64 for MAC.
+func (key AddressKey) BitLen() BitCount {
+	return key.bitLen
+}
+
+// Compare returns -1, 0, or 1 depending on whether key sorts before, equal
+// to, or after other, ordering first by kind (IPv4, then IPv6, then MAC,
+// matching addressKeyKind's declaration order) and then by address value.
+func (key AddressKey) Compare(other AddressKey) int {
+	if key.kind != other.kind {
+		if key.kind < other.kind {
+			return -1
+		}
+		return 1
+	}
+	if key.hi != other.hi {
+		if key.hi < other.hi {
+			return -1
+		}
+		return 1
+	}
+	if key.lo != other.lo {
+		if key.lo < other.lo {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// Less reports whether key sorts before other, per Compare, making
+// AddressKey usable directly with sort.Slice or slices.SortFunc.
+func (key AddressKey) Less(other AddressKey) bool {
+	return key.Compare(other) < 0
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The
+// binary form is a kind byte, a 1-byte bit length, the address bytes (4, 6,
+// 8, or 16 of them per the bit length), and the zone text if any (IPv6
+// only).
+func (key AddressKey) MarshalBinary() ([]byte, error) {
+	byteLen := int(key.bitLen / 8)
+	out := make([]byte, 0, 2+byteLen+len(zoneOf(key.zone)))
+	out = append(out, byte(key.kind), byte(key.bitLen))
+	switch key.kind {
+	case addressKeyKindIPv4:
+		var bytes [IPv4ByteCount]byte
+		putBeUint32(bytes[:], uint32(key.lo))
+		out = append(out, bytes[:]...)
+	case addressKeyKindIPv6:
+		var bytes [IPv6ByteCount]byte
+		putBeUint64(bytes[:8], key.hi)
+		putBeUint64(bytes[8:], key.lo)
+		out = append(out, bytes[:]...)
+		out = append(out, []byte(zoneOf(key.zone))...)
+	case addressKeyKindMAC:
+		bytes := make([]byte, byteLen)
+		putBeUintN(bytes, key.lo)
+		out = append(out, bytes...)
+	}
+	return out, nil
+}
+
+func zoneOf(zone *string) string {
+	if zone == nil {
+		return ""
+	}
+	return *zone
+}
+
+// beUintN reads up to 8 big-endian bytes (as used by a MAC address's 6 or 8
+// one-byte segments) into the low bits of a uint64.
+func beUintN(b []byte) uint64 {
+	var v uint64
+	for _, x := range b {
+		v = v<<8 | uint64(x)
+	}
+	return v
+}
+
+// putBeUintN writes the low len(b)*8 bits of v into b as big-endian bytes,
+// reversing beUintN.
+func putBeUintN(b []byte, v uint64) {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+}
+
+func beUint32(b []byte) uint32 {
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+func putBeUint32(b []byte, v uint32) {
+	b[0] = byte(v >> 24)
+	b[1] = byte(v >> 16)
+	b[2] = byte(v >> 8)
+	b[3] = byte(v)
+}
+
+func beUint64(b []byte) uint64 {
+	return uint64(beUint32(b[:4]))<<32 | uint64(beUint32(b[4:8]))
+}
+
+func putBeUint64(b []byte, v uint64) {
+	putBeUint32(b[:4], uint32(v>>32))
+	putBeUint32(b[4:8], uint32(v))
+}
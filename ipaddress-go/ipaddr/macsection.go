@@ -2,6 +2,7 @@ package ipaddr
 
 import (
 	"math/big"
+	"math/bits"
 	"unsafe"
 )
 
@@ -310,6 +311,35 @@ var macMaxValues = []uint64{
 	0xffffffffffffff,
 	0xffffffffffffffff}
 
+// macSectionCountMinus1 computes section's value count, minus 1, directly
+// from its segments as a uint64, the MAC-specific counterpart of
+// GetCount().Sub(count, bigOneConst()) that a MAC section (at most
+// ExtendedUniqueIdentifier64SegmentCount segments, 64 bits) never actually
+// needs math/big for. fits is false only when the true count does not fit
+// in a uint64 (ie the section has 8 segments, every one fully ranged),
+// the one case the uint64 fast path below cannot represent.
+func macSectionCountMinus1(section *MACAddressSection) (countMinus1 uint64, fits bool) {
+	segCount := section.GetSegmentCount()
+	countMinus1 = 0
+	for i := 0; i < segCount; i++ {
+		seg := section.GetSegment(i)
+		segCountMinus1 := uint64(seg.GetUpperSegmentValue()) - uint64(seg.GetSegmentValue())
+		if segCountMinus1 == 0 {
+			continue
+		}
+		hi, lo := bits.Mul64(countMinus1+1, segCountMinus1+1)
+		if hi != 0 {
+			return 0, false
+		}
+		newCountMinus1 := lo - 1
+		if newCountMinus1 < countMinus1 {
+			return 0, false
+		}
+		countMinus1 = newCountMinus1
+	}
+	return countMinus1, true
+}
+
 func (section *MACAddressSection) Increment(incrementVal int64) *MACAddressSection {
 	if incrementVal == 0 && !section.IsMultiple() {
 		return section
@@ -317,9 +347,28 @@ func (section *MACAddressSection) Increment(incrementVal int64) *MACAddressSecti
 	segCount := section.GetSegmentCount()
 	lowerValue := section.Uint64Value()
 	upperValue := section.UpperUint64Value()
-	count := section.GetCount()
-	countMinus1 := count.Sub(count, bigOneConst()).Uint64()
-	isOverflow := checkOverflow(incrementVal, lowerValue, upperValue, countMinus1, getMacMaxValueLong(segCount))
+	maxValue := getMacMaxValueLong(segCount)
+	if !section.IsMultiple() {
+		// Fast path: a single-valued section's count is always 1, so there is
+		// no need to call GetCount() at all, let alone do math/big
+		// arithmetic on it; the result segments are built directly from the
+		// incremented uint64 value.
+		if checkOverflow(incrementVal, lowerValue, upperValue, 0, maxValue) {
+			return nil
+		}
+		newValue := uint64(int64(lowerValue) + incrementVal)
+		return createSegmentsUint64(
+			newValue,
+			segCount,
+			section.GetPrefixLen(),
+			DefaultMACNetwork.getAddressCreator()).ToMACAddressSection()
+	}
+	countMinus1, fits := macSectionCountMinus1(section)
+	if !fits {
+		count := section.GetCount()
+		countMinus1 = count.Sub(count, bigOneConst()).Uint64()
+	}
+	isOverflow := checkOverflow(incrementVal, lowerValue, upperValue, countMinus1, maxValue)
 	if isOverflow {
 		return nil
 	}
@@ -0,0 +1,34 @@
+package ipaddr
+
+import "testing"
+
+// benchMACSection and benchMACRangeSection back the Increment benchmarks
+// below: the former a single-valued 6-segment MAC section, the latter a
+// section whose last segment is a range, forcing the non-fast-path below.
+var benchMACSection = func() *MACAddressSection {
+	addr, _ := NewMACAddressString("01:02:03:04:05:06").ToAddress()
+	return addr.GetSection()
+}()
+
+var benchMACRangeSection = func() *MACAddressSection {
+	addr, _ := NewMACAddressString("01:02:03:04:05:00-ff").ToAddress()
+	return addr.GetSection()
+}()
+
+// BenchmarkMACSectionIncrement demonstrates the single-valued fast path,
+// which builds its result straight from a uint64 without ever allocating a
+// big.Int.
+func BenchmarkMACSectionIncrement(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchMACSection.Increment(1)
+	}
+}
+
+// BenchmarkMACSectionIncrementRange exercises the multi-valued path, whose
+// count-minus-1 is still computed in uint64 via macSectionCountMinus1 rather
+// than math/big for any MAC section whose count fits in 64 bits.
+func BenchmarkMACSectionIncrementRange(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchMACRangeSection.Increment(1)
+	}
+}
@@ -0,0 +1,522 @@
+package ipaddr
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// MarshalText implements the encoding.TextMarshaler interface.  The text form is
+// the canonical string representation, the same as returned by String().
+func (addr IPAddress) MarshalText() ([]byte, error) {
+	return []byte(addr.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.  It parses text
+// in the same formats accepted by NewIPAddressString.
+func (addr *IPAddress) UnmarshalText(text []byte) error {
+	parsed, err := NewIPAddressString(string(text)).ToAddress()
+	if err != nil {
+		return err
+	}
+	*addr = *parsed
+	return nil
+}
+
+// binary tag byte layout shared by IPAddress, IPv4Address, and IPv6Address:
+// the low two bits hold the IP version, and the remaining bits are flags
+// indicating which optional fields follow the raw address bytes.
+const (
+	marshalTagIPv4 byte = 1
+	marshalTagIPv6 byte = 2
+
+	marshalFlagPrefix byte = 1 << 2
+	marshalFlagZone   byte = 1 << 3
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.  The binary
+// form is a tag byte identifying the IP version and which of a prefix length
+// and zone follow, the raw address bytes (4 or 16), an optional 1-byte prefix
+// length, and an optional zone as a 1-byte length followed by that many bytes,
+// so that a round trip through gob or a wire protocol loses no information.
+func (addr IPAddress) MarshalBinary() ([]byte, error) {
+	a := addr.init()
+	if ipv4Addr := a.ToIPv4Address(); ipv4Addr != nil {
+		return ipv4Addr.MarshalBinary()
+	} else if ipv6Addr := a.ToIPv6Address(); ipv6Addr != nil {
+		return ipv6Addr.MarshalBinary()
+	}
+	return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, reversing
+// MarshalBinary.
+func (addr *IPAddress) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	switch data[0] &^ (marshalFlagPrefix | marshalFlagZone) {
+	case marshalTagIPv4:
+		var ipv4Addr IPv4Address
+		if err := ipv4Addr.UnmarshalBinary(data); err != nil {
+			return err
+		}
+		*addr = *ipv4Addr.ToIPAddress()
+		return nil
+	case marshalTagIPv6:
+		var ipv6Addr IPv6Address
+		if err := ipv6Addr.UnmarshalBinary(data); err != nil {
+			return err
+		}
+		*addr = *ipv6Addr.ToIPAddress()
+		return nil
+	}
+	return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, per
+// IPAddress.MarshalBinary.
+func (addr IPv4Address) MarshalBinary() ([]byte, error) {
+	a := addr.init()
+	tag := marshalTagIPv4
+	prefLen := a.GetNetworkPrefixLength()
+	if prefLen != nil {
+		tag |= marshalFlagPrefix
+	}
+	out := append([]byte{tag}, a.GetBytes()...)
+	if prefLen != nil {
+		out = append(out, byte(prefLen.Len()))
+	}
+	return out, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, reversing
+// MarshalBinary.
+func (addr *IPv4Address) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0]&^marshalFlagPrefix != marshalTagIPv4 {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	hasPrefix := data[0]&marshalFlagPrefix != 0
+	rest := data[1:]
+	wantLen := IPv4ByteCount
+	if hasPrefix {
+		wantLen++
+	}
+	if len(rest) != wantLen {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	var parsed *IPv4Address
+	var err AddressValueException
+	if hasPrefix {
+		prefLen := cacheBitCount(BitCount(rest[IPv4ByteCount]))
+		parsed, err = NewIPv4AddressFromPrefixedIP(rest[:IPv4ByteCount], prefLen)
+	} else {
+		parsed, err = NewIPv4AddressFromIP(rest)
+	}
+	if err != nil {
+		return err
+	}
+	*addr = *parsed
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, per
+// IPAddress.MarshalBinary.
+func (addr IPv6Address) MarshalBinary() ([]byte, error) {
+	a := addr.init()
+	tag := marshalTagIPv6
+	prefLen := a.GetNetworkPrefixLength()
+	if prefLen != nil {
+		tag |= marshalFlagPrefix
+	}
+	if a.HasZone() {
+		tag |= marshalFlagZone
+	}
+	out := append([]byte{tag}, a.GetBytes()...)
+	if prefLen != nil {
+		out = append(out, byte(prefLen.Len()))
+	}
+	if a.HasZone() {
+		zone := []byte(a.zone)
+		out = append(out, byte(len(zone)))
+		out = append(out, zone...)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, reversing
+// MarshalBinary.
+func (addr *IPv6Address) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0]&^(marshalFlagPrefix|marshalFlagZone) != marshalTagIPv6 {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	hasPrefix := data[0]&marshalFlagPrefix != 0
+	hasZone := data[0]&marshalFlagZone != 0
+	rest := data[1:]
+	if len(rest) < IPv6ByteCount {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	ipBytes := rest[:IPv6ByteCount]
+	rest = rest[IPv6ByteCount:]
+	var prefLen PrefixLen
+	if hasPrefix {
+		if len(rest) < 1 {
+			return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		prefLen = cacheBitCount(BitCount(rest[0]))
+		rest = rest[1:]
+	}
+	var zone Zone
+	if hasZone {
+		if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+			return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		zoneLen := int(rest[0])
+		zone = canonicalZone(Zone(rest[1 : 1+zoneLen]))
+		rest = rest[1+zoneLen:]
+	}
+	if len(rest) != 0 {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	parsed, err := NewIPv6AddressFromPrefixedIP(ipBytes, prefLen)
+	if err != nil {
+		return err
+	}
+	if zone != noZone {
+		parsed.zone = zone
+	}
+	*addr = *parsed
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.  The address is encoded as
+// its canonical string, quoted as JSON requires.
+func (addr IPAddress) MarshalJSON() ([]byte, error) {
+	text, err := addr.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%q", text)), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (addr *IPAddress) UnmarshalJSON(b []byte) error {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.json"}}
+	}
+	return addr.UnmarshalText(b[1 : len(b)-1])
+}
+
+// Scan implements the database/sql.Scanner interface, allowing an IPAddress to be
+// populated directly from a database column, eg Postgres inet or cidr.
+func (addr *IPAddress) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*addr = IPAddress{}
+		return nil
+	case string:
+		return addr.UnmarshalText([]byte(v))
+	case []byte:
+		return addr.UnmarshalText(v)
+	default:
+		return fmt.Errorf("ipaddress: cannot scan type %T into IPAddress", src)
+	}
+}
+
+// Value implements the database/sql/driver.Valuer interface.
+func (addr IPAddress) Value() (driver.Value, error) {
+	return addr.String(), nil
+}
+
+// MarshalText implements encoding.TextMarshaler for IPv4Address.
+func (addr IPv4Address) MarshalText() ([]byte, error) {
+	return []byte(addr.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for IPv4Address.
+func (addr *IPv4Address) UnmarshalText(text []byte) error {
+	parsed, err := NewIPAddressString(string(text)).ToAddress()
+	if err != nil {
+		return err
+	}
+	ipv4Addr := parsed.ToIPv4Address()
+	if ipv4Addr == nil {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	}
+	*addr = *ipv4Addr
+	return nil
+}
+
+// Scan implements the database/sql.Scanner interface, allowing an IPv4Address
+// to be populated directly from a database column, eg Postgres inet or cidr.
+func (addr *IPv4Address) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*addr = IPv4Address{}
+		return nil
+	case string:
+		return addr.UnmarshalText([]byte(v))
+	case []byte:
+		return addr.UnmarshalText(v)
+	default:
+		return fmt.Errorf("ipaddress: cannot scan type %T into IPv4Address", src)
+	}
+}
+
+// Value implements the database/sql/driver.Valuer interface.
+func (addr IPv4Address) Value() (driver.Value, error) {
+	return addr.String(), nil
+}
+
+// MarshalText implements encoding.TextMarshaler for IPv6Address.
+func (addr IPv6Address) MarshalText() ([]byte, error) {
+	return []byte(addr.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for IPv6Address.
+func (addr *IPv6Address) UnmarshalText(text []byte) error {
+	parsed, err := NewIPAddressString(string(text)).ToAddress()
+	if err != nil {
+		return err
+	}
+	ipv6Addr := parsed.ToIPv6Address()
+	if ipv6Addr == nil {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	}
+	*addr = *ipv6Addr
+	return nil
+}
+
+// Scan implements the database/sql.Scanner interface, allowing an IPv6Address
+// to be populated directly from a database column, eg Postgres inet or cidr.
+func (addr *IPv6Address) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*addr = IPv6Address{}
+		return nil
+	case string:
+		return addr.UnmarshalText([]byte(v))
+	case []byte:
+		return addr.UnmarshalText(v)
+	default:
+		return fmt.Errorf("ipaddress: cannot scan type %T into IPv6Address", src)
+	}
+}
+
+// Value implements the database/sql/driver.Valuer interface.
+func (addr IPv6Address) Value() (driver.Value, error) {
+	return addr.String(), nil
+}
+
+// MarshalText implements encoding.TextMarshaler for IPAddressSeqRange, encoding
+// the range as "lower-upper".
+func (rng IPAddressSeqRange) MarshalText() ([]byte, error) {
+	return []byte(rng.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for IPAddressSeqRange, parsing
+// the "lower-upper" form produced by MarshalText.
+func (rng *IPAddressSeqRange) UnmarshalText(text []byte) error {
+	parsed, err := NewIPAddressString(string(text)).ToRange()
+	if err != nil {
+		return err
+	}
+	*rng = *parsed
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for IPv4Address.
+func (addr IPv4Address) MarshalJSON() ([]byte, error) {
+	text, err := addr.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%q", text)), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for IPv4Address.
+func (addr *IPv4Address) UnmarshalJSON(b []byte) error {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.json"}}
+	}
+	return addr.UnmarshalText(b[1 : len(b)-1])
+}
+
+// MarshalJSON implements the json.Marshaler interface for IPv6Address.
+func (addr IPv6Address) MarshalJSON() ([]byte, error) {
+	text, err := addr.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%q", text)), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for IPv6Address.
+func (addr *IPv6Address) UnmarshalJSON(b []byte) error {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.json"}}
+	}
+	return addr.UnmarshalText(b[1 : len(b)-1])
+}
+
+// MarshalJSON implements the json.Marshaler interface for IPAddressSeqRange.
+func (rng IPAddressSeqRange) MarshalJSON() ([]byte, error) {
+	text, err := rng.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%q", text)), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for IPAddressSeqRange.
+func (rng *IPAddressSeqRange) UnmarshalJSON(b []byte) error {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.json"}}
+	}
+	return rng.UnmarshalText(b[1 : len(b)-1])
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface for
+// IPAddressSeqRange.  The binary form is the lower bound's MarshalBinary
+// record, length-prefixed with a single byte, followed by the upper bound's
+// record in the same form.
+func (rng IPAddressSeqRange) MarshalBinary() ([]byte, error) {
+	lower, err := rng.GetLower().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	upper, err := rng.GetUpper().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	out := append([]byte{byte(len(lower))}, lower...)
+	out = append(out, upper...)
+	return out, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// reversing MarshalBinary.
+func (rng *IPAddressSeqRange) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	lowerLen := int(data[0])
+	data = data[1:]
+	if len(data) < lowerLen {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	var lower, upper IPAddress
+	if err := lower.UnmarshalBinary(data[:lowerLen]); err != nil {
+		return err
+	}
+	if err := upper.UnmarshalBinary(data[lowerLen:]); err != nil {
+		return err
+	}
+	parsed := lower.SpanWithRange(&upper)
+	*rng = *parsed
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler for MACAddress.
+func (addr MACAddress) MarshalText() ([]byte, error) {
+	return []byte(addr.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for MACAddress, parsing
+// text in the formats accepted by NewMACAddressString (colon, dash, dotted,
+// and space-delimited). Empty text yields the zero MACAddress rather than
+// an error, matching Go's json.Unmarshaler convention for empty input.
+func (addr *MACAddress) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*addr = MACAddress{}
+		return nil
+	}
+	parsed, err := NewMACAddressString(string(text)).ToAddress()
+	if err != nil {
+		return err
+	}
+	*addr = *parsed
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for MACAddress.
+func (addr MACAddress) MarshalJSON() ([]byte, error) {
+	text, err := addr.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%q", text)), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for MACAddress.
+func (addr *MACAddress) UnmarshalJSON(b []byte) error {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.json"}}
+	}
+	return addr.UnmarshalText(b[1 : len(b)-1])
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface for
+// MACAddress, by delegating to its section's MarshalBinary, which covers
+// IsMultiple() ranges and a prefix length; see
+// MACAddressSection.MarshalBinary.
+func (addr MACAddress) MarshalBinary() ([]byte, error) {
+	a := addr.init()
+	return a.GetSection().MarshalBinary()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// reversing MarshalBinary. Empty data yields the zero MACAddress rather
+// than an error, matching Go's json.Unmarshaler convention for empty input.
+func (addr *MACAddress) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		*addr = MACAddress{}
+		return nil
+	}
+	var section MACAddressSection
+	if err := section.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	*addr = *NewMACAddress(&section)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler for IPAddressString, encoding
+// the original, un-reparsed text the string was created with.
+func (str IPAddressString) MarshalText() ([]byte, error) {
+	return []byte(str.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for IPAddressString.
+func (str *IPAddressString) UnmarshalText(text []byte) error {
+	*str = *NewIPAddressString(string(text))
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for IPAddressString.
+func (str IPAddressString) MarshalJSON() ([]byte, error) {
+	text, err := str.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%q", text)), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for IPAddressString.
+func (str *IPAddressString) UnmarshalJSON(b []byte) error {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.json"}}
+	}
+	return str.UnmarshalText(b[1 : len(b)-1])
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface for
+// IPAddressString.  The binary form is simply the original text's bytes.
+func (str IPAddressString) MarshalBinary() ([]byte, error) {
+	return str.MarshalText()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, reversing
+// MarshalBinary.
+func (str *IPAddressString) UnmarshalBinary(data []byte) error {
+	return str.UnmarshalText(data)
+}
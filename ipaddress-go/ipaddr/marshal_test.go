@@ -0,0 +1,114 @@
+package ipaddr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestIPAddressMarshalRoundTrip checks that every accepted textual form of an
+// IPAddress round-trips through MarshalText/UnmarshalText, MarshalJSON/
+// UnmarshalJSON, and MarshalBinary/UnmarshalBinary, preserving the prefix
+// length and, for IPv6, the zone.
+func TestIPAddressMarshalRoundTrip(t *testing.T) {
+	tests := []string{
+		"1.2.3.4",
+		"1.2.3.4/24",
+		"0.0.0.0",
+		"255.255.255.255",
+		"::",
+		"::1",
+		"2001:db8::1",
+		"2001:db8::1/64",
+		"fe80::1%eth0",
+		"fe80::1%eth0/64",
+	}
+	for _, s := range tests {
+		addr, err := NewIPAddressString(s).ToAddress()
+		if err != nil {
+			t.Fatalf("NewIPAddressString(%q) failed to parse: %v", s, err)
+		}
+
+		text, err := addr.MarshalText()
+		if err != nil {
+			t.Fatalf("%q: MarshalText: %v", s, err)
+		}
+		var viaText IPAddress
+		if err := viaText.UnmarshalText(text); err != nil {
+			t.Fatalf("%q: UnmarshalText: %v", s, err)
+		}
+		if viaText.String() != addr.String() {
+			t.Errorf("%q: text round trip got %q, want %q", s, viaText.String(), addr.String())
+		}
+
+		jsonBytes, err := json.Marshal(addr)
+		if err != nil {
+			t.Fatalf("%q: MarshalJSON: %v", s, err)
+		}
+		var viaJSON IPAddress
+		if err := json.Unmarshal(jsonBytes, &viaJSON); err != nil {
+			t.Fatalf("%q: UnmarshalJSON: %v", s, err)
+		}
+		if viaJSON.String() != addr.String() {
+			t.Errorf("%q: json round trip got %q, want %q", s, viaJSON.String(), addr.String())
+		}
+
+		bin, err := addr.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%q: MarshalBinary: %v", s, err)
+		}
+		var viaBinary IPAddress
+		if err := viaBinary.UnmarshalBinary(bin); err != nil {
+			t.Fatalf("%q: UnmarshalBinary: %v", s, err)
+		}
+		if viaBinary.String() != addr.String() {
+			t.Errorf("%q: binary round trip got %q, want %q", s, viaBinary.String(), addr.String())
+		}
+		if addr.GetNetworkPrefixLength() != nil && viaBinary.GetNetworkPrefixLength() == nil {
+			t.Errorf("%q: binary round trip lost prefix length", s)
+		}
+		if ipv6 := addr.ToIPv6Address(); ipv6 != nil && ipv6.HasZone() {
+			if viaZone := viaBinary.ToIPv6Address(); viaZone == nil || !viaZone.HasZone() {
+				t.Errorf("%q: binary round trip lost zone", s)
+			}
+		}
+	}
+}
+
+// TestIPAddressSeqRangeMarshalRoundTrip checks that an IPAddressSeqRange
+// round-trips through its Marshal/Unmarshal pairs.
+func TestIPAddressSeqRangeMarshalRoundTrip(t *testing.T) {
+	tests := []string{
+		"1.2.3.4-1.2.3.10",
+		"::1-::ff",
+	}
+	for _, s := range tests {
+		rng, err := NewIPAddressString(s).ToRange()
+		if err != nil {
+			t.Fatalf("NewIPAddressString(%q) failed to parse as a range: %v", s, err)
+		}
+
+		bin, err := rng.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%q: MarshalBinary: %v", s, err)
+		}
+		var viaBinary IPAddressSeqRange
+		if err := viaBinary.UnmarshalBinary(bin); err != nil {
+			t.Fatalf("%q: UnmarshalBinary: %v", s, err)
+		}
+		if viaBinary.String() != rng.String() {
+			t.Errorf("%q: binary round trip got %q, want %q", s, viaBinary.String(), rng.String())
+		}
+
+		jsonBytes, err := json.Marshal(rng)
+		if err != nil {
+			t.Fatalf("%q: MarshalJSON: %v", s, err)
+		}
+		var viaJSON IPAddressSeqRange
+		if err := json.Unmarshal(jsonBytes, &viaJSON); err != nil {
+			t.Fatalf("%q: UnmarshalJSON: %v", s, err)
+		}
+		if viaJSON.String() != rng.String() {
+			t.Errorf("%q: json round trip got %q, want %q", s, viaJSON.String(), rng.String())
+		}
+	}
+}
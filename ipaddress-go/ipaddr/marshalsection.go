@@ -0,0 +1,302 @@
+package ipaddr
+
+import "fmt"
+
+// marshalFlagSectionPrefix mirrors marshalFlagPrefix but is scoped to the
+// section Marshal/Unmarshal pairs below, where no IP-version tag bit is
+// needed: the section's Go type alone already identifies the version.
+const marshalFlagSectionPrefix byte = 1 << 0
+
+// marshalFlagMACRange marks a MACAddressSection/MACAddress binary encoding
+// as carrying both lower and upper bytes rather than just one value.
+const marshalFlagMACRange byte = 1 << 1
+
+// MarshalText implements encoding.TextMarshaler for IPv4AddressSection,
+// producing the canonical string representation, the same as String().
+func (section IPv4AddressSection) MarshalText() ([]byte, error) {
+	return []byte(section.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for IPv4AddressSection,
+// parsing text in the same formats accepted by NewIPAddressString.
+func (section *IPv4AddressSection) UnmarshalText(text []byte) error {
+	addr, err := NewIPAddressString(string(text)).ToAddress()
+	if err != nil {
+		return err
+	}
+	ipv4Addr := addr.ToIPv4Address()
+	if ipv4Addr == nil {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	}
+	*section = *ipv4Addr.GetSection()
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for IPv4AddressSection.
+func (section IPv4AddressSection) MarshalJSON() ([]byte, error) {
+	text, err := section.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%q", text)), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for IPv4AddressSection.
+func (section *IPv4AddressSection) UnmarshalJSON(b []byte) error {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.json"}}
+	}
+	return section.UnmarshalText(b[1 : len(b)-1])
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface for
+// IPv4AddressSection.  The binary form is a flag byte indicating whether a
+// prefix length follows, the 4 raw address bytes, and an optional 1-byte
+// prefix length; no version tag is needed since the Go type already fixes it.
+func (section IPv4AddressSection) MarshalBinary() ([]byte, error) {
+	flags := byte(0)
+	prefLen := section.GetNetworkPrefixLength()
+	if prefLen != nil {
+		flags |= marshalFlagSectionPrefix
+	}
+	out := append([]byte{flags}, section.GetBytes()...)
+	if prefLen != nil {
+		out = append(out, byte(prefLen.Len()))
+	}
+	return out, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// reversing MarshalBinary.
+func (section *IPv4AddressSection) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	hasPrefix := data[0]&marshalFlagSectionPrefix != 0
+	rest := data[1:]
+	wantLen := IPv4ByteCount
+	if hasPrefix {
+		wantLen++
+	}
+	if len(rest) != wantLen {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	var parsed *IPv4Address
+	var err AddressValueException
+	if hasPrefix {
+		prefLen := cacheBitCount(BitCount(rest[IPv4ByteCount]))
+		parsed, err = NewIPv4AddressFromPrefixedIP(rest[:IPv4ByteCount], prefLen)
+	} else {
+		parsed, err = NewIPv4AddressFromIP(rest)
+	}
+	if err != nil {
+		return err
+	}
+	*section = *parsed.GetSection()
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler for IPv6AddressSection,
+// producing the canonical string representation, the same as String().
+func (section IPv6AddressSection) MarshalText() ([]byte, error) {
+	return []byte(section.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for IPv6AddressSection,
+// parsing text in the same formats accepted by NewIPAddressString.
+func (section *IPv6AddressSection) UnmarshalText(text []byte) error {
+	addr, err := NewIPAddressString(string(text)).ToAddress()
+	if err != nil {
+		return err
+	}
+	ipv6Addr := addr.ToIPv6Address()
+	if ipv6Addr == nil {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	}
+	*section = *ipv6Addr.GetSection()
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for IPv6AddressSection.
+func (section IPv6AddressSection) MarshalJSON() ([]byte, error) {
+	text, err := section.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%q", text)), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for IPv6AddressSection.
+func (section *IPv6AddressSection) UnmarshalJSON(b []byte) error {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.json"}}
+	}
+	return section.UnmarshalText(b[1 : len(b)-1])
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface for
+// IPv6AddressSection.  The binary form is a flag byte indicating whether a
+// prefix length follows, the 16 raw address bytes, and an optional 1-byte
+// prefix length.  IPv6AddressSection carries no zone, unlike IPv6Address.
+func (section IPv6AddressSection) MarshalBinary() ([]byte, error) {
+	flags := byte(0)
+	prefLen := section.GetNetworkPrefixLength()
+	if prefLen != nil {
+		flags |= marshalFlagSectionPrefix
+	}
+	out := append([]byte{flags}, section.GetBytes()...)
+	if prefLen != nil {
+		out = append(out, byte(prefLen.Len()))
+	}
+	return out, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// reversing MarshalBinary.
+func (section *IPv6AddressSection) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	hasPrefix := data[0]&marshalFlagSectionPrefix != 0
+	rest := data[1:]
+	wantLen := IPv6ByteCount
+	if hasPrefix {
+		wantLen++
+	}
+	if len(rest) != wantLen {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	var prefLen PrefixLen
+	if hasPrefix {
+		prefLen = cacheBitCount(BitCount(rest[IPv6ByteCount]))
+	}
+	parsed, err := NewIPv6AddressFromPrefixedIP(rest[:IPv6ByteCount], prefLen)
+	if err != nil {
+		return err
+	}
+	*section = *parsed.GetSection()
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler for MACAddressSection,
+// producing the canonical string representation, the same as String().
+func (section MACAddressSection) MarshalText() ([]byte, error) {
+	return []byte(section.ToCanonicalString()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for MACAddressSection,
+// parsing text in the same formats accepted by NewMACAddressString
+// (colon, dash, dotted, and space-delimited). Empty text yields the zero
+// MACAddressSection rather than an error, matching Go's json.Unmarshaler
+// convention for empty input.
+func (section *MACAddressSection) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*section = MACAddressSection{}
+		return nil
+	}
+	addr, err := NewMACAddressString(string(text)).ToAddress()
+	if err != nil {
+		return err
+	}
+	*section = *addr.GetSection()
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for MACAddressSection.
+func (section MACAddressSection) MarshalJSON() ([]byte, error) {
+	text, err := section.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%q", text)), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for MACAddressSection.
+func (section *MACAddressSection) UnmarshalJSON(b []byte) error {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.invalid.json"}}
+	}
+	return section.UnmarshalText(b[1 : len(b)-1])
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface for
+// MACAddressSection.  The binary form is a flag byte indicating whether
+// upper bytes and a prefix length follow, the section's lower bytes (6 for
+// EUI-48, 8 for EUI-64), the upper bytes if IsMultiple(), and an optional
+// 1-byte prefix length; the segment count is recovered from the byte count
+// alone.
+func (section MACAddressSection) MarshalBinary() ([]byte, error) {
+	flags := byte(0)
+	isRange := section.IsMultiple()
+	prefLen := section.GetPrefixLen()
+	if isRange {
+		flags |= marshalFlagMACRange
+	}
+	if prefLen != nil {
+		flags |= marshalFlagSectionPrefix
+	}
+	out := append([]byte{flags}, section.GetBytes()...)
+	if isRange {
+		out = append(out, section.GetUpperBytes()...)
+	}
+	if prefLen != nil {
+		out = append(out, byte(prefLen.Len()))
+	}
+	return out, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// reversing MarshalBinary. Empty data yields the zero MACAddressSection
+// rather than an error, matching Go's json.Unmarshaler convention for empty
+// input.
+func (section *MACAddressSection) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		*section = MACAddressSection{}
+		return nil
+	}
+	flags := data[0]
+	isRange := flags&marshalFlagMACRange != 0
+	hasPrefix := flags&marshalFlagSectionPrefix != 0
+	rest := data[1:]
+	bodyLen := len(rest)
+	if hasPrefix {
+		bodyLen--
+	}
+	if bodyLen < 0 {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	segCount := bodyLen
+	if isRange {
+		if bodyLen%2 != 0 {
+			return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		segCount = bodyLen / 2
+	}
+	lower := rest[:segCount]
+	rest = rest[segCount:]
+	var parsed *MACAddressSection
+	if isRange {
+		upper := rest[:segCount]
+		rest = rest[segCount:]
+		parsed = NewMACSectionFromRange(
+			func(i int) SegInt { return SegInt(lower[i]) },
+			func(i int) SegInt { return SegInt(upper[i]) },
+			segCount)
+	} else {
+		var err AddressValueError
+		parsed, err = NewMACSectionFromBytes(lower, segCount)
+		if err != nil {
+			return err
+		}
+	}
+	if hasPrefix {
+		if len(rest) != 1 {
+			return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		parsed = parsed.SetPrefixLen(BitCount(rest[0]))
+	} else if len(rest) != 0 {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	*section = *parsed
+	return nil
+}
@@ -0,0 +1,275 @@
+// Package multiaddr converts between IPAddress plus a transport tuple and
+// the self-describing multiaddr form used by libp2p, eg "/ip4/1.2.3.4/tcp/80"
+// or "/ip6zone/eth0/ip6/fe80::1/tcp/443", in both textual and TLV binary
+// encodings.
+package multiaddr
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+)
+
+// Protocol codes, as assigned by the multicodec registry.
+const (
+	ProtoIP4     = 4
+	ProtoTCP     = 6
+	ProtoUDP     = 273
+	ProtoIP6     = 41
+	ProtoIP6Zone = 42
+	ProtoQUIC    = 460
+)
+
+// Component is one element of a multiaddr: a protocol code plus its value,
+// eg {Proto: ProtoTCP, Value: "80"}.
+type Component struct {
+	Proto uint64
+	Value string
+}
+
+var protoNames = map[uint64]string{
+	ProtoIP4:     "ip4",
+	ProtoIP6:     "ip6",
+	ProtoIP6Zone: "ip6zone",
+	ProtoTCP:     "tcp",
+	ProtoUDP:     "udp",
+	ProtoQUIC:    "quic",
+}
+
+var namesToProto = func() map[string]uint64 {
+	m := make(map[string]uint64, len(protoNames))
+	for code, name := range protoNames {
+		m[name] = code
+	}
+	return m
+}()
+
+// hasValue reports whether a protocol carries a value after its name, as
+// opposed to being a bare flag like "quic".
+func hasValue(proto uint64) bool {
+	return proto != ProtoQUIC
+}
+
+// Encode renders addr and the given transport components as both the
+// textual multiaddr form and its TLV binary encoding.
+func Encode(addr *ipaddr.IPAddress, components ...Component) (string, []byte, error) {
+	var text strings.Builder
+	var bin []byte
+
+	ipComponent, err := ipComponentFor(addr)
+	if err != nil {
+		return "", nil, err
+	}
+	all := append([]Component{ipComponent}, components...)
+
+	for _, c := range all {
+		name, ok := protoNames[c.Proto]
+		if !ok {
+			return "", nil, fmt.Errorf("multiaddr: unknown protocol code %d", c.Proto)
+		}
+		text.WriteByte('/')
+		text.WriteString(name)
+		if hasValue(c.Proto) && c.Value != "" {
+			text.WriteByte('/')
+			text.WriteString(c.Value)
+		}
+
+		bin = appendVarint(bin, c.Proto)
+		if hasValue(c.Proto) && c.Value != "" {
+			valBytes, err := encodeValue(c.Proto, c.Value)
+			if err != nil {
+				return "", nil, err
+			}
+			bin = appendVarint(bin, uint64(len(valBytes)))
+			bin = append(bin, valBytes...)
+		}
+	}
+
+	return text.String(), bin, nil
+}
+
+func ipComponentFor(addr *ipaddr.IPAddress) (Component, error) {
+	if addr.IsIPv6() {
+		ipv6 := addr.ToIPv6Address()
+		if ipv6.HasZone() {
+			return Component{}, errors.New("multiaddr: use EncodeZoned for zoned IPv6 addresses")
+		}
+		return Component{Proto: ProtoIP6, Value: ipv6.ToCanonicalString()}, nil
+	}
+	return Component{Proto: ProtoIP4, Value: addr.ToIPv4Address().String()}, nil
+}
+
+// EncodeZoned is like Encode but for a zoned IPv6 address, which requires an
+// "/ip6zone/<zone>" component preceding the "/ip6/..." component.
+func EncodeZoned(addr *ipaddr.IPv6Address, components ...Component) (string, []byte, error) {
+	if !addr.HasZone() {
+		return Encode(addr.ToIPAddress(), components...)
+	}
+
+	var text strings.Builder
+	var bin []byte
+
+	zoneName := protoNames[ProtoIP6Zone]
+	text.WriteString("/" + zoneName + "/" + addr.ZoneStr())
+	bin = appendVarint(bin, ProtoIP6Zone)
+	zoneBytes := []byte(addr.ZoneStr())
+	bin = appendVarint(bin, uint64(len(zoneBytes)))
+	bin = append(bin, zoneBytes...)
+
+	rest, restBin, err := Encode(addr.WithoutZone().ToIPAddress(), components...)
+	if err != nil {
+		return "", nil, err
+	}
+	text.WriteString(rest)
+	bin = append(bin, restBin...)
+	return text.String(), bin, nil
+}
+
+func encodeValue(proto uint64, value string) ([]byte, error) {
+	switch proto {
+	case ProtoTCP, ProtoUDP:
+		port, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("multiaddr: invalid port %q: %w", value, err)
+		}
+		b := make([]byte, 2)
+		binary.BigEndian.PutUint16(b, uint16(port))
+		return b, nil
+	case ProtoIP4:
+		ip, err := ipaddr.NewIPAddressString(value).ToAddress()
+		if err != nil {
+			return nil, err
+		}
+		return ip.ToIPv4Address().GetBytes(), nil
+	case ProtoIP6:
+		ip, err := ipaddr.NewIPAddressString(value).ToAddress()
+		if err != nil {
+			return nil, err
+		}
+		return ip.ToIPv6Address().GetBytes(), nil
+	default:
+		return []byte(value), nil
+	}
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// Decode parses the textual multiaddr form, yielding its components in
+// order.
+func Decode(s string) ([]Component, error) {
+	if s == "" || s[0] != '/' {
+		return nil, errors.New("multiaddr: must start with '/'")
+	}
+	parts := strings.Split(s, "/")[1:]
+	var result []Component
+	for i := 0; i < len(parts); i++ {
+		proto, ok := namesToProto[parts[i]]
+		if !ok {
+			return nil, fmt.Errorf("multiaddr: unknown protocol %q", parts[i])
+		}
+		c := Component{Proto: proto}
+		if hasValue(proto) {
+			if i+1 >= len(parts) {
+				return nil, fmt.Errorf("multiaddr: protocol %q missing value", parts[i])
+			}
+			i++
+			c.Value = parts[i]
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// DecodeBinary parses the TLV binary multiaddr form, yielding its
+// components in order.
+func DecodeBinary(data []byte) ([]Component, error) {
+	var result []Component
+	for len(data) > 0 {
+		proto, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.New("multiaddr: invalid protocol varint")
+		}
+		data = data[n:]
+		name, ok := protoNames[proto]
+		if !ok {
+			return nil, fmt.Errorf("multiaddr: unknown protocol code %d", proto)
+		}
+		c := Component{Proto: proto}
+		if hasValue(proto) {
+			length, n := binary.Uvarint(data)
+			if n <= 0 {
+				return nil, errors.New("multiaddr: invalid length varint")
+			}
+			data = data[n:]
+			if uint64(len(data)) < length {
+				return nil, fmt.Errorf("multiaddr: truncated value for %q", name)
+			}
+			value, err := decodeValue(proto, data[:length])
+			if err != nil {
+				return nil, err
+			}
+			c.Value = value
+			data = data[length:]
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// DecodeError is returned by DecodeBinary when a component's TLV value
+// fails to decode for its protocol, eg a port that isn't exactly 2 bytes or
+// an ip4/ip6 value of the wrong width for its family. Proto identifies which
+// component failed, so callers building per-protocol routing tables can
+// distinguish a malformed-length component from an unknown protocol (which
+// DecodeBinary reports separately) via errors.As instead of string matching.
+type DecodeError struct {
+	Proto  uint64
+	Reason string
+}
+
+// Error renders e in the form "multiaddr: ip4: ip4 value must be 4 bytes, got 6".
+func (e *DecodeError) Error() string {
+	name, ok := protoNames[e.Proto]
+	if !ok {
+		name = strconv.FormatUint(e.Proto, 10)
+	}
+	return "multiaddr: " + name + ": " + e.Reason
+}
+
+func decodeValue(proto uint64, data []byte) (string, error) {
+	switch proto {
+	case ProtoTCP, ProtoUDP:
+		if len(data) != 2 {
+			return "", &DecodeError{Proto: proto, Reason: fmt.Sprintf("port value must be 2 bytes, got %d", len(data))}
+		}
+		return strconv.Itoa(int(binary.BigEndian.Uint16(data))), nil
+	case ProtoIP4:
+		if len(data) != 4 {
+			return "", &DecodeError{Proto: proto, Reason: fmt.Sprintf("ip4 value must be 4 bytes, got %d", len(data))}
+		}
+		addr, err := ipaddr.NewIPv4AddressFromIP(data)
+		if err != nil {
+			return "", &DecodeError{Proto: proto, Reason: err.Error()}
+		}
+		return addr.String(), nil
+	case ProtoIP6:
+		if len(data) != 16 {
+			return "", &DecodeError{Proto: proto, Reason: fmt.Sprintf("ip6 value must be 16 bytes, got %d", len(data))}
+		}
+		addr, err := ipaddr.NewIPv6AddressFromIP(data)
+		if err != nil {
+			return "", &DecodeError{Proto: proto, Reason: err.Error()}
+		}
+		return addr.ToCanonicalString(), nil
+	default:
+		return string(data), nil
+	}
+}
@@ -0,0 +1,192 @@
+package multiaddr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+)
+
+func mustAddr(t *testing.T, s string) *ipaddr.IPAddress {
+	t.Helper()
+	addr, err := ipaddr.NewIPAddressString(s).ToAddress()
+	if err != nil {
+		t.Fatalf("%q: failed to parse: %v", s, err)
+	}
+	return addr
+}
+
+// TestEncodeIP4TCP checks the textual and binary forms of a basic
+// "/ip4/.../tcp/..." multiaddr, and that both decode back to the same
+// components.
+func TestEncodeIP4TCP(t *testing.T) {
+	addr := mustAddr(t, "1.2.3.4")
+	text, bin, err := Encode(addr, Component{Proto: ProtoTCP, Value: "80"})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if want := "/ip4/1.2.3.4/tcp/80"; text != want {
+		t.Errorf("text got %q, want %q", text, want)
+	}
+
+	decoded, derr := Decode(text)
+	if derr != nil {
+		t.Fatalf("Decode: %v", derr)
+	}
+	want := []Component{{Proto: ProtoIP4, Value: "1.2.3.4"}, {Proto: ProtoTCP, Value: "80"}}
+	if !componentsEqual(decoded, want) {
+		t.Errorf("Decode got %v, want %v", decoded, want)
+	}
+
+	decodedBin, derr := DecodeBinary(bin)
+	if derr != nil {
+		t.Fatalf("DecodeBinary: %v", derr)
+	}
+	if !componentsEqual(decodedBin, want) {
+		t.Errorf("DecodeBinary got %v, want %v", decodedBin, want)
+	}
+}
+
+// TestEncodeIP6UDPQUIC checks an IPv6 address with a UDP port and the
+// bare-flag "quic" component.
+func TestEncodeIP6UDPQUIC(t *testing.T) {
+	addr := mustAddr(t, "::1")
+	text, bin, err := Encode(addr, Component{Proto: ProtoUDP, Value: "53"}, Component{Proto: ProtoQUIC})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if want := "/ip6/::1/udp/53/quic"; text != want {
+		t.Errorf("text got %q, want %q", text, want)
+	}
+
+	decoded, derr := Decode(text)
+	if derr != nil {
+		t.Fatalf("Decode: %v", derr)
+	}
+	want := []Component{{Proto: ProtoIP6, Value: "::1"}, {Proto: ProtoUDP, Value: "53"}, {Proto: ProtoQUIC}}
+	if !componentsEqual(decoded, want) {
+		t.Errorf("Decode got %v, want %v", decoded, want)
+	}
+
+	decodedBin, derr := DecodeBinary(bin)
+	if derr != nil {
+		t.Fatalf("DecodeBinary: %v", derr)
+	}
+	if !componentsEqual(decodedBin, want) {
+		t.Errorf("DecodeBinary got %v, want %v", decodedBin, want)
+	}
+}
+
+// TestEncodeZoned checks that a zoned IPv6 address produces a leading
+// "/ip6zone/<zone>" component, and that Encode itself rejects a zoned
+// address directly.
+func TestEncodeZoned(t *testing.T) {
+	addr := mustAddr(t, "fe80::1%eth0")
+	ipv6 := addr.ToIPv6Address()
+
+	if _, _, err := Encode(addr, Component{Proto: ProtoTCP, Value: "443"}); err == nil {
+		t.Error("Encode on a zoned address: expected an error, got none")
+	}
+
+	text, bin, err := EncodeZoned(ipv6, Component{Proto: ProtoTCP, Value: "443"})
+	if err != nil {
+		t.Fatalf("EncodeZoned: %v", err)
+	}
+	if want := "/ip6zone/eth0/ip6/fe80::1/tcp/443"; text != want {
+		t.Errorf("text got %q, want %q", text, want)
+	}
+
+	decoded, derr := Decode(text)
+	if derr != nil {
+		t.Fatalf("Decode: %v", derr)
+	}
+	want := []Component{
+		{Proto: ProtoIP6Zone, Value: "eth0"},
+		{Proto: ProtoIP6, Value: "fe80::1"},
+		{Proto: ProtoTCP, Value: "443"},
+	}
+	if !componentsEqual(decoded, want) {
+		t.Errorf("Decode got %v, want %v", decoded, want)
+	}
+
+	decodedBin, derr := DecodeBinary(bin)
+	if derr != nil {
+		t.Fatalf("DecodeBinary: %v", derr)
+	}
+	if !componentsEqual(decodedBin, want) {
+		t.Errorf("DecodeBinary got %v, want %v", decodedBin, want)
+	}
+}
+
+// TestDecodeErrors checks that malformed text and binary input are rejected
+// rather than silently misparsed.
+func TestDecodeErrors(t *testing.T) {
+	textCases := []string{
+		"",
+		"ip4/1.2.3.4",          // missing leading '/'
+		"/ip4/1.2.3.4/tcp",     // tcp missing its value
+		"/nosuchproto/1.2.3.4", // unknown protocol
+	}
+	for _, s := range textCases {
+		if _, err := Decode(s); err == nil {
+			t.Errorf("Decode(%q): expected an error, got none", s)
+		}
+	}
+
+	binCases := [][]byte{
+		{4},  // ip4 declared, but no value bytes follow
+		{99}, // unassigned protocol code
+	}
+	for i, data := range binCases {
+		if _, err := DecodeBinary(data); err == nil {
+			t.Errorf("DecodeBinary case %d: expected an error, got none", i)
+		}
+	}
+}
+
+// TestDecodeBinaryWrongLength checks that a TLV value of the wrong width for
+// its protocol is rejected with a typed *DecodeError identifying which
+// component failed, rather than panicking or silently producing a nil
+// address, for tcp/udp ports and both ip4 and ip6 addresses.
+func TestDecodeBinaryWrongLength(t *testing.T) {
+	tests := []struct {
+		name  string
+		proto uint64
+		value []byte
+	}{
+		{"tcp port too short", ProtoTCP, []byte{0xff}},
+		{"ip4 value too long", ProtoIP4, []byte{1, 2, 3, 4, 5, 6}},
+		{"ip6 value too short", ProtoIP6, []byte{1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		data := appendVarint(nil, tt.proto)
+		data = appendVarint(data, uint64(len(tt.value)))
+		data = append(data, tt.value...)
+
+		_, err := DecodeBinary(data)
+		if err == nil {
+			t.Errorf("%s: expected an error, got none", tt.name)
+			continue
+		}
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Errorf("%s: got %T, want *DecodeError", tt.name, err)
+			continue
+		}
+		if decodeErr.Proto != tt.proto {
+			t.Errorf("%s: DecodeError.Proto got %d, want %d", tt.name, decodeErr.Proto, tt.proto)
+		}
+	}
+}
+
+func componentsEqual(got, want []Component) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
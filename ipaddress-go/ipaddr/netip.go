@@ -0,0 +1,493 @@
+package ipaddr
+
+import (
+	"net/netip"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr/debug"
+)
+
+// ToNetIPAddr converts this address to a netip.Addr, the value type used throughout
+// the standard library's net/netip package.  The receiver must represent a single
+// address (ie GetCount() is 1); an address with multi-valued segments, such as a
+// subnet with a range or wildcard segment, cannot be represented as a single netip.Addr
+// and results in the zero netip.Addr being returned.
+//
+// IPv6 zone identifiers are preserved across the conversion.
+func (addr *IPAddress) ToNetIPAddr() netip.Addr {
+	if addr == nil {
+		return netip.Addr{}
+	}
+	addr = addr.init()
+	if !addr.IsMultiple() {
+		if ipv4Addr := addr.ToIPv4Address(); ipv4Addr != nil {
+			return ipv4Addr.ToNetIPAddr()
+		} else if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+			return ipv6Addr.ToNetIPAddr()
+		}
+	}
+	return netip.Addr{}
+}
+
+// ToNetIPAddr converts this IPv4 address to a netip.Addr.  The receiver must represent
+// a single address; a subnet with a range or wildcard segment cannot be represented
+// as a single netip.Addr and results in the zero netip.Addr being returned.
+func (addr *IPv4Address) ToNetIPAddr() netip.Addr {
+	if addr == nil || addr.IsMultiple() {
+		return netip.Addr{}
+	}
+	addr = addr.init()
+	var bytes [IPv4ByteCount]byte
+	addr.CopyBytes(bytes[:0])
+	return netip.AddrFrom4(bytes)
+}
+
+// ToNetIPAddr converts this IPv6 address to a netip.Addr, preserving the zone
+// identifier if one is present.  The receiver must represent a single address;
+// a subnet with a range or wildcard segment results in the zero netip.Addr.
+func (addr *IPv6Address) ToNetIPAddr() netip.Addr {
+	if addr == nil || addr.IsMultiple() {
+		return netip.Addr{}
+	}
+	addr = addr.init()
+	var bytes [IPv6ByteCount]byte
+	addr.CopyBytes(bytes[:0])
+	result := netip.AddrFrom16(bytes)
+	if zone := addr.zone; zone != noZone {
+		result = result.WithZone(string(zone))
+	}
+	return result
+}
+
+// ToNetIPPrefix converts this address to a netip.Prefix.  The receiver must be a
+// prefix block, ie the result of ToPrefixBlock or equivalent, with the prefix
+// length applying to the whole address; otherwise the zero netip.Prefix is returned.
+func (addr *IPAddress) ToNetIPPrefix() netip.Prefix {
+	if addr == nil {
+		return netip.Prefix{}
+	}
+	addr = addr.init()
+	prefLen := addr.GetNetworkPrefixLength()
+	if prefLen == nil || !addr.IsPrefixBlock() {
+		return netip.Prefix{}
+	}
+	return netip.PrefixFrom(addr.GetLower().ToNetIPAddr(), prefLen.Len())
+}
+
+// FromNetIPAddr creates an IPAddress from a netip.Addr, preserving any IPv6 zone.
+// The returned address has no associated prefix length.  An invalid (zero-value)
+// netip.Addr results in a nil IPAddress.
+// FromNetIPAddr preserves whichever form netip.Addr was constructed in: an
+// Is4In6 address converts to a 16-byte IPv4-mapped IPv6Address rather than
+// being silently unmapped to IPv4.  Call Unmap on the result, or addr.Unmap()
+// before conversion, if a canonical IPv4Address is wanted instead.  Setting the
+// convertv4v6 IPADDRDEBUG knob makes this unmapping automatic.
+func FromNetIPAddr(addr netip.Addr) *IPAddress {
+	if !addr.IsValid() {
+		return nil
+	}
+	if addr.Is4() {
+		bytes := addr.As4()
+		ipv4Addr, err := NewIPv4AddressFromIP(bytes[:])
+		if err != nil {
+			return nil
+		}
+		return ipv4Addr.ToIPAddress()
+	}
+	bytes := addr.As16()
+	ipv6Addr, err := NewIPv6AddressFromIP(bytes[:])
+	if err != nil {
+		return nil
+	}
+	if zone := addr.Zone(); zone != "" {
+		ipv6Addr.zone = canonicalZone(Zone(zone))
+	}
+	if addr.Is4In6() && debug.IsConvertV4V6Enabled() {
+		return ipv6Addr.Unmap()
+	}
+	return ipv6Addr.ToIPAddress()
+}
+
+// FromNetIPPrefix creates an IPAddress prefix block from a netip.Prefix.
+// An invalid netip.Prefix results in a nil IPAddress.
+func FromNetIPPrefix(prefix netip.Prefix) *IPAddress {
+	if !prefix.IsValid() {
+		return nil
+	}
+	addr := FromNetIPAddr(prefix.Addr())
+	if addr == nil {
+		return nil
+	}
+	return addr.ToPrefixBlockLen(prefix.Bits())
+}
+
+// ToNetIPAddrPort converts this sequential range to a netip.AddrPort when the range
+// represents a single address; the port must be supplied by the caller since
+// IPAddress itself carries no port information.
+func (rng *IPAddressSeqRange) ToNetIPAddrPort(port uint16) netip.AddrPort {
+	if rng == nil || rng.IsMultiple() {
+		return netip.AddrPort{}
+	}
+	return netip.AddrPortFrom(rng.GetLower().ToNetIPAddr(), port)
+}
+
+// ToNetIPRange returns the lower and upper bound of this sequential range as a pair
+// of netip.Addr values.
+func (rng *IPAddressSeqRange) ToNetIPRange() (lower, upper netip.Addr) {
+	if rng == nil {
+		return
+	}
+	return rng.GetLower().ToNetIPAddr(), rng.GetUpper().ToNetIPAddr()
+}
+
+// FromNetIPRange creates an IPAddressSeqRange spanning the given lower and upper
+// netip.Addr values.
+func FromNetIPRange(lower, upper netip.Addr) *IPAddressSeqRange {
+	lowerAddr := FromNetIPAddr(lower)
+	upperAddr := FromNetIPAddr(upper)
+	if lowerAddr == nil || upperAddr == nil {
+		return nil
+	}
+	return lowerAddr.SpanWithRange(upperAddr)
+}
+
+// ToNetIPAddrPort converts this address to a netip.AddrPort using the given port.
+// As with ToNetIPAddr, the receiver must represent a single address.
+func (addr *IPAddress) ToNetIPAddrPort(port uint16) netip.AddrPort {
+	return netip.AddrPortFrom(addr.ToNetIPAddr(), port)
+}
+
+// FromNetIPAddrPort creates an IPAddress and port from a netip.AddrPort.  The port
+// is returned separately since IPAddress itself carries no port information.
+func FromNetIPAddrPort(addrPort netip.AddrPort) (*IPAddress, uint16) {
+	return FromNetIPAddr(addrPort.Addr()), addrPort.Port()
+}
+
+// NewIPAddressFromNetIP is an alias for FromNetIPAddr, provided as a
+// constructor-style name for callers matching the NewXxxFrom... naming used
+// elsewhere in this package.
+func NewIPAddressFromNetIP(addr netip.Addr) *IPAddress {
+	return FromNetIPAddr(addr)
+}
+
+// NewIPAddressFromNetIPPrefix is an alias for FromNetIPPrefix, provided as a
+// constructor-style name matching NewIPAddressFromNetIP.
+func NewIPAddressFromNetIPPrefix(prefix netip.Prefix) *IPAddress {
+	return FromNetIPPrefix(prefix)
+}
+
+// ToNetIPAddr parses this address string and converts the result to a
+// netip.Addr, per IPAddress.ToNetIPAddr.  A parse failure or a
+// multi-valued address yields the zero netip.Addr.
+func (str *IPAddressString) ToNetIPAddr() netip.Addr {
+	addr, err := str.ToAddress()
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.ToNetIPAddr()
+}
+
+// ToNetIPPrefix parses this address string and converts the result to a
+// netip.Prefix, per IPAddress.ToNetIPPrefix.
+func (str *IPAddressString) ToNetIPPrefix() netip.Prefix {
+	addr, err := str.ToAddress()
+	if err != nil {
+		return netip.Prefix{}
+	}
+	return addr.ToNetIPPrefix()
+}
+
+// these conversions copy the address bytes rather than aliasing them: IPAddress
+// stores its segments boxed behind AddressDivision/*AddressSection, which has a
+// different memory layout than netip.Addr's inline 16-byte array, so there is no
+// way to share storage between the two representations. "Zero-copy" here refers
+// to avoiding any intermediate net.IP allocation, not to aliasing the backing array.
+
+// ToNetipAddr is an alias for ToNetIPAddr, spelled to match the net/netip
+// package name exactly for callers who prefer that casing.
+func (addr *IPAddress) ToNetipAddr() netip.Addr {
+	return addr.ToNetIPAddr()
+}
+
+// ToNetipPrefix is an alias for ToNetIPPrefix, spelled to match the
+// net/netip package name exactly.
+func (addr *IPAddress) ToNetipPrefix() netip.Prefix {
+	return addr.ToNetIPPrefix()
+}
+
+// ToNetipAddrPort is an alias for ToNetIPAddrPort, spelled to match the
+// net/netip package name exactly.
+func (addr *IPAddress) ToNetipAddrPort(port uint16) netip.AddrPort {
+	return addr.ToNetIPAddrPort(port)
+}
+
+// ToNetipAddr is an alias for ToNetIPAddr, spelled to match the net/netip
+// package name exactly.
+func (str *IPAddressString) ToNetipAddr() netip.Addr {
+	return str.ToNetIPAddr()
+}
+
+// ToNetipPrefix is an alias for ToNetIPPrefix, spelled to match the
+// net/netip package name exactly.
+func (str *IPAddressString) ToNetipPrefix() netip.Prefix {
+	return str.ToNetIPPrefix()
+}
+
+// ToNetipAddrPort is an alias for ToNetIPAddrPort, spelled to match the
+// net/netip package name exactly.
+func (rng *IPAddressSeqRange) ToNetipAddrPort(port uint16) netip.AddrPort {
+	return rng.ToNetIPAddrPort(port)
+}
+
+// NewIPAddressFromNetipAddr is an alias for NewIPAddressFromNetIP, spelled
+// to match the net/netip package name exactly.
+func NewIPAddressFromNetipAddr(addr netip.Addr) *IPAddress {
+	return NewIPAddressFromNetIP(addr)
+}
+
+// NewIPAddressFromNetipPrefix is an alias for NewIPAddressFromNetIPPrefix,
+// spelled to match the net/netip package name exactly.
+func NewIPAddressFromNetipPrefix(prefix netip.Prefix) *IPAddress {
+	return NewIPAddressFromNetIPPrefix(prefix)
+}
+
+// ToNetIPAddr converts this section's lowest address to a netip.Addr. The
+// receiver must be 4 or 16 bytes long, the IPv4 or IPv6 byte count; any other
+// length, such as a MAC-derived section, results in the zero netip.Addr.
+// A section carries no zone, unlike IPAddress, so the result never has one.
+func (section *IPAddressSection) ToNetIPAddr() netip.Addr {
+	if section == nil {
+		return netip.Addr{}
+	}
+	switch raw := section.GetBytes(); len(raw) {
+	case IPv4ByteCount:
+		var bytes [IPv4ByteCount]byte
+		copy(bytes[:], raw)
+		return netip.AddrFrom4(bytes)
+	case IPv6ByteCount:
+		var bytes [IPv6ByteCount]byte
+		copy(bytes[:], raw)
+		return netip.AddrFrom16(bytes)
+	default:
+		return netip.Addr{}
+	}
+}
+
+// ToNetIPPrefix converts this section to a netip.Prefix, using its own
+// network prefix length. The zero netip.Prefix is returned if the section
+// has no prefix length, or is not 4 or 16 bytes long.
+func (section *IPAddressSection) ToNetIPPrefix() netip.Prefix {
+	if section == nil {
+		return netip.Prefix{}
+	}
+	prefLen := section.GetNetworkPrefixLength()
+	if prefLen == nil {
+		return netip.Prefix{}
+	}
+	addr := section.ToNetIPAddr()
+	if !addr.IsValid() {
+		return netip.Prefix{}
+	}
+	return netip.PrefixFrom(addr, prefLen.Len())
+}
+
+// NewIPv4AddressFromNetIP creates an IPv4Address from a netip.Addr.  addr
+// must hold a 4-byte address (addr.Is4()); any other form, including an
+// Is4In6 address, is rejected, since unmapping is a decision callers should
+// make explicitly via Unmap rather than have happen implicitly here.
+func NewIPv4AddressFromNetIP(addr netip.Addr) (*IPv4Address, AddressValueException) {
+	if !addr.Is4() {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	}
+	bytes := addr.As4()
+	return NewIPv4AddressFromIP(bytes[:])
+}
+
+// NewIPv6AddressFromNetIP creates an IPv6Address from a netip.Addr, preserving
+// any zone.  addr must hold a 16-byte address (addr.Is6() or addr.Is4In6());
+// a 4-byte addr.Is4() address is rejected.
+func NewIPv6AddressFromNetIP(addr netip.Addr) (*IPv6Address, AddressValueException) {
+	if addr.Is4() {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	}
+	bytes := addr.As16()
+	result, err := NewIPv6AddressFromIP(bytes[:])
+	if err != nil {
+		return nil, err
+	}
+	if zone := addr.Zone(); zone != "" {
+		result.zone = canonicalZone(Zone(zone))
+	}
+	return result, nil
+}
+
+// NewIPv6AddressFromNetIPAddr is NewIPv6AddressFromNetIP under the name
+// matching netip.AddrPort-style callers tend to expect.
+func NewIPv6AddressFromNetIPAddr(addr netip.Addr) (*IPv6Address, error) {
+	return NewIPv6AddressFromNetIP(addr)
+}
+
+// NewIPv4AddressFromNetIPPrefix creates an IPv4Address prefix block from a
+// netip.Prefix whose address is a 4-byte netip.Addr.
+func NewIPv4AddressFromNetIPPrefix(p netip.Prefix) (*IPv4Address, AddressValueException) {
+	if !p.IsValid() {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	addr, err := NewIPv4AddressFromNetIP(p.Addr())
+	if err != nil {
+		return nil, err
+	}
+	return addr.ToPrefixBlockLen(BitCount(p.Bits())), nil
+}
+
+// NewIPv6AddressFromNetIPPrefix creates an IPv6Address prefix block from a
+// netip.Prefix whose address is a 16-byte netip.Addr.
+func NewIPv6AddressFromNetIPPrefix(p netip.Prefix) (*IPv6Address, AddressValueException) {
+	if !p.IsValid() {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	addr, err := NewIPv6AddressFromNetIP(p.Addr())
+	if err != nil {
+		return nil, err
+	}
+	return addr.ToPrefixBlockLen(BitCount(p.Bits())), nil
+}
+
+// ToNetIPPrefix converts this IPv4 address to a netip.Prefix, using its own
+// network prefix length.  The zero netip.Prefix is returned if addr has no
+// prefix length or is not a prefix block.
+func (addr *IPv4Address) ToNetIPPrefix() netip.Prefix {
+	if addr == nil {
+		return netip.Prefix{}
+	}
+	addr = addr.init()
+	prefLen := addr.GetNetworkPrefixLength()
+	if prefLen == nil || !addr.IsPrefixBlock() {
+		return netip.Prefix{}
+	}
+	return netip.PrefixFrom(addr.GetLower().ToNetIPAddr(), prefLen.Len())
+}
+
+// ToNetIPPrefix converts this IPv6 address to a netip.Prefix, using its own
+// network prefix length.  The zero netip.Prefix is returned if addr has no
+// prefix length or is not a prefix block.
+func (addr *IPv6Address) ToNetIPPrefix() netip.Prefix {
+	if addr == nil {
+		return netip.Prefix{}
+	}
+	addr = addr.init()
+	prefLen := addr.GetNetworkPrefixLength()
+	if prefLen == nil || !addr.IsPrefixBlock() {
+		return netip.Prefix{}
+	}
+	return netip.PrefixFrom(addr.GetLower().ToNetIPAddr(), prefLen.Len())
+}
+
+// ToNetIPAddr converts this range to a netip.Addr when the range represents a
+// single address, reporting ok as false and the zero netip.Addr otherwise.
+func (rng *IPv4AddressSeqRange) ToNetIPAddr() (result netip.Addr, ok bool) {
+	if rng == nil || rng.IsMultiple() {
+		return netip.Addr{}, false
+	}
+	return rng.GetLower().ToNetIPAddr(), true
+}
+
+// ToNetIPAddr converts this range to a netip.Addr when the range represents a
+// single address, reporting ok as false and the zero netip.Addr otherwise.
+func (rng *IPv6AddressSeqRange) ToNetIPAddr() (result netip.Addr, ok bool) {
+	if rng == nil || rng.IsMultiple() {
+		return netip.Addr{}, false
+	}
+	return rng.GetLower().ToNetIPAddr(), true
+}
+
+// IPAddressPort pairs an IPAddress with a port number, the way netip.AddrPort
+// pairs a netip.Addr with one, for callers that want to round-trip a
+// "host:port" string, eg "1.2.3.4:80" or "[::1]:80", without depending on
+// net/netip directly.
+type IPAddressPort struct {
+	Address *IPAddress
+	Port    PortNum
+}
+
+// NewIPAddressPortFromNetIPAddrPort converts a netip.AddrPort to an
+// IPAddressPort.
+func NewIPAddressPortFromNetIPAddrPort(addrPort netip.AddrPort) IPAddressPort {
+	return IPAddressPort{Address: FromNetIPAddr(addrPort.Addr()), Port: PortNum(addrPort.Port())}
+}
+
+// ToNetIPAddrPort converts this IPAddressPort to a netip.AddrPort.
+func (ap IPAddressPort) ToNetIPAddrPort() netip.AddrPort {
+	return netip.AddrPortFrom(ap.Address.ToNetIPAddr(), uint16(ap.Port))
+}
+
+// String renders ap in the same "host:port" form accepted by
+// ParseIPAddressPort, eg "1.2.3.4:80" or "[::1]:80".
+func (ap IPAddressPort) String() string {
+	return ap.ToNetIPAddrPort().String()
+}
+
+// ParseIPAddressPort parses s, a "host:port" string such as "1.2.3.4:80" or
+// "[::1]:80", into an IPAddressPort.
+func ParseIPAddressPort(s string) (IPAddressPort, error) {
+	addrPort, err := netip.ParseAddrPort(s)
+	if err != nil {
+		return IPAddressPort{}, err
+	}
+	return NewIPAddressPortFromNetIPAddrPort(addrPort), nil
+}
+
+// ToNetipPort converts this Port to the uint16 port number form used by
+// netip.AddrPort, returning 0 if p is nil.
+func (p *PortVal) ToNetipPort() uint16 {
+	return uint16(p.PortNum())
+}
+
+// FromNetipPort converts a uint16 port number, as carried by a
+// netip.AddrPort, to a Port.
+func FromNetipPort(port uint16) Port {
+	return ToPort(PortNum(port))
+}
+
+// ToNetipPrefix combines this PrefixLen with addr into a netip.Prefix,
+// reporting ok as false if p is nil, in which case the returned netip.Prefix
+// is the zero value rather than a prefix of length 0.
+func (p *PrefixBitCount) ToNetipPrefix(addr netip.Addr) (prefix netip.Prefix, ok bool) {
+	if p == nil {
+		return netip.Prefix{}, false
+	}
+	return netip.PrefixFrom(addr, p.bitCount()), true
+}
+
+// FromNetipPrefix extracts the prefix length of prefix as a PrefixLen,
+// returning nil if prefix is not valid, mirroring netip.Prefix's own
+// IsValid/zero-value semantics rather than treating an invalid prefix as a
+// prefix length of 0.
+func FromNetipPrefix(prefix netip.Prefix) PrefixLen {
+	if !prefix.IsValid() {
+		return nil
+	}
+	return cacheBitCount(prefix.Bits())
+}
+
+// ToNetipRange returns the lower and upper bound of this address as a pair
+// of netip.Addr values. Unlike ToNetipAddr, which yields the zero netip.Addr
+// for any multi-valued address (a subnet with a range or wildcard segment),
+// ToNetipRange always succeeds: for a single-valued address, lo and hi are
+// equal.
+func (addr *IPAddress) ToNetipRange() (lo, hi netip.Addr) {
+	if addr == nil {
+		return
+	}
+	addr = addr.init()
+	return addr.GetLower().ToNetipAddr(), addr.GetUpper().ToNetipAddr()
+}
+
+// FromNetipAddr is an alias for FromNetIPAddr, spelled to match the
+// net/netip package name exactly, for callers who prefer that casing, as
+// with ToNetipAddr above. There is no equivalent FromNetipPrefix alias for
+// FromNetIPPrefix: that exact name is already taken by the PrefixLen-typed
+// FromNetipPrefix above, so FromNetIPPrefix remains the one spelling for
+// building an *IPAddress prefix block from a netip.Prefix.
+func FromNetipAddr(addr netip.Addr) *IPAddress {
+	return FromNetIPAddr(addr)
+}
@@ -0,0 +1,181 @@
+// Package netipconv gathers this module's net/netip interop surface
+// (otherwise spread across ipaddr's own Address/Prefix/Port methods) behind
+// one small, discoverable set of free functions, for callers migrating
+// between this library and the growing net/netip ecosystem.
+package netipconv
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+)
+
+// ToAddr converts addr to a netip.Addr, reporting ok as false if addr is
+// nil or multi-valued, in which case no single netip.Addr exists.
+func ToAddr(addr *ipaddr.IPAddress) (result netip.Addr, ok bool) {
+	if addr == nil || addr.IsMultiple() {
+		return netip.Addr{}, false
+	}
+	return addr.ToNetIPAddr(), true
+}
+
+// FromAddr converts a netip.Addr to an *ipaddr.IPAddress, preserving an
+// IPv6 zone if present.
+func FromAddr(addr netip.Addr) *ipaddr.IPAddress {
+	return ipaddr.FromNetIPAddr(addr)
+}
+
+// ToPrefix converts addr, which must carry a prefix length, to a
+// netip.Prefix, reporting ok as false if addr has no prefix length or is
+// not a single address.
+func ToPrefix(addr *ipaddr.IPAddress) (result netip.Prefix, ok bool) {
+	netAddr, ok := ToAddr(addr)
+	if !ok {
+		return netip.Prefix{}, false
+	}
+	return addr.GetNetworkPrefixLength().ToNetipPrefix(netAddr)
+}
+
+// FromPrefix converts a netip.Prefix to the *ipaddr.IPAddress prefix block
+// it denotes, so any host bits of prefix's address are not kept, returning
+// nil if prefix is not valid.
+func FromPrefix(prefix netip.Prefix) *ipaddr.IPAddress {
+	if !prefix.IsValid() {
+		return nil
+	}
+	return ipaddr.NewIPAddressFromNetIPPrefix(prefix)
+}
+
+// ToAddrPort converts ap to a netip.AddrPort, reporting ok as false if ap's
+// address is nil or multi-valued.
+func ToAddrPort(ap ipaddr.IPAddressPort) (result netip.AddrPort, ok bool) {
+	netAddr, ok := ToAddr(ap.Address)
+	if !ok {
+		return netip.AddrPort{}, false
+	}
+	return netip.AddrPortFrom(netAddr, uint16(ap.Port)), true
+}
+
+// FromAddrPort converts a netip.AddrPort to an ipaddr.IPAddressPort.
+func FromAddrPort(addrPort netip.AddrPort) ipaddr.IPAddressPort {
+	return ipaddr.NewIPAddressPortFromNetIPAddrPort(addrPort)
+}
+
+// ErrMultiValued is returned in place of a netip.Addr or netip.Prefix when
+// the source IPAddress or IPAddressSection covers more than one address,
+// since net/netip has no concept of a range. SegmentIndex names the first
+// segment, in order, found to hold more than one value, or -1 if only the
+// grouping as a whole, and not a specific segment, could be identified as
+// multi-valued.
+type ErrMultiValued struct {
+	SegmentIndex int
+}
+
+func (e *ErrMultiValued) Error() string {
+	if e.SegmentIndex < 0 {
+		return "netipconv: grouping is multi-valued; net/netip has no range concept"
+	}
+	return fmt.Sprintf("netipconv: segment %d is multi-valued; net/netip has no range concept", e.SegmentIndex)
+}
+
+// firstMultiValuedSegment returns the index of the first segment of section
+// whose lower and upper values differ, or -1 if section is a single address.
+func firstMultiValuedSegment(section *ipaddr.AddressSection) int {
+	count := section.GetSegmentCount()
+	for i := 0; i < count; i++ {
+		seg := section.GetSegment(i)
+		if seg.GetSegmentValue() != seg.GetUpperSegmentValue() {
+			return i
+		}
+	}
+	return -1
+}
+
+// sectionOf returns addr's underlying AddressSection, or nil if addr is nil
+// or not a recognized IP version.
+func sectionOf(addr *ipaddr.IPAddress) *ipaddr.AddressSection {
+	if v4 := addr.ToIPv4Address(); v4 != nil {
+		return v4.GetSection().ToAddressSection()
+	} else if v6 := addr.ToIPv6Address(); v6 != nil {
+		return v6.GetSection().ToAddressSection()
+	}
+	return nil
+}
+
+// ToAddrStrict converts addr to a netip.Addr, like ToAddr, but returns an
+// *ErrMultiValued naming the offending segment instead of merely reporting
+// ok as false when addr covers more than one address.
+func ToAddrStrict(addr *ipaddr.IPAddress) (netip.Addr, error) {
+	if addr == nil {
+		return netip.Addr{}, fmt.Errorf("netipconv: nil address")
+	}
+	if section := sectionOf(addr); section != nil {
+		if i := firstMultiValuedSegment(section); i >= 0 {
+			return netip.Addr{}, &ErrMultiValued{SegmentIndex: i}
+		}
+	}
+	return addr.ToNetIPAddr(), nil
+}
+
+// ToSectionAddr converts section's lowest address to a netip.Addr, the
+// IPAddressSection counterpart of ToAddrStrict. It returns an
+// *ErrMultiValued if section covers more than one address; since
+// IPAddressSection does not expose per-segment access the way IPAddress
+// does, the offending segment cannot be named and SegmentIndex is -1.
+func ToSectionAddr(section *ipaddr.IPAddressSection) (netip.Addr, error) {
+	if section == nil {
+		return netip.Addr{}, fmt.Errorf("netipconv: nil section")
+	}
+	if section.GetPrefixCount(section.GetBitCount()).Cmp(big.NewInt(1)) != 0 {
+		return netip.Addr{}, &ErrMultiValued{SegmentIndex: -1}
+	}
+	return section.ToNetIPAddr(), nil
+}
+
+// ToSectionPrefix converts section to a netip.Prefix using its own network
+// prefix length, reporting ok as false if section has no prefix length or is
+// not a recognized byte length.
+func ToSectionPrefix(section *ipaddr.IPAddressSection) (result netip.Prefix, ok bool) {
+	if section == nil {
+		return netip.Prefix{}, false
+	}
+	result = section.ToNetIPPrefix()
+	return result, result.IsValid()
+}
+
+// ToAddrUnmapped is ToAddr, followed by netip.Addr.Unmap, so an IPv4-mapped
+// IPv6 address (::ffff:a.b.c.d) converts to the plain IPv4 form a.b.c.d,
+// matching the 4in6-unmapping convention common among net/netip adapters.
+func ToAddrUnmapped(addr *ipaddr.IPAddress) (result netip.Addr, ok bool) {
+	result, ok = ToAddr(addr)
+	if !ok {
+		return result, false
+	}
+	return result.Unmap(), true
+}
+
+// ToAddrs converts a slice of IPAddress to a slice of netip.Addr, returning
+// an *ErrMultiValued, wrapped with the offending slice index, on the first
+// address that covers more than one address.
+func ToAddrs(addrs []*ipaddr.IPAddress) ([]netip.Addr, error) {
+	result := make([]netip.Addr, len(addrs))
+	for i, addr := range addrs {
+		converted, err := ToAddrStrict(addr)
+		if err != nil {
+			return nil, fmt.Errorf("netipconv: address %d: %w", i, err)
+		}
+		result[i] = converted
+	}
+	return result, nil
+}
+
+// FromAddrs converts a slice of netip.Addr to a slice of IPAddress.
+func FromAddrs(addrs []netip.Addr) []*ipaddr.IPAddress {
+	result := make([]*ipaddr.IPAddress, len(addrs))
+	for i, addr := range addrs {
+		result[i] = FromAddr(addr)
+	}
+	return result
+}
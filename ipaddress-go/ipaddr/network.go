@@ -3,7 +3,6 @@ package ipaddr
 import (
 	"sync"
 	"sync/atomic"
-	"unsafe"
 )
 
 type AddressNetwork interface {
@@ -17,10 +16,11 @@ type AddressNetwork interface {
 // You can create your own network objects satisfying this interface, allowing you to create your own address types,
 // or to provide your own IP address conversion between IPv4 and IPv6.
 // When creating your own network, for IP addresses to be associated with it, you must:
-// - create each address using the creator methods in the instance creator returned from GetIPAddressCreator(),
-//	which will associate each address with said network when creating the address
-// - return the network object from the IPAddressStringParameters implementation used for parsing an IPAddressString,
-//	which will associate the parsed address with the network
+//   - create each address using the creator methods in the instance creator returned from GetIPAddressCreator(),
+//     which will associate each address with said network when creating the address
+//   - return the network object from the IPAddressStringParameters implementation used for parsing an IPAddressString,
+//     which will associate the parsed address with the network
+//
 // Addresses deprived from an existing address, using masking, iterating, or any other address manipulation,
 // will be associated with the same network as the original address, by using the network's address creator instance.
 // Addresses created by instantiation not through the network's creator instance will be associated with the default network.
@@ -43,17 +43,27 @@ type IPAddressNetwork interface {
 }
 
 type ipAddressNetwork struct {
-	subnetsMasksWithPrefix, subnetMasks, hostMasksWithPrefix, hostMasks []*IPAddress
+	subnetsMasksWithPrefix, subnetMasks, hostMasksWithPrefix, hostMasks []atomic.Pointer[IPAddress]
+
+	// each cache above has a parallel slice of sync.Once, one per cache
+	// index (ie per prefix length), so that concurrent requests for
+	// different prefix lengths never block each other, while concurrent
+	// requests for the same prefix length collapse into one computation.
+	subnetsMasksWithPrefixOnce, subnetMasksOnce, hostMasksWithPrefixOnce, hostMasksOnce []sync.Once
+
+	genMu      sync.Mutex
+	subnetGens map[BitCount]*SubnetGenerator
+	hostGens   map[string]*HostGenerator
 }
 
-//
-//
-//
-//
-//
 type IPv6AddressNetwork struct {
 	ipAddressNetwork
 	creator IPv6AddressCreator
+
+	wkMu                                                    sync.Mutex
+	loopback, unspecified, linkLocalPrefix, multicastPrefix *IPAddress
+	privatePrefixes, docPrefixes                            *[]*IPAddress
+	conversionStrategy                                      ConversionStrategy
 }
 
 func (network *IPv6AddressNetwork) GetIPv6AddressCreator() *IPv6AddressCreator {
@@ -69,8 +79,9 @@ func (network *IPv6AddressNetwork) GetIPv6AddressCreator() *IPv6AddressCreator {
 //}
 
 func (network *IPv6AddressNetwork) GetLoopback() *IPAddress {
-	//TODO use the creator
-	return nil
+	return lazyAddr(&network.loopback, &network.wkMu, func() *IPAddress {
+		return mustPrefixBlock("::1")
+	})
 }
 
 //func (network *IPv6AddressNetwork) GetNetworkIPAddress(prefLen PrefixLen) *IPAddress {
@@ -78,22 +89,22 @@ func (network *IPv6AddressNetwork) GetLoopback() *IPAddress {
 //}
 
 func (network *IPv6AddressNetwork) GetNetworkMask(prefLen BitCount) *IPAddress {
-	return getMask(IPv6, zeroIPv6Seg.ToAddressDivision(), prefLen, network.subnetMasks, true, false)
+	return getMask(IPv6, zeroIPv6Seg.ToAddressDivision(), prefLen, network.subnetMasks, network.subnetMasksOnce, true, false)
 	//return network.getNetworkIPv6Mask(prefLen, false).ToIPAddress()
 }
 
 func (network *IPv6AddressNetwork) GetPrefixedNetworkMask(prefLen BitCount) *IPAddress {
-	return getMask(IPv6, zeroIPv6Seg.ToAddressDivision(), prefLen, network.subnetsMasksWithPrefix, true, true)
+	return getMask(IPv6, zeroIPv6Seg.ToAddressDivision(), prefLen, network.subnetsMasksWithPrefix, network.subnetsMasksWithPrefixOnce, true, true)
 	//return network.getNetworkIPv6Mask(prefLen, true).ToIPAddress()
 }
 
 func (network *IPv6AddressNetwork) GetHostMask(prefLen BitCount) *IPAddress {
-	return getMask(IPv6, zeroIPv6Seg.ToAddressDivision(), prefLen, network.hostMasks, false, false)
+	return getMask(IPv6, zeroIPv6Seg.ToAddressDivision(), prefLen, network.hostMasks, network.hostMasksOnce, false, false)
 	//return network.getNetworkIPv4Mask(prefLen, false).ToIPAddress()
 }
 
 func (network *IPv6AddressNetwork) GetPrefixedHostMask(prefLen BitCount) *IPAddress {
-	return getMask(IPv6, zeroIPv6Seg.ToAddressDivision(), prefLen, network.hostMasksWithPrefix, false, true)
+	return getMask(IPv6, zeroIPv6Seg.ToAddressDivision(), prefLen, network.hostMasksWithPrefix, network.hostMasksWithPrefixOnce, false, true)
 	//return network.getNetworkIPv4Mask(prefLen, false).ToIPAddress()
 }
 
@@ -113,10 +124,14 @@ var _ IPAddressNetwork = &IPv6AddressNetwork{}
 
 var DefaultIPv6Network = IPv6AddressNetwork{
 	ipAddressNetwork: ipAddressNetwork{
-		make([]*IPAddress, IPv6BitCount+1),
-		make([]*IPAddress, IPv6BitCount+1),
-		make([]*IPAddress, IPv6BitCount+1),
-		make([]*IPAddress, IPv6BitCount+1),
+		subnetsMasksWithPrefix:     make([]atomic.Pointer[IPAddress], IPv6BitCount+1),
+		subnetMasks:                make([]atomic.Pointer[IPAddress], IPv6BitCount+1),
+		hostMasksWithPrefix:        make([]atomic.Pointer[IPAddress], IPv6BitCount+1),
+		hostMasks:                  make([]atomic.Pointer[IPAddress], IPv6BitCount+1),
+		subnetsMasksWithPrefixOnce: make([]sync.Once, IPv6BitCount+1),
+		subnetMasksOnce:            make([]sync.Once, IPv6BitCount+1),
+		hostMasksWithPrefixOnce:    make([]sync.Once, IPv6BitCount+1),
+		hostMasksOnce:              make([]sync.Once, IPv6BitCount+1),
 	},
 }
 
@@ -129,6 +144,11 @@ var DefaultIPv6Network = IPv6AddressNetwork{
 type IPv4AddressNetwork struct {
 	ipAddressNetwork
 	creator IPv4AddressCreator
+
+	wkMu                                                    sync.Mutex
+	loopback, unspecified, linkLocalPrefix, multicastPrefix *IPAddress
+	privatePrefixes, docPrefixes                            *[]*IPAddress
+	conversionStrategy                                      ConversionStrategy
 }
 
 func (network *IPv4AddressNetwork) GetIPv4AddressCreator() *IPv4AddressCreator {
@@ -144,8 +164,9 @@ func (network *IPv4AddressNetwork) GetIPv4AddressCreator() *IPv4AddressCreator {
 //}
 
 func (network *IPv4AddressNetwork) GetLoopback() *IPAddress {
-	//TODO
-	return nil
+	return lazyAddr(&network.loopback, &network.wkMu, func() *IPAddress {
+		return mustPrefixBlock("127.0.0.1")
+	})
 }
 
 //func (network *IPv4AddressNetwork) GetNetworkIPAddress(prefLen PrefixLen) *IPAddress {
@@ -157,22 +178,22 @@ func (network *IPv4AddressNetwork) GetLoopback() *IPAddress {
 //}
 
 func (network *IPv4AddressNetwork) GetNetworkMask(prefLen BitCount) *IPAddress {
-	return getMask(IPv4, zeroIPv4Seg.ToAddressDivision(), prefLen, network.subnetMasks, true, false)
+	return getMask(IPv4, zeroIPv4Seg.ToAddressDivision(), prefLen, network.subnetMasks, network.subnetMasksOnce, true, false)
 	//return network.getNetworkIPv4Mask(prefLen, false).ToIPAddress()
 }
 
 func (network *IPv4AddressNetwork) GetPrefixedNetworkMask(prefLen BitCount) *IPAddress {
-	return getMask(IPv4, zeroIPv4Seg.ToAddressDivision(), prefLen, network.subnetsMasksWithPrefix, true, true)
+	return getMask(IPv4, zeroIPv4Seg.ToAddressDivision(), prefLen, network.subnetsMasksWithPrefix, network.subnetsMasksWithPrefixOnce, true, true)
 	//return network.getNetworkIPv4Mask(prefLen, true).ToIPAddress()
 }
 
 func (network *IPv4AddressNetwork) GetHostMask(prefLen BitCount) *IPAddress {
-	return getMask(IPv4, zeroIPv4Seg.ToAddressDivision(), prefLen, network.hostMasks, false, false)
+	return getMask(IPv4, zeroIPv4Seg.ToAddressDivision(), prefLen, network.hostMasks, network.hostMasksOnce, false, false)
 	//return network.getNetworkIPv4Mask(prefLen, false).ToIPAddress()
 }
 
 func (network *IPv4AddressNetwork) GetPrefixedHostMask(prefLen BitCount) *IPAddress {
-	return getMask(IPv4, zeroIPv4Seg.ToAddressDivision(), prefLen, network.hostMasksWithPrefix, false, true)
+	return getMask(IPv4, zeroIPv4Seg.ToAddressDivision(), prefLen, network.hostMasksWithPrefix, network.hostMasksWithPrefixOnce, false, true)
 	//return network.getNetworkIPv4Mask(prefLen, false).ToIPAddress()
 }
 
@@ -185,10 +206,14 @@ var _ IPAddressNetwork = &IPv4AddressNetwork{}
 
 var DefaultIPv4Network = IPv4AddressNetwork{
 	ipAddressNetwork: ipAddressNetwork{
-		make([]*IPAddress, IPv4BitCount+1),
-		make([]*IPAddress, IPv4BitCount+1),
-		make([]*IPAddress, IPv4BitCount+1),
-		make([]*IPAddress, IPv4BitCount+1),
+		subnetsMasksWithPrefix:     make([]atomic.Pointer[IPAddress], IPv4BitCount+1),
+		subnetMasks:                make([]atomic.Pointer[IPAddress], IPv4BitCount+1),
+		hostMasksWithPrefix:        make([]atomic.Pointer[IPAddress], IPv4BitCount+1),
+		hostMasks:                  make([]atomic.Pointer[IPAddress], IPv4BitCount+1),
+		subnetsMasksWithPrefixOnce: make([]sync.Once, IPv4BitCount+1),
+		subnetMasksOnce:            make([]sync.Once, IPv4BitCount+1),
+		hostMasksWithPrefixOnce:    make([]sync.Once, IPv4BitCount+1),
+		hostMasksOnce:              make([]sync.Once, IPv4BitCount+1),
 	},
 }
 
@@ -206,11 +231,24 @@ func cacheStr(cachedString **string, stringer func() string) (str string) {
 }
 */
 
-var maskMutex sync.Mutex
+// ensureMaskSlot returns the address already cached at cache[idx], or builds
+// one with build and stores it, using onces[idx] so that concurrent callers
+// asking for the same idx collapse into a single call to build while
+// callers asking for other indexes are never blocked by it.
+func ensureMaskSlot(cache []atomic.Pointer[IPAddress], onces []sync.Once, idx int, build func() *IPAddress) *IPAddress {
+	if v := cache[idx].Load(); v != nil {
+		return v
+	}
+	onces[idx].Do(func() {
+		if cache[idx].Load() == nil {
+			cache[idx].Store(build())
+		}
+	})
+	return cache[idx].Load()
+}
 
-func getMask(version IPVersion, zeroSeg *AddressDivision, networkPrefixLength BitCount, cache []*IPAddress, network, withPrefixLength bool) *IPAddress {
+func getMask(version IPVersion, zeroSeg *AddressDivision, networkPrefixLength BitCount, cache []atomic.Pointer[IPAddress], onces []sync.Once, network, withPrefixLength bool) *IPAddress {
 	bits := networkPrefixLength
-	//IPVersion version = getIPVersion();
 	addressBitLength := GetBitCount(version)
 	if bits < 0 {
 		bits = 0
@@ -218,21 +256,10 @@ func getMask(version IPVersion, zeroSeg *AddressDivision, networkPrefixLength Bi
 		bits = addressBitLength
 	}
 	cacheIndex := bits
-	subnet := cache[cacheIndex]
-	if subnet != nil {
+	if subnet := cache[cacheIndex].Load(); subnet != nil {
 		return subnet
 	}
 
-	maskMutex.Lock()
-	subnet = cache[cacheIndex]
-	if subnet != nil {
-		maskMutex.Unlock()
-		return subnet
-	}
-	//
-	//
-	//
-
 	var onesSubnetIndex, zerosSubnetIndex int
 	if network {
 		onesSubnetIndex = int(addressBitLength)
@@ -241,150 +268,81 @@ func getMask(version IPVersion, zeroSeg *AddressDivision, networkPrefixLength Bi
 		onesSubnetIndex = 0
 		zerosSubnetIndex = int(addressBitLength)
 	}
-	onesSubnet := cache[onesSubnetIndex]
-	zerosSubnet := cache[zerosSubnetIndex]
 	segmentCount := GetSegmentCount(version)
 	bitsPerSegment := GetBitsPerSegment(version)
-	//bytesPerSegment := GetBytesPerSegment(version);
-	//if(onesSubnet == nil || zerosSubnet == nil) {
-	//synchronized(cache) {
-	//onesSubnet = cache[onesSubnetIndex];
 	maxSegmentValue := GetMaxSegmentValue(version)
-	if onesSubnet == nil {
-		//IPAddressCreator<T, ?, ?, S, ?> creator = getAddressCreator();
-		newSegments := createSegmentArray(segmentCount)
 
-		//if network && withPrefixLength {
+	onesSubnet := ensureMaskSlot(cache, onces, onesSubnetIndex, func() *IPAddress {
+		newSegments := createSegmentArray(segmentCount)
 		if withPrefixLength {
 			if network {
 				segment := createAddressDivision(zeroSeg.deriveNewSeg(maxSegmentValue, nil))
-				//lastSegment := createAddressDivision(zeroSeg.deriveNewSeg(maxSegmentValue, getDivisionPrefixLength(bitsPerSegment, bitsPerSegment) /* bitsPerSegment */))
 				lastSegment := createAddressDivision(zeroSeg.deriveNewSeg(maxSegmentValue, cacheBitCount(bitsPerSegment) /* bitsPerSegment */))
 				lastIndex := len(newSegments) - 1
 				fillDivs(newSegments[:lastIndex], segment)
-				//S segment = creator.createSegment(maxSegmentValue, IPAddressSection.getSegmentPrefixLength(bitsPerSegment, addressBitLength) /* null */ );
-				//Arrays.fill(newSegments, 0, newSegments.length - 1, segment);
 				newSegments[lastIndex] = lastSegment
-				onesSubnet = createIPAddress(createSection(newSegments, cacheBitCount(addressBitLength), version.toType(), 0), noZone)
-				//onesSubnet = creator.createAddressInternal(newSegments, cacheBits(addressBitLength)); /* address creation */
-
-			} else {
-				segment := createAddressDivision(zeroSeg.deriveNewSeg(maxSegmentValue, cacheBits(0)))
-				//newSegments[0] = firstSegment
-				//fillDivs(newSegments[1:], segment)
-				fillDivs(newSegments, segment)
-				onesSubnet = createIPAddress(createSection(newSegments, cacheBits(0), version.toType(), 0), noZone)
+				return createIPAddress(createSection(newSegments, cacheBitCount(addressBitLength), version.toType(), 0), noZone)
 			}
-		} else {
-			segment := createAddressDivision(zeroSeg.deriveNewSeg(maxSegmentValue, nil))
-			//S segment = creator.createSegment(maxSegmentValue);
-			//Arrays.fill(newSegments, segment);
+			segment := createAddressDivision(zeroSeg.deriveNewSeg(maxSegmentValue, cacheBits(0)))
 			fillDivs(newSegments, segment)
-			onesSubnet = createIPAddress(createSection(newSegments, nil, version.toType(), 0), noZone) /* address creation */
-			//onesSubnet = creator.createAddressInternal(newSegments); /* address creation */
+			return createIPAddress(createSection(newSegments, cacheBits(0), version.toType(), 0), noZone)
 		}
-		//initMaskCachedValues(onesSubnet.getSection(), network, withPrefixLength, networkAddress, addressBitLength, onesSubnetIndex, segmentCount, bitsPerSegment, bytesPerSegment);
-
-		dataLoc := (*unsafe.Pointer)(unsafe.Pointer(&cache[onesSubnetIndex]))
-		atomic.StorePointer(dataLoc, unsafe.Pointer(onesSubnet))
+		segment := createAddressDivision(zeroSeg.deriveNewSeg(maxSegmentValue, nil))
+		fillDivs(newSegments, segment)
+		return createIPAddress(createSection(newSegments, nil, version.toType(), 0), noZone)
+	})
 
-		//cache[onesSubnetIndex] = onesSubnet;
-	}
-	//zerosSubnet = cache[zerosSubnetIndex];
-	if zerosSubnet == nil {
-		//IPAddressCreator<T, ?, ?, S, ?> creator = getAddressCreator();
+	zerosSubnet := ensureMaskSlot(cache, onces, zerosSubnetIndex, func() *IPAddress {
 		newSegments := createSegmentArray(segmentCount)
-		//S seg;
 		if withPrefixLength {
 			prefLen := cacheBits(0)
 			if network {
 				segment := createAddressDivision(zeroSeg.deriveNewSeg(0, prefLen))
-				//seg = creator.createSegment(0, IPAddressSection.getSegmentPrefixLength(bitsPerSegment, 0) /* 0 */);
 				fillDivs(newSegments, segment)
-				//Arrays.fill(newSegments, seg);
-				zerosSubnet = createIPAddress(createSection(newSegments, prefLen, version.toType(), 0), noZone)
-				//zerosSubnet = creator.createAddressInternal(newSegments, prefLen); /* address creation */
-				//if(getPrefixConfiguration().zeroHostsAreSubnets() && !networkAddress) {
-				//	zerosSubnet = (T) zerosSubnet.getLower();
-				//}
-			} else {
-
-				//segment := createAddressDivision(zeroSeg.deriveNewSeg(0, xxx))
-				//lastSegment := createAddressDivision(zeroSeg.deriveNewSeg(maxSegmentValue, getDivisionPrefixLength(bitsPerSegment, bitsPerSegment) /* bitsPerSegment */))
-				lastSegment := createAddressDivision(zeroSeg.deriveNewSeg(0, cacheBitCount(bitsPerSegment) /* bitsPerSegment */))
-				lastIndex := len(newSegments) - 1
-				fillDivs(newSegments[:lastIndex], zeroSeg)
-				//S segment = creator.createSegment(maxSegmentValue, IPAddressSection.getSegmentPrefixLength(bitsPerSegment, addressBitLength) /* null */ );
-				//Arrays.fill(newSegments, 0, newSegments.length - 1, segment);
-				newSegments[lastIndex] = lastSegment
-				zerosSubnet = createIPAddress(createSection(newSegments, cacheBitCount(addressBitLength), version.toType(), 0), noZone)
-				//onesSubnet = creator.createAddressInternal(newSegments, cacheBits(addressBitLength)); /* address creation */
-
+				return createIPAddress(createSection(newSegments, prefLen, version.toType(), 0), noZone)
 			}
-		} else {
-			segment := createAddressDivision(zeroSeg.deriveNewSeg(0, nil))
-			fillDivs(newSegments, segment)
-			zerosSubnet = createIPAddress(createSection(newSegments, nil, version.toType(), 0), noZone)
-			//seg = creator.createSegment(0);
-			//Arrays.fill(newSegments, seg);
-			//zerosSubnet = creator.createAddressInternal(newSegments); /* address creation */
+			lastSegment := createAddressDivision(zeroSeg.deriveNewSeg(0, cacheBitCount(bitsPerSegment) /* bitsPerSegment */))
+			lastIndex := len(newSegments) - 1
+			fillDivs(newSegments[:lastIndex], zeroSeg)
+			newSegments[lastIndex] = lastSegment
+			return createIPAddress(createSection(newSegments, cacheBitCount(addressBitLength), version.toType(), 0), noZone)
 		}
-		//initMaskCachedValues(zerosSubnet.getSection(), network, withPrefixLength, networkAddress, addressBitLength, zerosSubnetIndex, segmentCount, bitsPerSegment, bytesPerSegment);
+		segment := createAddressDivision(zeroSeg.deriveNewSeg(0, nil))
+		fillDivs(newSegments, segment)
+		return createIPAddress(createSection(newSegments, nil, version.toType(), 0), noZone)
+	})
 
-		dataLoc := (*unsafe.Pointer)(unsafe.Pointer(&cache[zerosSubnetIndex]))
-		atomic.StorePointer(dataLoc, unsafe.Pointer(zerosSubnet))
-
-		//cache[zerosSubnetIndex] = zerosSubnet;
-	}
-	//}
-	//}
-
-	//synchronized(cache) {
-	//subnet = cache[cacheIndex];
-	//if(subnet == nil) {
-	//BiFunction<T, Integer, S> segProducer = getSegmentProducer();
 	prefix := bits
 	onesSegment := onesSubnet.getDivision(0)
 	zerosSegment := zerosSubnet.getDivision(0)
-	//onesSegment := segProducer(onesSubnet, 1);
-	//zerosSegment := segProducer(zerosSubnet, 1);
-	//IPAddressCreator<T, ?, ?, S, ?> creator = getAddressCreator();
 
-	//ArrayList<S> segmentList = new ArrayList<S>(segmentCount);
 	newSegments := createSegmentArray(segmentCount)[:0]
 	i := 0
-	//for ; bits > 0; i++, bits -= bitsPerSegment {
 	for ; bits > 0; i, bits = i+1, bits-bitsPerSegment {
 		if bits <= bitsPerSegment {
-			//S segment = null;
 			var segment *AddressDivision
 
-			//first do a check whether we have already created a segment like the one we need
+			// first check whether we have already created a segment
+			// like the one we need, scanning other cache entries
+			// lock-free via atomic.Pointer.Load
 			offset := ((bits - 1) % bitsPerSegment) + 1
 			for j, entry := 0, offset; j < segmentCount; j, entry = j+1, entry+bitsPerSegment {
-				//for j := 0, entry = offset; j < segmentCount; j++, entry += bitsPerSegment {
-				if entry != cacheIndex { //we already know that the entry at cacheIndex is null
-					prev := cache[entry]
-					if prev != nil {
+				if entry != cacheIndex { // we already know that the entry at cacheIndex is nil
+					if prev := cache[entry].Load(); prev != nil {
 						segment = prev.getDivision(j)
-						//segment = segProducer.apply(prev, j);
 						break
 					}
 				}
 			}
 
-			//if none of the other addresses with a similar segment are created yet, we need a new segment.
+			// if none of the other addresses with a similar segment are created yet, we need a new segment
 			if segment == nil {
-				//int networkMask = fullMask & (fullMask << (segmentBitSize - i));
-				//int mask = getSegmentNetworkMask(bits);
 				if network {
 					mask := maxSegmentValue & (maxSegmentValue << (bitsPerSegment - bits))
 					if withPrefixLength {
 						segment = createAddressDivision(zeroSeg.deriveNewSeg(mask, getDivisionPrefixLength(bitsPerSegment, bits)))
-						//segment = creator.createSegment(mask, IPAddressSection.getSegmentPrefixLength(bitsPerSegment, bits));
 					} else {
 						segment = createAddressDivision(zeroSeg.deriveNewSeg(mask, nil))
-						//segment = creator.createSegment(mask);
 					}
 				} else {
 					mask := maxSegmentValue & ^(maxSegmentValue << (bitsPerSegment - bits))
@@ -393,10 +351,8 @@ func getMask(version IPVersion, zeroSeg *AddressDivision, networkPrefixLength Bi
 					} else {
 						segment = createAddressDivision(zeroSeg.deriveNewSeg(mask, nil))
 					}
-					//segment = creator.createSegment(getSegmentHostMask(bits));
 				}
 			}
-			//segmentList.add(segment);
 			newSegments = append(newSegments, segment)
 		} else {
 			if network {
@@ -404,7 +360,6 @@ func getMask(version IPVersion, zeroSeg *AddressDivision, networkPrefixLength Bi
 			} else {
 				newSegments = append(newSegments, zerosSegment)
 			}
-			//segmentList.add(network ? onesSegment : zerosSegment);
 		}
 	}
 	for ; i < segmentCount; i++ {
@@ -413,43 +368,15 @@ func getMask(version IPVersion, zeroSeg *AddressDivision, networkPrefixLength Bi
 		} else {
 			newSegments = append(newSegments, onesSegment)
 		}
-		//segmentList.add(network ? zerosSegment : onesSegment);
 	}
-	//S newSegments[] = creator.createSegmentArray(segmentList.size());
-	//segmentList.toArray(newSegments);
 	var prefLen PrefixLen
 	if withPrefixLength {
 		prefLen = cacheBitCount(prefix)
 	}
-	subnet = createIPAddress(createSection(newSegments, prefLen, version.toType(), 0), noZone)
-
-	//if withPrefixLength {
-	//	subnet = createIPAddress(createSection(newSegments, cacheBitCount(prefix), version.toType(), 0), noZone)
-	//
-	//	//subnet = creator.createAddressInternal(newSegments, cacheBits(prefix)); /* address creation */
-	//	//if(getPrefixConfiguration().zeroHostsAreSubnets() && !networkAddress) {
-	//	//	subnet = (T) subnet.getLower();
-	//	//}
-	//} else {
-	//	subnet = createIPAddress(createSection(newSegments, nil, version.toType(), 0), noZone)
-	//	//subnet = creator.createAddressInternal(newSegments); /* address creation */
-	//}
-	//initialize the cache fields since we know what they are now - they do not have to be calculated later
-	//initMaskCachedValues(subnet.getSection(), network, withPrefixLength, networkAddress, addressBitLength, prefix, segmentCount, bitsPerSegment, bytesPerSegment);
-	//cache[cacheIndex] = subnet; //last thing is to put into the cache - don't put it there before we are done with it
-	//} // end subnet from cache is null
-
-	dataLoc := (*unsafe.Pointer)(unsafe.Pointer(&cache[cacheIndex]))
-	atomic.StorePointer(dataLoc, unsafe.Pointer(subnet))
-
-	//} //end synchronized
-
-	//
-	//
-	maskMutex.Unlock()
-
-	//} // end subnet from cache is null
-	return subnet
+
+	return ensureMaskSlot(cache, onces, cacheIndex, func() *IPAddress {
+		return createIPAddress(createSection(newSegments, prefLen, version.toType(), 0), noZone)
+	})
 }
 
 type MACAddressNetwork struct {
@@ -467,4 +394,4 @@ func (network *MACAddressNetwork) GetMACAddressCreator() *MACAddressCreator {
 
 var _ AddressNetwork = &MACAddressNetwork{}
 
-var DefaultMACNetwork MACAddressNetwork
\ No newline at end of file
+var DefaultMACNetwork MACAddressNetwork
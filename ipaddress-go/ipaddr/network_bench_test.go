@@ -0,0 +1,37 @@
+package ipaddr
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// BenchmarkGetNetworkMaskParallel spawns one goroutine per prefix length and
+// has each request its own IPv4 network mask concurrently, demonstrating
+// that per-cache-slot sync.Once lets distinct prefix lengths proceed without
+// blocking each other.
+func BenchmarkGetNetworkMaskParallel(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		network := IPv4AddressNetwork{
+			ipAddressNetwork: ipAddressNetwork{
+				subnetsMasksWithPrefix:     make([]atomic.Pointer[IPAddress], IPv4BitCount+1),
+				subnetMasks:                make([]atomic.Pointer[IPAddress], IPv4BitCount+1),
+				hostMasksWithPrefix:        make([]atomic.Pointer[IPAddress], IPv4BitCount+1),
+				hostMasks:                  make([]atomic.Pointer[IPAddress], IPv4BitCount+1),
+				subnetsMasksWithPrefixOnce: make([]sync.Once, IPv4BitCount+1),
+				subnetMasksOnce:            make([]sync.Once, IPv4BitCount+1),
+				hostMasksWithPrefixOnce:    make([]sync.Once, IPv4BitCount+1),
+				hostMasksOnce:              make([]sync.Once, IPv4BitCount+1),
+			},
+		}
+		var wg sync.WaitGroup
+		for prefLen := 0; prefLen <= IPv4BitCount; prefLen++ {
+			wg.Add(1)
+			go func(prefLen BitCount) {
+				defer wg.Done()
+				network.GetNetworkMask(prefLen)
+			}(prefLen)
+		}
+		wg.Wait()
+	}
+}
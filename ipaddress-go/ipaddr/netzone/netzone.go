@@ -0,0 +1,39 @@
+// Package netzone bridges ipaddr's zoned IPv6 addresses to the standard
+// library's net package, so a zoned IPv6Address can be used directly with
+// net.ListenUDP, net.DialTCP, and similar scoped-address APIs.
+package netzone
+
+import (
+	"net"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+)
+
+// UDPAddr converts addr plus a port into a *net.UDPAddr whose Zone field is
+// populated from addr's own zone.
+func UDPAddr(addr *ipaddr.IPv6Address, port int) *net.UDPAddr {
+	return &net.UDPAddr{IP: addr.GetIP(), Port: port, Zone: addr.ZoneStr()}
+}
+
+// TCPAddr converts addr plus a port into a *net.TCPAddr whose Zone field is
+// populated from addr's own zone.
+func TCPAddr(addr *ipaddr.IPv6Address, port int) *net.TCPAddr {
+	return &net.TCPAddr{IP: addr.GetIP(), Port: port, Zone: addr.ZoneStr()}
+}
+
+// FromUDPAddr converts a *net.UDPAddr carrying an IPv6 address back into a
+// zoned ipaddr.IPv6Address, resolving its interface-name zone, if any, with
+// ipaddr.ResolveZone to confirm the interface exists.
+func FromUDPAddr(a *net.UDPAddr) (*ipaddr.IPv6Address, error) {
+	addr, err := ipaddr.NewIPv6AddressFromIP(a.IP)
+	if err != nil {
+		return nil, err
+	}
+	if a.Zone == "" {
+		return addr, nil
+	}
+	if _, err := ipaddr.ResolveZone(a.Zone); err != nil {
+		return nil, err
+	}
+	return ipaddr.NewIPv6AddressZoned(addr.GetSection(), ipaddr.Zone(a.Zone)), nil
+}
@@ -0,0 +1,86 @@
+package ipaddr
+
+import "math/big"
+
+// ContainsNonZeroHosts returns whether this address or subnet contains every
+// address in other except, if other has a prefix length, the all-zero host
+// address (the network/subnet identifier) within it.  This lets a range-based
+// subnet such as "192.13.1.1-127" be considered to contain the prefix block
+// "192.13.1.0/25" even though strict containment (which counts the all-zero
+// network address "192.13.1.0" itself) would be false, since the network
+// address is usually reserved and not individually addressable.
+//
+// If other has no prefix length, this is equivalent to Contains.
+func (addr *IPAddress) ContainsNonZeroHosts(other *IPAddress) bool {
+	addr = addr.init()
+	if other == nil {
+		return false
+	}
+	other = other.init()
+	if other.GetNetworkPrefixLength() == nil {
+		return addr.contains(other.ToAddress())
+	}
+	zeroHost := other.ToZeroHost()
+	lowerVal := addrValue(other.GetLower())
+	upperVal := addrValue(other.GetUpper())
+	if zeroHost != nil && addrValue(zeroHost).Cmp(lowerVal) == 0 {
+		lowerVal = new(big.Int).Add(lowerVal, bigOne())
+		if lowerVal.Cmp(upperVal) > 0 {
+			return true // other contains only its own zero-host address
+		}
+	}
+	return addrValue(addr.GetLower()).Cmp(lowerVal) <= 0 && addrValue(addr.GetUpper()).Cmp(upperVal) >= 0
+}
+
+// Contains returns whether this address or subnet contains every address in
+// other.  It accepts a generic *IPAddress regardless of version, unlike the
+// per-version Contains methods on IPv4Address/IPv6Address, returning false
+// when the versions differ.
+func (addr *IPAddress) Contains(other *IPAddress) bool {
+	addr = addr.init()
+	if other == nil {
+		return false
+	}
+	other = other.init()
+	return addr.contains(other.ToAddress())
+}
+
+// ContainsSinglePrefixBlock returns whether this address represents, for the
+// given prefix length, exactly one prefix block, the one whose network
+// matches the network of this address.  Unlike ContainsPrefixBlock, which
+// allows this address to be a larger range spanning multiple prefix blocks of
+// the given length, ContainsSinglePrefixBlock requires this address itself to
+// be that single prefix block.
+func (addr *IPAddress) ContainsSinglePrefixBlock(prefixLen BitCount) bool {
+	addr = addr.init()
+	if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+		return ipv6Addr.ContainsSinglePrefixBlock(prefixLen)
+	} else if ipv4Addr := addr.ToIPv4Address(); ipv4Addr != nil {
+		return ipv4Addr.ContainsSinglePrefixBlock(prefixLen)
+	}
+	return false
+}
+
+// ToZeroHost returns the address with the same prefix as this address but with
+// all host bits (the bits beyond the prefix length) set to zero.  If this
+// address has no prefix length, it is returned unchanged.
+func (addr *IPAddress) ToZeroHost() *IPAddress {
+	addr = addr.init()
+	if addr.GetNetworkPrefixLength() == nil {
+		return addr
+	}
+	if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+		res, err := ipv6Addr.ToZeroHost()
+		if err != nil {
+			return nil
+		}
+		return res.ToIPAddress()
+	} else if ipv4Addr := addr.ToIPv4Address(); ipv4Addr != nil {
+		res, err := ipv4Addr.ToZeroHost()
+		if err != nil {
+			return nil
+		}
+		return res.ToIPAddress()
+	}
+	return nil
+}
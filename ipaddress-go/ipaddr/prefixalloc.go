@@ -0,0 +1,59 @@
+package ipaddr
+
+// This file adds prefix sub-allocation on top of the existing IPSet, for
+// IPAM-style callers that need to carve a fixed-length block out of a larger
+// CIDR while avoiding already-reserved space and minimizing fragmentation of
+// what remains free.
+
+// FindAvailablePrefix returns a prefix block of the given length, contained
+// within addr's own prefix block, that does not overlap any address in
+// reserved. Among the maximal free blocks not covered by reserved, the
+// smallest one still large enough to hold the requested length is chosen
+// (buddy-allocator style, so a large free block is only split when nothing
+// smaller will do), with ties broken by the lowest address. An error is
+// returned if no such prefix exists.
+func (addr *IPAddress) FindAvailablePrefix(length BitCount, reserved *IPSet) (*IPAddress, error) {
+	addr = addr.init()
+	builder := &IPSetBuilder{}
+	builder.AddPrefix(addr)
+	free := builder.Build()
+	if reserved != nil {
+		free = free.Difference(reserved)
+	}
+	var best *IPAddress
+	for _, block := range free.Prefixes() {
+		prefLen := block.GetNetworkPrefixLength()
+		if prefLen == nil || prefLen.Len() > length {
+			// a maximal free block whose prefix length is at most length
+			// (ie at least as large) can be split down to length; one
+			// already narrower than length cannot hold a length-sized block
+			continue
+		}
+		if best == nil || prefLen.Len() > best.GetNetworkPrefixLength().Len() {
+			best = block
+		}
+	}
+	if best == nil {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefix.pool.exhausted"}}
+	}
+	return best.GetLower().ToPrefixBlockLen(length), nil
+}
+
+// FindAvailablePrefixes returns count prefix blocks of the given length,
+// contained within addr's own prefix block, none overlapping each other. Each successive block is reserved before the next is chosen,
+// so earlier allocations never fragment later ones more than necessary. An
+// error is returned, along with whatever blocks were already found, if
+// fewer than count are available.
+func (addr *IPAddress) FindAvailablePrefixes(length BitCount, count int) ([]*IPAddress, error) {
+	results := make([]*IPAddress, 0, count)
+	builder := &IPSetBuilder{}
+	for i := 0; i < count; i++ {
+		next, err := addr.FindAvailablePrefix(length, builder.Build())
+		if err != nil {
+			return results, err
+		}
+		results = append(results, next)
+		builder.AddPrefix(next)
+	}
+	return results, nil
+}
@@ -0,0 +1,84 @@
+package ipaddr
+
+import "math/big"
+
+// GetPrefixCount returns the number of blocks of the given prefix length
+// contained in addr's own prefix block, using math/big so large splits (eg
+// an IPv6 /32 split into /128s) do not overflow. length is normalized to
+// addr's bit count via checkBitCount; if length is shorter than addr's own
+// prefix length, no such blocks exist and the result is zero.
+func (addr *IPAddress) GetPrefixCount(length BitCount) *big.Int {
+	addr = addr.init()
+	length = checkBitCount(length, ipVersionBitCount(addr.GetIPVersion()))
+	if ownPrefLen := addr.GetNetworkPrefixLength(); ownPrefLen != nil && length < ownPrefLen.Len() {
+		return bigZero()
+	}
+	if v4 := addr.ToIPv4Address(); v4 != nil {
+		return v4.GetSection().GetPrefixCountLen(length)
+	} else if v6 := addr.ToIPv6Address(); v6 != nil {
+		return v6.GetSection().GetPrefixCountLen(length)
+	}
+	return bigZero()
+}
+
+// GetPrefixCount returns the number of blocks of the given prefix length
+// contained in section, the IPAddressSection counterpart to
+// (*IPAddress).GetPrefixCount.
+func (section *IPAddressSection) GetPrefixCount(length BitCount) *big.Int {
+	if section == nil {
+		return bigZero()
+	}
+	length = checkBitCount(length, section.GetBitCount())
+	if ownPrefLen := section.GetNetworkPrefixLength(); ownPrefLen != nil && length < ownPrefLen.Len() {
+		return bigZero()
+	}
+	return section.GetPrefixCountLen(length)
+}
+
+// ipPrefixIterator lazily yields the sub-prefix blocks of a given length
+// spanning an address range, in ascending order.
+type ipPrefixIterator struct {
+	cur, end, blockSize *big.Int
+	version             IPVersion
+	prefixLen           BitCount
+	done                bool
+}
+
+func (it *ipPrefixIterator) HasNext() bool {
+	return !it.done
+}
+
+func (it *ipPrefixIterator) Next() *IPAddress {
+	if it.done {
+		return nil
+	}
+	next := valueToAddr(it.cur, it.version).ToPrefixBlockLen(it.prefixLen)
+	advanced := new(big.Int).Add(it.cur, it.blockSize)
+	if advanced.Cmp(it.end) > 0 {
+		it.done = true
+	} else {
+		it.cur = advanced
+	}
+	return next
+}
+
+// PrefixIterator returns a streaming iterator over every sub-prefix block of
+// the given length contained in addr's own range, in ascending order,
+// normalizing length via checkBitCount the same way GetPrefixCount does. If
+// length is shorter than addr's own prefix length, the returned iterator is
+// empty.
+func (addr *IPAddress) PrefixIterator(length BitCount) IPAddressIterator {
+	addr = addr.init()
+	bitCount := ipVersionBitCount(addr.GetIPVersion())
+	length = checkBitCount(length, bitCount)
+	if ownPrefLen := addr.GetNetworkPrefixLength(); ownPrefLen != nil && length < ownPrefLen.Len() {
+		return &ipPrefixIterator{done: true}
+	}
+	return &ipPrefixIterator{
+		cur:       addrValue(addr.GetLower()),
+		end:       addrValue(addr.GetUpper()),
+		blockSize: new(big.Int).Lsh(bigOne(), uint(bitCount-length)),
+		version:   addr.GetIPVersion(),
+		prefixLen: length,
+	}
+}
@@ -0,0 +1,289 @@
+// Package probe implements address-reachability checks — ICMP echo, TCP
+// connect, and ARP presence — built directly on ipaddr's iteration and
+// prefix-block machinery, so a caller can sweep a CIDR block with no
+// scanning dependency beyond the standard library.
+package probe
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+)
+
+// errARPUnsupported is returned by Ping when Method is ARP on a platform
+// whose neighbor table this package does not yet know how to read.
+var errARPUnsupported = errors.New("probe: ARP method not supported on " + runtime.GOOS)
+
+// Method selects the liveness check Ping and Sweep perform.
+type Method int
+
+const (
+	// ICMP sends an ICMP (or ICMPv6 for IPv6 targets) echo request over a
+	// raw socket, which typically requires elevated privileges. The
+	// datagram dial tried first is given no port and so always fails; no
+	// unprivileged ping socket is used.
+	ICMP Method = iota
+	// TCP attempts a TCP connection to ProbeOptions.Port and treats a
+	// completed handshake as reachable.
+	TCP
+	// ARP resolves the address's link-layer address via the local IPv4
+	// ARP neighbor table, for probing hosts on a directly attached subnet
+	// without sending any packet of its own. The IPv6 (NDP) neighbor cache
+	// is not read, so IPv6 targets report Unreachable.
+	ARP
+)
+
+// Reachability classifies the outcome of a single probe.
+type Reachability int
+
+const (
+	Unknown Reachability = iota
+	Reachable
+	Unreachable
+	TimedOut
+)
+
+// ProbeOptions configures a single Ping.
+type ProbeOptions struct {
+	Method Method
+	// Port is the destination port used when Method is TCP; ignored otherwise.
+	Port int
+	// Timeout bounds how long Ping waits for a response. A zero value
+	// defaults to 2 seconds.
+	Timeout time.Duration
+}
+
+// SweepOptions configures a Sweep over a block.
+type SweepOptions struct {
+	ProbeOptions
+	// Concurrency caps the number of probes in flight at once. A value
+	// less than 1 defaults to 1.
+	Concurrency int
+	// RatePerSecond caps how many probes Sweep starts per second. A value
+	// less than 1 means unlimited.
+	RatePerSecond int
+	// HostsOnly skips the network and all-ones (broadcast) addresses of
+	// an IPv4 prefix block, mirroring the host-only iteration ipcalc-style
+	// tools perform over a CIDR.
+	HostsOnly bool
+}
+
+// ProbeResult carries the outcome of probing a single address.
+type ProbeResult struct {
+	Address      *ipaddr.IPAddress
+	RTT          time.Duration
+	Reachability Reachability
+}
+
+// Ping probes a single address and blocks until the result is known or
+// opts.Timeout elapses.
+func Ping(addr *ipaddr.IPAddress, opts ProbeOptions) (ProbeResult, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	switch opts.Method {
+	case TCP:
+		return pingTCP(addr, opts.Port, timeout)
+	case ARP:
+		return pingARP(addr, timeout)
+	default:
+		return pingICMP(addr, timeout)
+	}
+}
+
+// Sweep iterates block's addresses, respecting opts.HostsOnly for IPv4
+// prefix blocks, and probes each one according to opts.ProbeOptions. It
+// returns a channel of results that closes once every address has been
+// probed.
+func Sweep(block *ipaddr.IPAddress, opts SweepOptions) <-chan ProbeResult {
+	results := make(chan ProbeResult)
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var ticker *time.Ticker
+	if opts.RatePerSecond > 0 {
+		ticker = time.NewTicker(time.Second / time.Duration(opts.RatePerSecond))
+	}
+
+	go func() {
+		defer close(results)
+		if ticker != nil {
+			defer ticker.Stop()
+		}
+
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for _, addr := range hostAddresses(block, opts.HostsOnly) {
+			if ticker != nil {
+				<-ticker.C
+			}
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(addr *ipaddr.IPAddress) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				result, err := Ping(addr, opts.ProbeOptions)
+				if err != nil {
+					result = ProbeResult{Address: addr, Reachability: Unknown}
+				}
+				results <- result
+			}(addr)
+		}
+		wg.Wait()
+	}()
+
+	return results
+}
+
+// hostAddresses enumerates block's individual addresses, optionally
+// skipping the network and broadcast addresses of an IPv4 prefix block.
+func hostAddresses(block *ipaddr.IPAddress, hostsOnly bool) []*ipaddr.IPAddress {
+	var all []*ipaddr.IPAddress
+	if ipv4Addr := block.ToIPv4Address(); ipv4Addr != nil {
+		iter := ipv4Addr.Iterator()
+		for iter.HasNext() {
+			all = append(all, iter.Next().ToIPAddress())
+		}
+	} else if ipv6Addr := block.ToIPv6Address(); ipv6Addr != nil {
+		iter := ipv6Addr.Iterator()
+		for iter.HasNext() {
+			all = append(all, iter.Next().ToIPAddress())
+		}
+	}
+	if !hostsOnly || !block.IsIPv4() || len(all) <= 2 {
+		return all
+	}
+	network, broadcast := all[0].String(), all[len(all)-1].String()
+	hosts := make([]*ipaddr.IPAddress, 0, len(all)-2)
+	for _, addr := range all {
+		if s := addr.String(); s == network || s == broadcast {
+			continue
+		}
+		hosts = append(hosts, addr)
+	}
+	return hosts
+}
+
+func pingTCP(addr *ipaddr.IPAddress, port int, timeout time.Duration) (ProbeResult, error) {
+	result := ProbeResult{Address: addr}
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(addr.String(), strconv.Itoa(port)), timeout)
+	if err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			result.Reachability = TimedOut
+			return result, nil
+		}
+		result.Reachability = Unreachable
+		return result, nil
+	}
+	defer conn.Close()
+	result.RTT = time.Since(start)
+	result.Reachability = Reachable
+	return result, nil
+}
+
+// pingICMP sends a single ICMP (or ICMPv6) echo request. It first dials
+// "udp4"/"udp6" with an address that has no port, which net.DialTimeout
+// rejects, so the request always goes out over the raw "ip4:icmp" or
+// "ip6:ipv6-icmp" socket, which typically requires elevated privileges.
+func pingICMP(addr *ipaddr.IPAddress, timeout time.Duration) (ProbeResult, error) {
+	result := ProbeResult{Address: addr}
+	network, rawNetwork, echoType := "udp4", "ip4:icmp", byte(8)
+	if addr.IsIPv6() {
+		network, rawNetwork, echoType = "udp6", "ip6:ipv6-icmp", byte(128)
+	}
+
+	conn, err := net.DialTimeout(network, addr.String(), timeout)
+	if err != nil {
+		conn, err = net.DialTimeout(rawNetwork, addr.String(), timeout)
+	}
+	if err != nil {
+		result.Reachability = Unreachable
+		return result, nil
+	}
+	defer conn.Close()
+
+	id := uint16(os.Getpid() & 0xffff)
+	packet := echoPacket(echoType, id, 1)
+	start := time.Now()
+	if _, err := conn.Write(packet); err != nil {
+		result.Reachability = Unreachable
+		return result, nil
+	}
+	conn.SetReadDeadline(start.Add(timeout))
+	reply := make([]byte, 512)
+	if _, err := conn.Read(reply); err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			result.Reachability = TimedOut
+			return result, nil
+		}
+		result.Reachability = Unreachable
+		return result, nil
+	}
+	result.RTT = time.Since(start)
+	result.Reachability = Reachable
+	return result, nil
+}
+
+// echoPacket builds a minimal ICMP/ICMPv6 echo request with the standard
+// 16-bit one's-complement checksum in bytes 2-3.
+func echoPacket(icmpType byte, id, seq uint16) []byte {
+	packet := []byte{
+		icmpType, 0, 0, 0,
+		byte(id >> 8), byte(id), byte(seq >> 8), byte(seq),
+	}
+	var sum uint32
+	for i := 0; i < len(packet); i += 2 {
+		sum += uint32(packet[i])<<8 | uint32(packet[i+1])
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	checksum := ^uint16(sum)
+	packet[2], packet[3] = byte(checksum>>8), byte(checksum)
+	return packet
+}
+
+// pingARP resolves addr's link-layer address via the host's ARP neighbor
+// table rather than sending a probe of its own, so it only reports hosts
+// the kernel has already discovered on the local subnet. It is currently
+// implemented only for Linux, which exposes the table at /proc/net/arp.
+func pingARP(addr *ipaddr.IPAddress, timeout time.Duration) (ProbeResult, error) {
+	result := ProbeResult{Address: addr}
+	if runtime.GOOS != "linux" {
+		result.Reachability = Unknown
+		return result, errARPUnsupported
+	}
+	f, err := os.Open("/proc/net/arp")
+	if err != nil {
+		result.Reachability = Unknown
+		return result, err
+	}
+	defer f.Close()
+
+	target := addr.String()
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header line
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 4 && fields[0] == target {
+			if fields[3] != "00:00:00:00:00:00" {
+				result.Reachability = Reachable
+				return result, nil
+			}
+			break
+		}
+	}
+	result.Reachability = Unreachable
+	return result, nil
+}
@@ -0,0 +1,243 @@
+package ipaddr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReverseDNSOptions configures ToReverseDNSStringOpts.
+type ReverseDNSOptions struct {
+	// TruncateToPrefix drops the labels beyond the network prefix length,
+	// so a /64 IPv6 prefix yields exactly 16 nibble labels plus "ip6.arpa",
+	// rather than the full 32-nibble expansion of the prefix's lowest
+	// address.
+	TruncateToPrefix bool
+
+	// RFC2317 enables classless in-addr.arpa delegation labels for IPv4
+	// prefixes that do not fall on an octet boundary, naming the zone after
+	// the network address within the partial octet (eg "0/28").
+	RFC2317 bool
+}
+
+// ToReverseDNSStringOpts renders the reverse-DNS PTR name for this
+// address's network prefix, the full host record name when it has no
+// prefix, per the given options.
+func (addr *IPv4Address) ToReverseDNSStringOpts(opts ReverseDNSOptions) (string, AddressStringException) {
+	addr = addr.init()
+	bytes := addr.GetLower().GetBytes()
+	bits := IPv4BitCount
+	if prefixLen := addr.GetNetworkPrefixLength(); prefixLen != nil && opts.TruncateToPrefix {
+		bits = prefixLen.Len()
+	}
+	octets := bits / 8
+	remainder := bits % 8
+
+	var labels []string
+	if opts.RFC2317 && remainder != 0 {
+		labels = append(labels, fmt.Sprintf("%d/%d", bytes[octets]&byte(0xff<<uint(8-remainder)), bits))
+	}
+	for i := octets - 1; i >= 0; i-- {
+		labels = append(labels, fmt.Sprintf("%d", bytes[i]))
+	}
+	labels = append(labels, "in-addr", "arpa")
+	return strings.Join(labels, "."), nil
+}
+
+// ToReverseDNSStringOpts renders the reverse-DNS PTR name for this
+// address's network prefix, truncated to exactly
+// GetNetworkPrefixLength/4 nibble labels when opts.TruncateToPrefix is
+// set, rather than the full 32-nibble expansion.
+func (addr *IPv6Address) ToReverseDNSStringOpts(opts ReverseDNSOptions) (string, AddressStringException) {
+	addr = addr.init()
+	bytes := addr.GetLower().GetBytes()
+	bits := IPv6BitCount
+	if prefixLen := addr.GetNetworkPrefixLength(); prefixLen != nil && opts.TruncateToPrefix {
+		bits = prefixLen.Len()
+	}
+	nibbles := bits / 4
+	var labels []string
+	for i := nibbles - 1; i >= 0; i-- {
+		byteVal := bytes[i/2]
+		var nibble byte
+		if i%2 == 0 {
+			nibble = byteVal >> 4
+		} else {
+			nibble = byteVal & 0xf
+		}
+		labels = append(labels, fmt.Sprintf("%x", nibble))
+	}
+	labels = append(labels, "ip6", "arpa")
+	return strings.Join(labels, "."), nil
+}
+
+// ToReverseDNSStringOpts renders the reverse-DNS PTR name for this
+// section's bytes, per the given options; it dispatches to the IPv4 or
+// IPv6 PTR layout based on the section's byte count.
+func (section *AddressSection) ToReverseDNSStringOpts(opts ReverseDNSOptions) (string, AddressStringException) {
+	if ipv4Section := section.ToIPv4AddressSection(); ipv4Section != nil {
+		addr := NewIPv4Address(ipv4Section)
+		return addr.ToReverseDNSStringOpts(opts)
+	} else if ipv6Section := section.ToIPv6AddressSection(); ipv6Section != nil {
+		addr := NewIPv6Address(ipv6Section)
+		return addr.ToReverseDNSStringOpts(opts)
+	}
+	return "", &addressStringError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+}
+
+// PTRNameIterator enumerates one reverse-DNS owner name per address in a
+// subnet, in address order, suitable for generating the PTR records of a
+// zone file one line at a time without materializing every address first.
+// Each name is the full, untruncated host record (as ToReverseDNSStringOpts
+// would render with the zero-value ReverseDNSOptions): iterating per
+// individual address already pins every label, so prefix truncation and
+// RFC 2317 delegation labels, which name a block rather than a host, do not
+// apply here.
+type PTRNameIterator struct {
+	iter IPAddressIterator
+}
+
+// PTRNameIterator returns a PTRNameIterator over every individual address
+// in addr's subnet, in address order.
+func (addr *IPAddress) PTRNameIterator() *PTRNameIterator {
+	return &PTRNameIterator{iter: addr.init().Iterator()}
+}
+
+// PTRNameIterator returns a PTRNameIterator over every individual address
+// in addr's subnet, in address order.
+func (addr *IPv4Address) PTRNameIterator() *PTRNameIterator {
+	return addr.ToIPAddress().PTRNameIterator()
+}
+
+// PTRNameIterator returns a PTRNameIterator over every individual address
+// in addr's subnet, in address order.
+func (addr *IPv6Address) PTRNameIterator() *PTRNameIterator {
+	return addr.ToIPAddress().PTRNameIterator()
+}
+
+// HasNext returns whether Next can produce another name.
+func (it *PTRNameIterator) HasNext() bool {
+	return it.iter.HasNext()
+}
+
+// Next returns the reverse-DNS owner name of the next address in the
+// subnet.
+func (it *PTRNameIterator) Next() (string, AddressStringException) {
+	next := it.iter.Next()
+	if ipv4Addr := next.ToIPv4Address(); ipv4Addr != nil {
+		return ipv4Addr.ToReverseDNSStringOpts(ReverseDNSOptions{})
+	}
+	return next.ToIPv6Address().ToReverseDNSStringOpts(ReverseDNSOptions{})
+}
+
+// ParsePTRName parses name, a reverse-DNS owner name as produced by
+// ToReverseDNSStringOpts (IPv4 "in-addr.arpa" or IPv6 "ip6.arpa", whole or
+// truncated to a prefix, with or without an RFC 2317 classless-delegation
+// label), back into the subnet or individual address it names.
+func ParsePTRName(name string) (*IPAddress, AddressStringException) {
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+	}
+	suffix := strings.Join(labels[len(labels)-2:], ".")
+	labels = labels[:len(labels)-2]
+	switch suffix {
+	case "in-addr.arpa":
+		return parsePTRNameIPv4(labels)
+	case "ip6.arpa":
+		return parsePTRNameIPv6(labels)
+	}
+	return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+}
+
+func parsePTRNameIPv4(labels []string) (*IPAddress, AddressStringException) {
+	var prefixLen PrefixLen
+	if len(labels) > 0 && strings.Contains(labels[0], "/") {
+		parts := strings.SplitN(labels[0], "/", 2)
+		val, err := strconv.ParseUint(parts[0], 10, 8)
+		if err != nil {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+		}
+		bits, err := strconv.Atoi(parts[1])
+		if err != nil || bits < 0 || bits > IPv4BitCount {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+		}
+		labels = labels[1:]
+		octets := bits / 8
+		if len(labels) != octets {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+		}
+		var bytes [IPv4ByteCount]byte
+		if err := fillPTRBytes(bytes[:octets], labels, 10, 8); err != nil {
+			return nil, err
+		}
+		bytes[octets] = byte(val)
+		prefixLen = ToPrefixLen(bits)
+		addr, verr := NewIPv4AddressFromPrefixedIP(bytes[:], prefixLen)
+		if verr != nil {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+		}
+		return addr.ToIPAddress(), nil
+	}
+	octets := len(labels)
+	if octets < 1 || octets > IPv4ByteCount {
+		return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+	}
+	var bytes [IPv4ByteCount]byte
+	if err := fillPTRBytes(bytes[:octets], labels, 10, 8); err != nil {
+		return nil, err
+	}
+	if octets < IPv4ByteCount {
+		prefixLen = ToPrefixLen(octets * 8)
+	}
+	addr, verr := NewIPv4AddressFromPrefixedIP(bytes[:], prefixLen)
+	if verr != nil {
+		return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+	}
+	return addr.ToIPAddress(), nil
+}
+
+func parsePTRNameIPv6(labels []string) (*IPAddress, AddressStringException) {
+	nibbles := len(labels)
+	if nibbles < 1 || nibbles > IPv6ByteCount*2 {
+		return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+	}
+	var bytes [IPv6ByteCount]byte
+	for i, label := range labels {
+		val, err := strconv.ParseUint(label, 16, 4)
+		if err != nil {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+		}
+		nibbleIndex := nibbles - 1 - i
+		byteIndex := nibbleIndex / 2
+		if nibbleIndex%2 == 0 {
+			bytes[byteIndex] |= byte(val) << 4
+		} else {
+			bytes[byteIndex] |= byte(val)
+		}
+	}
+	var prefixLen PrefixLen
+	if nibbles < IPv6ByteCount*2 {
+		prefixLen = ToPrefixLen(nibbles * 4)
+	}
+	addr, verr := NewIPv6AddressFromPrefixedIP(bytes[:], prefixLen)
+	if verr != nil {
+		return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+	}
+	return addr.ToIPAddress(), nil
+}
+
+// fillPTRBytes parses labels, in reverse-DNS order (least significant
+// first), as base-radix byte values no wider than bitSize bits, writing them
+// into out from its end backwards (labels[0] is the least significant, so it
+// lands last).
+func fillPTRBytes(out []byte, labels []string, radix, bitSize int) AddressStringException {
+	for i, label := range labels {
+		val, err := strconv.ParseUint(label, radix, bitSize)
+		if err != nil {
+			return &addressStringError{addressError: addressError{key: "ipaddress.error.ptr.invalid"}}
+		}
+		out[len(out)-1-i] = byte(val)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package ipaddr
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestReverseDNSStringOptsIPv4 checks full, prefix-truncated, and RFC 2317
+// classless-delegation PTR name rendering for IPv4.
+func TestReverseDNSStringOptsIPv4(t *testing.T) {
+	tests := []struct {
+		addr string
+		opts ReverseDNSOptions
+		want string
+	}{
+		{"1.2.3.4", ReverseDNSOptions{}, "4.3.2.1.in-addr.arpa"},
+		{"1.2.3.4/24", ReverseDNSOptions{TruncateToPrefix: true}, "3.2.1.in-addr.arpa"},
+		{"1.2.3.10/28", ReverseDNSOptions{TruncateToPrefix: true, RFC2317: true}, "0/28.3.2.1.in-addr.arpa"},
+	}
+	for _, tt := range tests {
+		addr, err := NewIPAddressString(tt.addr).ToAddress()
+		if err != nil {
+			t.Fatalf("%q: failed to parse: %v", tt.addr, err)
+		}
+		got, perr := addr.ToIPv4Address().ToReverseDNSStringOpts(tt.opts)
+		if perr != nil {
+			t.Fatalf("%q: ToReverseDNSStringOpts: %v", tt.addr, perr)
+		}
+		if got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
+
+// TestReverseDNSStringOptsIPv6 checks that full rendering yields 32 nibble
+// labels and that TruncateToPrefix yields exactly prefix/4 nibble labels,
+// both ending in "ip6.arpa".
+func TestReverseDNSStringOptsIPv6(t *testing.T) {
+	full, err := NewIPAddressString("2001:db8::1").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	name, perr := full.ToIPv6Address().ToReverseDNSStringOpts(ReverseDNSOptions{})
+	if perr != nil {
+		t.Fatalf("ToReverseDNSStringOpts: %v", perr)
+	}
+	if !strings.HasSuffix(name, ".ip6.arpa") {
+		t.Fatalf("got %q, want ip6.arpa suffix", name)
+	}
+	if labels := strings.Split(name, "."); len(labels) != 34 { // 32 nibbles + ip6 + arpa
+		t.Errorf("got %d labels, want 34: %q", len(labels), name)
+	}
+	if !strings.HasPrefix(name, "1.") {
+		t.Errorf("got %q, want it to start with the last nibble \"1\"", name)
+	}
+
+	truncated, err := NewIPAddressString("2001:db8::/64").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	name, perr = truncated.ToIPv6Address().ToReverseDNSStringOpts(ReverseDNSOptions{TruncateToPrefix: true})
+	if perr != nil {
+		t.Fatalf("ToReverseDNSStringOpts: %v", perr)
+	}
+	if labels := strings.Split(name, "."); len(labels) != 18 { // 16 nibbles + ip6 + arpa
+		t.Errorf("got %d labels, want 18: %q", len(labels), name)
+	}
+	if !strings.HasPrefix(name, "0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.") {
+		t.Errorf("got %q, want it to start with the /64 prefix nibbles", name)
+	}
+}
+
+// TestPTRNameIterator checks that PTRNameIterator yields one full host PTR
+// name per address in a small subnet, in address order.
+func TestPTRNameIterator(t *testing.T) {
+	addr, err := NewIPAddressString("1.2.3.0/30").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	want := []string{
+		"0.3.2.1.in-addr.arpa",
+		"1.3.2.1.in-addr.arpa",
+		"2.3.2.1.in-addr.arpa",
+		"3.3.2.1.in-addr.arpa",
+	}
+	it := addr.PTRNameIterator()
+	var got []string
+	for it.HasNext() {
+		name, err := it.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		got = append(got, name)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d names, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("name %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// TestParsePTRNameRoundTrip checks that ParsePTRName reverses
+// ToReverseDNSStringOpts for full and prefix-truncated names, for both
+// address families, including the RFC 2317 delegation form.
+func TestParsePTRNameRoundTrip(t *testing.T) {
+	tests := []struct {
+		addr string
+		opts ReverseDNSOptions
+	}{
+		{"1.2.3.4", ReverseDNSOptions{}},
+		{"1.2.3.0/24", ReverseDNSOptions{TruncateToPrefix: true}},
+		{"1.2.3.0/28", ReverseDNSOptions{TruncateToPrefix: true, RFC2317: true}},
+		{"2001:db8::1", ReverseDNSOptions{}},
+		{"2001:db8::/64", ReverseDNSOptions{TruncateToPrefix: true}},
+	}
+	for _, tt := range tests {
+		addr, err := NewIPAddressString(tt.addr).ToAddress()
+		if err != nil {
+			t.Fatalf("%q: failed to parse: %v", tt.addr, err)
+		}
+		var name string
+		var perr AddressStringException
+		if v4 := addr.ToIPv4Address(); v4 != nil {
+			name, perr = v4.ToReverseDNSStringOpts(tt.opts)
+		} else {
+			name, perr = addr.ToIPv6Address().ToReverseDNSStringOpts(tt.opts)
+		}
+		if perr != nil {
+			t.Fatalf("%q: ToReverseDNSStringOpts: %v", tt.addr, perr)
+		}
+		parsed, perr := ParsePTRName(name)
+		if perr != nil {
+			t.Fatalf("%q: ParsePTRName(%q): %v", tt.addr, name, perr)
+		}
+		wantLower := addr.GetLower()
+		if parsed.GetLower().String() != wantLower.String() {
+			t.Errorf("%q: round trip lower got %q, want %q", tt.addr, parsed.GetLower().String(), wantLower.String())
+		}
+		if tt.opts.TruncateToPrefix {
+			wantPrefix := addr.GetNetworkPrefixLength()
+			gotPrefix := parsed.GetNetworkPrefixLength()
+			if gotPrefix == nil || wantPrefix == nil || gotPrefix.Len() != wantPrefix.Len() {
+				t.Errorf("%q: round trip prefix got %v, want %v", tt.addr, gotPrefix, wantPrefix)
+			}
+		}
+	}
+}
+
+// TestParsePTRNameInvalid checks that ParsePTRName rejects malformed names
+// rather than silently misparsing them.
+func TestParsePTRNameInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"in-addr.arpa",
+		"4.3.2.1.example.com",
+		"256.3.2.1.in-addr.arpa",
+		"g.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+	}
+	for _, s := range tests {
+		if _, err := ParsePTRName(s); err == nil {
+			t.Errorf("%q: expected an error, got none", s)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package ipaddr
+
+import (
+	"io"
+	"math/big"
+	"math/rand"
+)
+
+// RandomIPv4Address generates a uniformly random IPv4 address using r.
+func RandomIPv4Address(r *rand.Rand) *IPv4Address {
+	vals := func(segmentIndex int) SegInt {
+		return SegInt(r.Intn(IPv4MaxValuePerSegment + 1))
+	}
+	return NewIPv4AddressFromValues(vals)
+}
+
+// RandomIPv6Address generates a uniformly random IPv6 address using r.
+func RandomIPv6Address(r *rand.Rand) *IPv6Address {
+	vals := func(segmentIndex int) SegInt {
+		return SegInt(r.Intn(IPv6MaxValuePerSegment + 1))
+	}
+	return NewIPv6AddressFromVals(vals)
+}
+
+// RandomIPv4Subnet generates a random IPv4 prefix block whose prefix length is
+// uniformly chosen in [0, IPv4BitCount].
+func RandomIPv4Subnet(r *rand.Rand) *IPv4Address {
+	prefLen := r.Intn(IPv4BitCount + 1)
+	return RandomIPv4Address(r).ToPrefixBlockLen(prefLen)
+}
+
+// RandomIPv6Subnet generates a random IPv6 prefix block whose prefix length is
+// uniformly chosen in [0, IPv6BitCount].
+func RandomIPv6Subnet(r *rand.Rand) *IPv6Address {
+	prefLen := r.Intn(IPv6BitCount + 1)
+	return RandomIPv6Address(r).ToPrefixBlockLen(prefLen)
+}
+
+// Random returns a single address whose value is drawn uniformly between
+// addr's lowest and highest values, mirroring ipcalc-ng's -r option. For a
+// single address or a prefix block the result lies within addr; for a
+// subnet whose values are not sequential, eg 1.2.*.5, it may not.
+func (addr *IPAddress) Random(r *rand.Rand) *IPAddress {
+	addr = addr.init()
+	lower := addrValue(addr.GetLower())
+	span := new(big.Int).Sub(addrValue(addr.GetUpper()), lower)
+	offset := new(big.Int).Rand(r, span.Add(span, bigOne()))
+	return valueToAddr(new(big.Int).Add(lower, offset), addr.GetIPVersion())
+}
+
+// RandomSubnet returns a random child prefix block of the given prefix
+// length chosen uniformly from within addr's own range.  prefixLen must be
+// no shorter than addr's own prefix length, if addr has one.
+func (addr *IPAddress) RandomSubnet(prefixLen BitCount, r *rand.Rand) *IPAddress {
+	addr = addr.init()
+	return addr.Random(r).ToPrefixBlockLen(prefixLen)
+}
+
+// RandomULA generates an RFC 4193 unique-local IPv6 address: the fixed
+// fd00::/8 prefix, a 40-bit random Global ID read from r, and a zero Subnet
+// ID, returning the containing /48.  It mirrors ipcalc-ng's ULA-generation
+// behaviour; r is typically crypto/rand.Reader so the Global ID is
+// unpredictable as RFC 4193 requires.
+func RandomULA(r io.Reader) (*IPv6Address, AddressValueException) {
+	var addrBytes [IPv6ByteCount]byte
+	addrBytes[0] = 0xfd
+	if _, err := io.ReadFull(r, addrBytes[1:6]); err != nil {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.random.source"}}
+	}
+	return NewIPv6AddressFromPrefixedIP(addrBytes[:], GetPrefixLen(48))
+}
+
+// RandomAddressStrings generates count random textual address representations,
+// mixing IPv4 and IPv6, single addresses and prefixed subnets, for use as fuzz
+// seeds or stress-test input to the parser.
+func RandomAddressStrings(r *rand.Rand, count int) []string {
+	result := make([]string, count)
+	for i := range result {
+		switch r.Intn(4) {
+		case 0:
+			result[i] = RandomIPv4Address(r).String()
+		case 1:
+			result[i] = RandomIPv4Subnet(r).String()
+		case 2:
+			result[i] = RandomIPv6Address(r).String()
+		default:
+			result[i] = RandomIPv6Subnet(r).String()
+		}
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package ipaddr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// RepairKind identifies one specific normalization ParseWithRepair is willing
+// to apply to otherwise-invalid input, so a caller can whitelist which
+// repairs it is willing to trust.
+type RepairKind int
+
+const (
+	// RepairBrackets drops a surrounding "[...]" pair, as used to disambiguate
+	// an IPv6 address from a port in a host:port string.
+	RepairBrackets RepairKind = iota
+	// RepairWhitespace strips leading and trailing whitespace.
+	RepairWhitespace
+	// RepairLeadingZeroOctet reinterprets an IPv4 octet with a leading zero,
+	// eg "086", as decimal rather than the octal value a strict inet_aton
+	// reading would give it.
+	RepairLeadingZeroOctet
+	// RepairRedundantZeroGroup collapses an explicit "0" group adjacent to a
+	// "::" compression, eg "::0:1" to "::1", which is redundant but not
+	// itself ambiguous.
+	RepairRedundantZeroGroup
+	// RepairHexCase normalizes the case of hex digits in an IPv6 address to
+	// lowercase, per RFC 5952.
+	RepairHexCase
+	// RepairZoneEncoding decodes a "%25"-encoded zone separator, as used in a
+	// URL host per RFC 6874, to the literal "%" this module's parser expects.
+	RepairZoneEncoding
+)
+
+// Repair describes one normalization ParseWithRepair applied to the input
+// before it would parse as a single, unambiguous address.
+type Repair struct {
+	Kind RepairKind
+	// Detail is a short human-readable description of what was changed, eg
+	// `dropped surrounding brackets` or `leading-zero octet read as decimal, not octal`.
+	Detail string
+}
+
+// ParseWithRepair parses s both strictly and, if the strict parse fails,
+// leniently: strict is the result of ParseIPAddressStrict under
+// DefaultParseOptions, nil if s is not already in unambiguous RFC 4291/5952
+// form. repaired is the address obtained after applying whichever of a fixed
+// set of deterministic textual repairs were needed for s to parse as a
+// single address, nil if no combination of repairs yields one. repairs
+// records, in application order, every repair that was applied to produce
+// repaired; it is empty when strict parsing already succeeded, in which case
+// repaired equals strict. err is non-nil only when neither a strict nor a
+// repaired interpretation exists.
+func ParseWithRepair(s string) (strict *IPAddress, repaired *IPAddress, repairs []Repair, err AddressStringException) {
+	if addr, strictErr := ParseIPAddressStrict(s, DefaultParseOptions); strictErr == nil {
+		return addr, addr, nil, nil
+	}
+
+	work := s
+	var applied []Repair
+
+	if trimmed := strings.TrimSpace(work); trimmed != work {
+		applied = append(applied, Repair{Kind: RepairWhitespace, Detail: "stripped leading/trailing whitespace"})
+		work = trimmed
+	}
+
+	if strings.HasPrefix(work, "[") && strings.HasSuffix(work, "]") {
+		applied = append(applied, Repair{Kind: RepairBrackets, Detail: "dropped surrounding brackets"})
+		work = work[1 : len(work)-1]
+	}
+
+	if idx := strings.Index(work, "%25"); idx >= 0 {
+		work = work[:idx] + "%" + percentDecodeZone(work[idx+3:])
+		applied = append(applied, Repair{Kind: RepairZoneEncoding, Detail: "decoded %25 zone separator"})
+	}
+
+	if !strings.ContainsRune(work, ':') {
+		if fixed, detail, ok := repairLeadingZeroOctets(work); ok {
+			work = fixed
+			applied = append(applied, Repair{Kind: RepairLeadingZeroOctet, Detail: detail})
+		}
+	} else {
+		if fixed, ok := repairRedundantZeroGroup(work); ok {
+			work = fixed
+			applied = append(applied, Repair{Kind: RepairRedundantZeroGroup, Detail: "collapsed redundant 0 group next to ::"})
+		}
+		if lower := strings.ToLower(work); lower != work {
+			work = lower
+			applied = append(applied, Repair{Kind: RepairHexCase, Detail: "lowercased hex digits"})
+		}
+	}
+
+	addr, repairedErr := NewIPAddressString(work).ToAddress()
+	if repairedErr != nil {
+		return nil, nil, nil, repairedErr
+	}
+	return nil, addr, applied, nil
+}
+
+// repairLeadingZeroOctets rewrites every IPv4 dotted-decimal octet with a
+// leading zero, eg "086", to its decimal value, eg "86", reporting whether
+// any octet needed the rewrite.
+func repairLeadingZeroOctets(s string) (fixed string, detail string, ok bool) {
+	octets := strings.Split(s, ".")
+	changed := false
+	for i, octet := range octets {
+		if len(octet) > 1 && octet[0] == '0' {
+			val := 0
+			valid := true
+			for j := 0; j < len(octet); j++ {
+				if octet[j] < '0' || octet[j] > '9' {
+					valid = false
+					break
+				}
+				val = val*10 + int(octet[j]-'0')
+			}
+			if valid && val <= 255 {
+				octets[i] = strconv.Itoa(val)
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return s, "", false
+	}
+	return strings.Join(octets, "."), "leading-zero octet read as decimal, not octal", true
+}
+
+// repairRedundantZeroGroup collapses a "0" group immediately adjacent to a
+// "::" compression, eg "::0:1" to "::1" or "1:0::" to "1::", which is
+// redundant rather than ambiguous.
+func repairRedundantZeroGroup(s string) (fixed string, ok bool) {
+	if strings.HasPrefix(s, "::0:") {
+		return "::" + s[4:], true
+	}
+	if strings.HasSuffix(s, ":0::") {
+		return s[:len(s)-4] + "::", true
+	}
+	return s, false
+}
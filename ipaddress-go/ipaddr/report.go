@@ -0,0 +1,114 @@
+package ipaddr
+
+import "math/big"
+
+// ClassfulClass identifies the historical IPv4 address classes (A through
+// E), as used by Report to note whether a subnet's prefix matches its
+// classful default.
+type ClassfulClass byte
+
+const (
+	ClassfulA ClassfulClass = 'A'
+	ClassfulB ClassfulClass = 'B'
+	ClassfulC ClassfulClass = 'C'
+	ClassfulD ClassfulClass = 'D'
+	ClassfulE ClassfulClass = 'E'
+)
+
+// SubnetReport is a structured summary of a subnet, in the style of the
+// ipcalc command-line tool: network/broadcast boundaries, usable host
+// range and count, netmask/hostmask, classful class, and special-use
+// classification.
+type SubnetReport struct {
+	Network           *IPAddress
+	Broadcast         *IPAddress // IPv4 broadcast, or the IPv6 subnet-router anycast address
+	FirstHost         *IPAddress
+	LastHost          *IPAddress
+	HostCount         *big.Int
+	PrefixLength      BitCount
+	Netmask           *IPAddress
+	Hostmask          *IPAddress
+	ClassfulClass     ClassfulClass // zero value for IPv6
+	IsDefaultClassful bool
+	Class             AddressClass
+	IsSpecialPurpose  bool
+}
+
+// Report produces a SubnetReport describing this address's containing
+// subnet.  If this address has no prefix length, the report treats it as a
+// single host (a /32 or /128).
+func (addr *IPAddress) Report() *SubnetReport {
+	addr = addr.init()
+	prefixLen := addr.GetNetworkPrefixLength()
+	bitCount := ipVersionBitCount(addr.GetIPVersion())
+	var prefLen BitCount
+	if prefixLen != nil {
+		prefLen = prefixLen.Len()
+	} else {
+		prefLen = BitCount(bitCount)
+	}
+
+	network := addr.ToZeroHost()
+	upper := addr.GetUpper()
+
+	hostCount := new(big.Int).Lsh(bigOne(), uint(bitCount-int(prefLen)))
+
+	report := &SubnetReport{
+		Network:      network,
+		Broadcast:    upper,
+		HostCount:    hostCount,
+		PrefixLength: prefLen,
+	}
+
+	if hostCount.Cmp(big.NewInt(2)) > 0 {
+		report.FirstHost = network.IncrementBig(bigOne())
+		report.LastHost = upper.IncrementBig(new(big.Int).Neg(bigOne()))
+	} else {
+		report.FirstHost = network
+		report.LastHost = upper
+	}
+
+	if addr.IsIPv4() {
+		report.Netmask = ipv4MaskFor(prefLen)
+		report.Hostmask = ipv4HostMaskFor(prefLen)
+		report.ClassfulClass, report.IsDefaultClassful = classfulInfo(network.ToIPv4Address())
+	}
+
+	report.Class = addr.Classify()
+	report.IsSpecialPurpose = addr.IsSpecialPurpose()
+
+	return report
+}
+
+func ipv4MaskFor(prefLen BitCount) *IPAddress {
+	value := new(big.Int).Lsh(bigOne(), uint(32-prefLen))
+	value.Sub(new(big.Int).Lsh(bigOne(), 32), value)
+	return valueToAddr(value, IPv4)
+}
+
+func ipv4HostMaskFor(prefLen BitCount) *IPAddress {
+	value := new(big.Int).Sub(new(big.Int).Lsh(bigOne(), uint(32-prefLen)), bigOne())
+	return valueToAddr(value, IPv4)
+}
+
+// classfulInfo returns the historical classful class of the network address
+// and whether prefLen matches that class's default prefix length.
+func classfulInfo(network *IPv4Address) (ClassfulClass, bool) {
+	first := network.GetSegment(0).GetSegmentValue()
+	prefLen := BitCount(32)
+	if network.GetNetworkPrefixLength() != nil {
+		prefLen = network.GetNetworkPrefixLength().Len()
+	}
+	switch {
+	case first < 128:
+		return ClassfulA, prefLen == 8
+	case first < 192:
+		return ClassfulB, prefLen == 16
+	case first < 224:
+		return ClassfulC, prefLen == 24
+	case first < 240:
+		return ClassfulD, prefLen == 4
+	default:
+		return ClassfulE, prefLen == 4
+	}
+}
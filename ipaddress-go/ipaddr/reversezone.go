@@ -0,0 +1,130 @@
+package ipaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReverseZone generates the reverse-DNS zone name (in-addr.arpa for IPv4,
+// ip6.arpa for IPv6) that contains this address's prefix block.  For IPv4
+// prefixes that fall on an octet boundary (/8, /16, /24) the zone is named
+// after that boundary, eg "32.168.192.in-addr.arpa" for 192.168.32.0/24.
+// For IPv4 prefixes longer than /24 (requiring RFC 2317 classless delegation),
+// the zone is named using the network address prefixed into the final octet,
+// eg "0/28.32.168.192.in-addr.arpa" for 192.168.32.0/28.
+func (addr *IPAddress) ReverseZone() (string, error) {
+	addr = addr.init()
+	prefixLen := addr.GetNetworkPrefixLength()
+	if ipv4Addr := addr.ToIPv4Address(); ipv4Addr != nil {
+		return ipv4ReverseZone(ipv4Addr, prefixLen)
+	} else if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+		return ipv6ReverseZone(ipv6Addr, prefixLen)
+	}
+	return "", fmt.Errorf("ipaddress: address has indeterminate IP version")
+}
+
+func ipv4ReverseZone(addr *IPv4Address, prefixLen PrefixLen) (string, error) {
+	bytes := addr.GetLower().GetBytes()
+	bits := IPv4BitCount
+	if prefixLen != nil {
+		bits = prefixLen.Len()
+	}
+	octets := bits / 8
+	remainder := bits % 8
+
+	var labels []string
+	for i := octets - 1; i >= 0; i-- {
+		labels = append(labels, fmt.Sprintf("%d", bytes[i]))
+	}
+	zone := strings.Join(labels, ".") + IPv4ReverseDnsSuffix
+	if octets < 4 {
+		// drop the leading "." when there are no octet labels at all (the /0 zone)
+		if zone[0] == '.' {
+			zone = zone[1:]
+		}
+	}
+	if remainder != 0 {
+		// RFC 2317 classless delegation: the zone for a sub-octet boundary is
+		// named after the network address within the next full octet.
+		zone = fmt.Sprintf("%d/%d.%s", bytes[octets]&byte(0xff<<uint(8-remainder)), bits, zone)
+	}
+	return zone, nil
+}
+
+func ipv6ReverseZone(addr *IPv6Address, prefixLen PrefixLen) (string, error) {
+	bytes := addr.GetLower().GetBytes()
+	bits := IPv6BitCount
+	if prefixLen != nil {
+		bits = prefixLen.Len()
+	}
+	nibbles := bits / 4
+	var labels []string
+	for i := nibbles - 1; i >= 0; i-- {
+		byteVal := bytes[i/2]
+		var nibble byte
+		if i%2 == 0 {
+			nibble = byteVal >> 4
+		} else {
+			nibble = byteVal & 0xf
+		}
+		labels = append(labels, fmt.Sprintf("%x", nibble))
+	}
+	return strings.Join(labels, ".") + IPv6ReverseDnsSuffix, nil
+}
+
+// ReverseZones generates the set of reverse-DNS zones needed to delegate every
+// address in a subnet whose prefix is not octet- or nibble-aligned: the subnet
+// is split at the nearest enclosing octet (IPv4) or nibble (IPv6) boundary and
+// a zone is produced for each resulting prefix block.  For an already-aligned
+// subnet this returns the single zone from ReverseZone.
+func (addr *IPAddress) ReverseZones() ([]string, error) {
+	addr = addr.init()
+	prefixLen := addr.GetNetworkPrefixLength()
+	if prefixLen == nil {
+		zone, err := addr.ReverseZone()
+		if err != nil {
+			return nil, err
+		}
+		return []string{zone}, nil
+	}
+	alignment := 8
+	if addr.IsIPv6() {
+		alignment = 4
+	}
+	bits := prefixLen.Len()
+	if bits%alignment == 0 {
+		zone, err := addr.ReverseZone()
+		if err != nil {
+			return nil, err
+		}
+		return []string{zone}, nil
+	}
+	enclosingBits := (bits / alignment) * alignment
+	enclosing := addr.ToPrefixBlockLen(enclosingBits)
+	var subBlocks []*IPAddress
+	if ipv4Addr := enclosing.ToIPv4Address(); ipv4Addr != nil {
+		iter := ipv4Addr.PrefixBlockIterator()
+		for iter.HasNext() {
+			subBlocks = append(subBlocks, iter.Next().ToIPAddress())
+		}
+	} else if ipv6Addr := enclosing.ToIPv6Address(); ipv6Addr != nil {
+		iter := ipv6Addr.PrefixBlockIterator()
+		for iter.HasNext() {
+			subBlocks = append(subBlocks, iter.Next().ToIPAddress())
+		}
+	}
+	seen := make(map[string]bool)
+	var zones []string
+	for _, sub := range subBlocks {
+		sub = sub.ToPrefixBlockLen(bits)
+		zone, err := sub.ReverseZone()
+		if err != nil {
+			return nil, err
+		}
+		if !seen[zone] {
+			seen[zone] = true
+			zones = append(zones, zone)
+		}
+	}
+	return zones, nil
+}
@@ -0,0 +1,88 @@
+package ipaddr
+
+import "testing"
+
+// TestReverseZoneIPv4Aligned checks the octet-aligned case documented on
+// ReverseZone: a /24 is named directly from its three network octets.
+func TestReverseZoneIPv4Aligned(t *testing.T) {
+	addr, err := NewIPAddressString("192.168.32.0/24").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	zone, zerr := addr.ReverseZone()
+	if zerr != nil {
+		t.Fatalf("ReverseZone: %v", zerr)
+	}
+	if want := "32.168.192.in-addr.arpa"; zone != want {
+		t.Errorf("got %q, want %q", zone, want)
+	}
+}
+
+// TestReverseZoneIPv4Delegation checks the RFC 2317 classless-delegation
+// case documented on ReverseZone: a /28 is named with the network address
+// prefixed into the final octet's label.
+func TestReverseZoneIPv4Delegation(t *testing.T) {
+	addr, err := NewIPAddressString("192.168.32.0/28").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	zone, zerr := addr.ReverseZone()
+	if zerr != nil {
+		t.Fatalf("ReverseZone: %v", zerr)
+	}
+	if want := "0/28.32.168.192.in-addr.arpa"; zone != want {
+		t.Errorf("got %q, want %q", zone, want)
+	}
+}
+
+// TestReverseZoneIPv6 checks nibble expansion for an octet-aligned (in
+// nibble terms) IPv6 prefix.
+func TestReverseZoneIPv6(t *testing.T) {
+	addr, err := NewIPAddressString("2001:db8::/32").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	zone, zerr := addr.ReverseZone()
+	if zerr != nil {
+		t.Fatalf("ReverseZone: %v", zerr)
+	}
+	if want := "8.b.d.0.1.0.0.2.ip6.arpa"; zone != want {
+		t.Errorf("got %q, want %q", zone, want)
+	}
+}
+
+// TestReverseZonesAligned checks that an already octet-aligned IPv4 prefix
+// (no RFC 2317 splitting needed) yields exactly the one zone ReverseZone
+// would produce on its own.
+func TestReverseZonesAligned(t *testing.T) {
+	addr, err := NewIPAddressString("192.168.32.0/24").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	zones, zerr := addr.ReverseZones()
+	if zerr != nil {
+		t.Fatalf("ReverseZones: %v", zerr)
+	}
+	if len(zones) != 1 {
+		t.Fatalf("got %d zones, want 1: %v", len(zones), zones)
+	}
+	if want := "32.168.192.in-addr.arpa"; zones[0] != want {
+		t.Errorf("got %q, want %q", zones[0], want)
+	}
+}
+
+// TestReverseZonesNoPrefix checks that an address with no network prefix
+// length falls back to the single ReverseZone result.
+func TestReverseZonesNoPrefix(t *testing.T) {
+	addr, err := NewIPAddressString("192.168.32.5").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	zones, zerr := addr.ReverseZones()
+	if zerr != nil {
+		t.Fatalf("ReverseZones: %v", zerr)
+	}
+	if len(zones) != 1 {
+		t.Fatalf("got %d zones, want 1: %v", len(zones), zones)
+	}
+}
@@ -0,0 +1,135 @@
+package ipaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rfc5952MappedPrefix is the ::ffff:0:0/96 block RFC 5952 section 5 requires be
+// rendered with an embedded dotted-quad IPv4 suffix.
+var rfc5952MappedPrefix = mustPrefixBlock("::ffff:0:0/96")
+
+// ToRFC5952String renders this address in the strict canonical form required
+// by RFC 5952 section 4: lowercase hex with no leading zeros in each field,
+// the "::" shorthand applied to the first of the longest run of two or more
+// consecutive all-zero fields (never to a single zero field), and no other
+// compression.  Addresses that have no run of at least two consecutive
+// zero fields are rendered with no "::" at all.  Per section 5, an address
+// in the ::ffff:0:0/96 range is rendered with its last 32 bits as a
+// dotted-quad IPv4 address, eg "::ffff:192.0.2.1".
+func (addr *IPv6Address) ToRFC5952String() string {
+	addr = addr.init()
+	if rfc5952MappedPrefix.Contains(addr.ToIPAddress()) {
+		return addr.toRFC5952MappedString()
+	}
+	segs := addr.GetSegments()
+	values := make([]SegInt, len(segs))
+	for i, seg := range segs {
+		values[i] = seg.GetSegmentValue()
+	}
+
+	start, runLen := longestZeroRun(values)
+
+	var b strings.Builder
+	if runLen >= 2 {
+		for i := 0; i < start; i++ {
+			if i > 0 {
+				b.WriteByte(IPv6SegmentSeparator)
+			}
+			fmt.Fprintf(&b, "%x", values[i])
+		}
+		b.WriteString("::")
+		for i := start + runLen; i < len(values); i++ {
+			if i > start+runLen {
+				b.WriteByte(IPv6SegmentSeparator)
+			}
+			fmt.Fprintf(&b, "%x", values[i])
+		}
+	} else {
+		for i, v := range values {
+			if i > 0 {
+				b.WriteByte(IPv6SegmentSeparator)
+			}
+			fmt.Fprintf(&b, "%x", v)
+		}
+	}
+	return b.String()
+}
+
+// ToCanonicalRFC5952String is an alias for ToRFC5952String, named to match
+// this package's ToXxx string-conversion convention.
+func (addr *IPv6Address) ToCanonicalRFC5952String() string {
+	return addr.ToRFC5952String()
+}
+
+// toRFC5952MappedString renders an address in ::ffff:0:0/96 as
+// "::ffff:a.b.c.d", per RFC 5952 section 5.
+func (addr *IPv6Address) toRFC5952MappedString() string {
+	v4 := addr.GetSegment(6).GetSegmentValue()<<16 | addr.GetSegment(7).GetSegmentValue()
+	return fmt.Sprintf("::ffff:%d.%d.%d.%d", byte(v4>>24), byte(v4>>16), byte(v4>>8), byte(v4))
+}
+
+// IsCanonicalRFC5952 reports whether s is already in the strict canonical
+// form produced by (*IPv6Address).ToRFC5952String: parsing s and
+// re-rendering it canonically must yield the identical string.
+func IsCanonicalRFC5952(s string) bool {
+	addr, err := NewIPAddressString(s).ToAddress()
+	if err != nil {
+		return false
+	}
+	ipv6Addr := addr.ToIPv6Address()
+	if ipv6Addr == nil {
+		return false
+	}
+	return ipv6Addr.ToRFC5952String() == s
+}
+
+// NormalizeString parses s as an IPv4 or IPv6 address, with an optional
+// IPv6 zone, and returns its canonical string form: RFC 5952 canonical (via
+// ToRFC5952String) for IPv6, with any zone reattached unchanged since RFC
+// 5952 does not govern zone syntax, or dotted-decimal for IPv4. It lets a
+// caller normalize untrusted input for equality comparison or hashing
+// without constructing an address of their own; for every valid s,
+// parsing NormalizeString(s) yields the same address as parsing s.
+func NormalizeString(s string) (string, error) {
+	address, zone := splitZone(s)
+	addr, err := NewIPAddressString(address).ToAddress()
+	if err != nil {
+		return "", err
+	}
+	if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+		canonical := ipv6Addr.ToRFC5952String()
+		if zone != noZone {
+			canonical += string(IPv6ZoneSeparator) + string(zone)
+		}
+		return canonical, nil
+	}
+	return addr.ToCanonicalString(), nil
+}
+
+// longestZeroRun returns the start index and length of the first (leftmost)
+// longest run of consecutive zero values in values.  If no run of length 2
+// or more exists, it returns (0, 0) when values holds no zero, or (i, 1) for
+// the first lone zero field at index i, neither of which qualifies for "::"
+// compression under RFC 5952.
+func longestZeroRun(values []SegInt) (start, length int) {
+	bestStart, bestLen := -1, 0
+	curStart, curLen := -1, 0
+	for i, v := range values {
+		if v == 0 {
+			if curLen == 0 {
+				curStart = i
+			}
+			curLen++
+			if curLen > bestLen {
+				bestStart, bestLen = curStart, curLen
+			}
+		} else {
+			curLen = 0
+		}
+	}
+	if bestStart < 0 {
+		return 0, 0
+	}
+	return bestStart, bestLen
+}
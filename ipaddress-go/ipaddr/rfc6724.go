@@ -0,0 +1,202 @@
+package ipaddr
+
+import (
+	"net"
+	"sort"
+)
+
+// rfc6724Scope mirrors the scope values from RFC 4007/6724 section 3.1,
+// ordered so that a smaller value is a narrower scope.
+type rfc6724Scope int
+
+const (
+	scopeLinkLocal rfc6724Scope = 2
+	scopeSiteLocal rfc6724Scope = 5
+	scopeGlobal    rfc6724Scope = 14
+)
+
+// scopeOf returns the RFC 6724 scope of addr, derived from the well-known
+// IPv6 prefixes (2000::/3 global, fc00::/7 unique-local treated as global
+// per RFC 6724's guidance for ULAs, fe80::/10 link-local) or, for IPv4-mapped
+// addresses, the scope of the embedded IPv4 address.
+func scopeOf(addr *IPAddress) rfc6724Scope {
+	addr = addr.Unmap()
+	if addr.IsIPv4() {
+		class := addr.Classify()
+		if class == ClassLoopback || class == ClassLinkLocal {
+			return scopeLinkLocal
+		}
+		return scopeGlobal
+	}
+	ipv6Addr := addr.ToIPv6Address()
+	if ipv6Addr == nil {
+		return scopeGlobal
+	}
+	first := ipv6Addr.GetSegment(0).GetSegmentValue()
+	switch {
+	case first&0xffc0 == 0xfe80:
+		return scopeLinkLocal
+	case first&0xfe00 == 0xfec0:
+		return scopeSiteLocal // deprecated, RFC 3879
+	default:
+		return scopeGlobal
+	}
+}
+
+// commonPrefixLen returns the number of leading bits a and b share.
+func commonPrefixLen(a, b *IPAddress) BitCount {
+	aBytes, bBytes := a.GetBytes(), b.GetBytes()
+	n := len(aBytes)
+	if len(bBytes) < n {
+		n = len(bBytes)
+	}
+	var bits BitCount
+	for i := 0; i < n; i++ {
+		x := aBytes[i] ^ bBytes[i]
+		if x == 0 {
+			bits += 8
+			continue
+		}
+		for x&0x80 == 0 {
+			bits++
+			x <<= 1
+		}
+		break
+	}
+	return bits
+}
+
+// candidateSource picks the best source address for dst from srcs, using
+// the scope- and prefix-match rules from RFC 6724 section 5 (rules 2 and 8;
+// the deprecated/temporary-address rules from 3, 4, and 7 do not apply
+// since this package has no concept of a deprecated or temporary address).
+func candidateSource(dst *IPAddress, srcs []*IPAddress) *IPAddress {
+	var best *IPAddress
+	var bestScope rfc6724Scope
+	var bestCommon BitCount
+	dstScope := scopeOf(dst)
+	for _, src := range srcs {
+		if src.GetIPVersion() != dst.GetIPVersion() {
+			continue
+		}
+		srcScope := scopeOf(src)
+		if best == nil {
+			best, bestScope, bestCommon = src, srcScope, commonPrefixLen(dst, src)
+			continue
+		}
+		// Rule 2: prefer smaller scope that is still >= destination's scope,
+		// else the smallest scope available.
+		if (srcScope >= dstScope && (bestScope < dstScope || srcScope < bestScope)) ||
+			(bestScope < dstScope && srcScope < bestScope) {
+			best, bestScope, bestCommon = src, srcScope, commonPrefixLen(dst, src)
+			continue
+		}
+		// Rule 8: prefer longer matching prefix.
+		common := commonPrefixLen(dst, src)
+		if srcScope == bestScope && common > bestCommon {
+			best, bestScope, bestCommon = src, srcScope, common
+		}
+	}
+	return best
+}
+
+// SortByRFC6724 sorts dest in place from most to least preferred destination
+// address, per the applicable rules of RFC 6724 section 6: preferring a
+// destination reachable from a usable source (rule 1), matching scope
+// (rule 2), smaller scope (rule 8), longest matching prefix (rule 9), and
+// otherwise a stable order.
+// srcs supplies the candidate source addresses to pair against each
+// destination; see SortByRFC6724FromInterfaces to discover them automatically.
+func SortByRFC6724(dest []*IPAddress, srcs []*IPAddress) {
+	type scored struct {
+		addr   *IPAddress
+		src    *IPAddress
+		scope  rfc6724Scope
+		match  bool
+		common BitCount
+		index  int
+	}
+	items := make([]scored, len(dest))
+	for i, d := range dest {
+		src := candidateSource(d, srcs)
+		var scope rfc6724Scope
+		var match bool
+		var common BitCount
+		if src != nil {
+			scope = scopeOf(d)
+			match = scopeOf(src) == scope
+			common = commonPrefixLen(d, src)
+		} else {
+			scope = scopeGlobal + 1 // no usable source: sort last
+		}
+		items[i] = scored{addr: d, src: src, scope: scope, match: match, common: common, index: i}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if (a.src == nil) != (b.src == nil) {
+			return a.src != nil // Rule 1: reachable destinations first
+		}
+		if a.match != b.match {
+			return a.match // Rule 2: matching source scope first
+		}
+		if a.scope != b.scope {
+			return a.scope < b.scope // Rule 8: smaller scope first
+		}
+		if a.common != b.common {
+			return a.common > b.common // Rule 9: longest match first
+		}
+		return a.index < b.index
+	})
+	for i, it := range items {
+		dest[i] = it.addr
+	}
+}
+
+// SortByRFC6724FromInterfaces is like SortByRFC6724, but discovers the
+// candidate source addresses from the local machine's network interfaces
+// via net.Interfaces/net.Interface.Addrs rather than taking them as a
+// parameter.
+func SortByRFC6724FromInterfaces(dest []*IPAddress) error {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return err
+	}
+	var srcs []*IPAddress
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			ipNet, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			if addr := FromNetIP(ipNet.IP); addr != nil {
+				srcs = append(srcs, addr)
+			}
+		}
+	}
+	SortByRFC6724(dest, srcs)
+	return nil
+}
+
+// FromNetIP converts a net.IP to an IPAddress, returning nil if ip is
+// neither a valid 4- nor 16-byte address.
+func FromNetIP(ip net.IP) *IPAddress {
+	if v4 := ip.To4(); v4 != nil {
+		addr, err := NewIPv4AddressFromIP(v4)
+		if err != nil {
+			return nil
+		}
+		return addr.ToIPAddress()
+	}
+	if v6 := ip.To16(); v6 != nil {
+		addr, err := NewIPv6AddressFromIP(v6)
+		if err != nil {
+			return nil
+		}
+		return addr.ToIPAddress()
+	}
+	return nil
+}
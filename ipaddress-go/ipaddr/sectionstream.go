@@ -0,0 +1,78 @@
+package ipaddr
+
+import (
+	"bufio"
+	"context"
+	"io"
+)
+
+// SectionReader adapts a SectionIterator to an io.Reader, emitting each
+// section's canonical string followed by a newline.  It consumes the
+// underlying iterator lazily, so memory use is bounded regardless of how many
+// sections the iterator produces.
+type SectionReader struct {
+	iter SectionIterator
+	buf  []byte
+}
+
+// NewSectionReader creates an io.Reader over iter.
+func NewSectionReader(iter SectionIterator) *SectionReader {
+	return &SectionReader{iter: iter}
+}
+
+// Read implements io.Reader, refilling its internal buffer one section at a
+// time as it is drained.
+func (r *SectionReader) Read(p []byte) (n int, err error) {
+	for len(r.buf) == 0 {
+		if !r.iter.HasNext() {
+			return 0, io.EOF
+		}
+		r.buf = append(r.buf, []byte(r.iter.Next().String())...)
+		r.buf = append(r.buf, '\n')
+	}
+	n = copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// SectionChan streams the sections produced by iter over a channel of the
+// given buffer size, so a consumer can range over the channel instead of
+// polling HasNext/Next.  The channel is closed once the iterator is exhausted
+// or ctx is done; in the latter case iteration stops early without draining
+// the iterator.
+func SectionChan(ctx context.Context, iter SectionIterator, bufferSize int) <-chan *AddressSection {
+	out := make(chan *AddressSection, bufferSize)
+	go func() {
+		defer close(out)
+		for iter.HasNext() {
+			select {
+			case out <- iter.Next():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// WriteSections writes every section produced by iter to w, one canonical
+// string per line, buffering output so that writes are efficient without
+// requiring all sections to be held in memory at once.
+func WriteSections(w io.Writer, iter SectionIterator) (count int, err error) {
+	bw := bufio.NewWriter(w)
+	defer func() {
+		if flushErr := bw.Flush(); err == nil {
+			err = flushErr
+		}
+	}()
+	for iter.HasNext() {
+		if _, err = bw.WriteString(iter.Next().String()); err != nil {
+			return count, err
+		}
+		if err = bw.WriteByte('\n'); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
@@ -0,0 +1,122 @@
+package ipaddr
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// This file adds sockaddr_in/sockaddr_in6 binary marshaling for IPAddress,
+// plus a ToNetAddr bridge to net.Addr, for callers handing address ranges
+// computed by this module directly to a raw socket or packet-capture API.
+//
+// MarshalSockaddr/UnmarshalSockaddr use the Linux/BSD sockaddr_in and
+// sockaddr_in6 byte layout (a native-endian uint16 address family, followed
+// by the port and address bytes in network byte order): this module has no
+// existing per-GOOS build files or syscall/cgo dependency to draw the true
+// native byte order and family constants from for every OS, so the layout
+// is fixed to the little-endian form used on Linux, the BSDs, and macOS
+// rather than varying with GOOS; it does not match Windows' WSAAPI
+// sockaddr layout.
+const (
+	sockAddrFamilyINET  = 2  // AF_INET
+	sockAddrFamilyINET6 = 10 // AF_INET6, Linux/BSD value
+)
+
+// MarshalSockaddr writes addr's sockaddr_in (IPv4, 16 bytes) or sockaddr_in6
+// (IPv6, 28 bytes) byte representation, for the given port, into the start
+// of buf, and returns the number of bytes written; an error is returned if
+// buf is shorter than that. addr must represent a single address, as with
+// ToNetIPAddr; an IPv6 zone, if present, is resolved to sin6_scope_id via
+// ResolveZone.
+func (addr *IPAddress) MarshalSockaddr(buf []byte, port uint16) (n int, err error) {
+	addr = addr.init()
+	if ipv4Addr := addr.ToIPv4Address(); ipv4Addr != nil {
+		if len(buf) < 16 {
+			return 0, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		start := 0
+		copy(buf[start:start+16], make([]byte, 16))
+		binary.LittleEndian.PutUint16(buf[start:], sockAddrFamilyINET)
+		binary.BigEndian.PutUint16(buf[start+2:], port)
+		copy(buf[start+4:], ipv4Addr.GetBytes())
+		return 16, nil
+	} else if ipv6Addr := addr.ToIPv6Address(); ipv6Addr != nil {
+		if len(buf) < 28 {
+			return 0, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		start := 0
+		copy(buf[start:start+28], make([]byte, 28))
+		binary.LittleEndian.PutUint16(buf[start:], sockAddrFamilyINET6)
+		binary.BigEndian.PutUint16(buf[start+2:], port)
+		// sin6_flowinfo, buf[start+4:start+8], left zero
+		copy(buf[start+8:], ipv6Addr.GetBytes())
+		var scopeID uint32
+		if zone := ipv6Addr.GetZone(); zone != noZone {
+			scopeID, err = ResolveZone(string(zone))
+			if err != nil {
+				return 0, err
+			}
+		}
+		binary.LittleEndian.PutUint32(buf[start+24:], scopeID)
+		return 28, nil
+	}
+	return 0, &addressValueError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+}
+
+// UnmarshalSockaddr parses data as a sockaddr_in or sockaddr_in6 byte
+// representation, per MarshalSockaddr's layout, returning the address and
+// port it encodes. A non-zero sin6_scope_id is resolved back to a zone via
+// the local interface table, and dropped silently if it no longer
+// identifies an interface.
+func UnmarshalSockaddr(data []byte) (addr *IPAddress, port uint16, err error) {
+	if len(data) < 2 {
+		return nil, 0, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	switch binary.LittleEndian.Uint16(data) {
+	case sockAddrFamilyINET:
+		if len(data) < 16 {
+			return nil, 0, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		port = binary.BigEndian.Uint16(data[2:])
+		ipv4Addr, verr := NewIPv4AddressFromIP(data[4:8])
+		if verr != nil {
+			return nil, 0, verr
+		}
+		return ipv4Addr.ToIPAddress(), port, nil
+	case sockAddrFamilyINET6:
+		if len(data) < 28 {
+			return nil, 0, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+		}
+		port = binary.BigEndian.Uint16(data[2:])
+		ipv6Addr, verr := NewIPv6AddressFromIP(data[8:24])
+		if verr != nil {
+			return nil, 0, verr
+		}
+		if scopeID := binary.LittleEndian.Uint32(data[24:28]); scopeID != 0 {
+			if iface, ifaceErr := net.InterfaceByIndex(int(scopeID)); ifaceErr == nil {
+				ipv6Addr = NewIPv6AddressZoned(ipv6Addr.GetSection(), Zone(iface.Name))
+			}
+		}
+		return ipv6Addr.ToIPAddress(), port, nil
+	}
+	return nil, 0, &addressValueError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+}
+
+// ToNetAddr converts addr, together with the given port, to a net.Addr:
+// *net.UDPAddr for network "udp"/"udp4"/"udp6", *net.TCPAddr for
+// "tcp"/"tcp4"/"tcp6", or *net.IPAddr for any other network (including the
+// empty string). addr must represent a single address, as with
+// ToNetIPAddr; IPv6 zones are preserved.
+func (addr *IPAddress) ToNetAddr(network string, port int) net.Addr {
+	addr = addr.init()
+	netIPAddr := addr.GetNetIP()
+	ip, zone := netIPAddr.IP, netIPAddr.Zone
+	switch network {
+	case "udp", "udp4", "udp6":
+		return &net.UDPAddr{IP: ip, Port: port, Zone: zone}
+	case "tcp", "tcp4", "tcp6":
+		return &net.TCPAddr{IP: ip, Port: port, Zone: zone}
+	default:
+		return &net.IPAddr{IP: ip, Zone: zone}
+	}
+}
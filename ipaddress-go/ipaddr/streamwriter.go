@@ -0,0 +1,39 @@
+package ipaddr
+
+import (
+	"bufio"
+	"io"
+)
+
+// WriteAddresses writes each address produced by iter to w, one per line, using
+// the canonical string form, stopping once maxBytes have been written (the write
+// that would cross the budget is not performed).  It returns the number of
+// addresses written and the number of bytes written.  A maxBytes of 0 means no
+// limit.
+//
+// This lets callers stream an enormous subnet or range iterator (eg a /8 or a
+// large IPv6 block) to a file or socket without materializing every address in
+// memory at once.
+func WriteAddresses(w io.Writer, iter IPAddressIterator, maxBytes int64) (count int, written int64, err error) {
+	bw := bufio.NewWriter(w)
+	defer func() {
+		if flushErr := bw.Flush(); err == nil {
+			err = flushErr
+		}
+	}()
+	for iter.HasNext() {
+		addr := iter.Next()
+		line := addr.String() + "\n"
+		lineLen := int64(len(line))
+		if maxBytes > 0 && written+lineLen > maxBytes {
+			return count, written, nil
+		}
+		n, werr := bw.WriteString(line)
+		written += int64(n)
+		if werr != nil {
+			return count, written, werr
+		}
+		count++
+	}
+	return count, written, nil
+}
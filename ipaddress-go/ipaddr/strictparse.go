@@ -0,0 +1,206 @@
+package ipaddr
+
+import (
+	"strings"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr/debug"
+)
+
+// ParseOptions controls the strictness of ParseIPAddressStrict.  The zero value
+// is the strict default: no leading zeros, no non-canonical IPv6 forms, and no
+// embedded IPv4-in-IPv6 unless explicitly allowed.
+type ParseOptions struct {
+	// AllowLeadingZeros permits IPv4 octets such as "010" that would otherwise be
+	// rejected as ambiguous between octal and decimal interpretation (the
+	// CVE-2021-29923 class of bug).  Defaults to false.
+	AllowLeadingZeros bool
+
+	// AllowNonCanonicalIPv6 permits IPv6 forms that are valid but not in RFC 5952
+	// canonical form, eg upper-case hex digits or a missing "::" compression where
+	// one is possible.  Defaults to false.
+	AllowNonCanonicalIPv6 bool
+
+	// AllowEmbeddedIPv4 permits embedded IPv4-in-IPv6 notation for IPv4-mapped
+	// addresses, eg "::ffff:1.2.3.4". Only the IPv4-mapped form is checked by
+	// this option: other dotted forms, eg "::1.2.3.4", are not rejected by it.
+	// Defaults to false.
+	AllowEmbeddedIPv4 bool
+
+	// CompatMode, when true, disables every check above and falls back to the
+	// permissive behavior of NewIPAddressString, for callers migrating gradually
+	// to the strict entrypoint.
+	CompatMode bool
+
+	// RFC6943, when true, additionally rejects trailing whitespace, per RFC
+	// 6943's recommended profile for parsing addresses from untrusted,
+	// security-sensitive input. Defaults to false.
+	RFC6943 bool
+}
+
+// DefaultParseOptions is the strict, RFC-conformant configuration used when no
+// options are supplied: it is equivalent to the zero value of ParseOptions.
+var DefaultParseOptions = ParseOptions{}
+
+// ParseIPAddressStrict parses s into an IPAddress under the given options,
+// rejecting ambiguous or non-conformant input that the lenient NewIPAddressString
+// entrypoint accepts for backward compatibility.  Security-sensitive callers such
+// as SSRF filters and ACLs should prefer this entrypoint so that a given input
+// string has exactly one unambiguous interpretation.
+func ParseIPAddressStrict(s string, opts ParseOptions) (*IPAddress, AddressStringException) {
+	if opts.CompatMode {
+		return NewIPAddressString(s).ToAddress()
+	}
+	if !opts.AllowLeadingZeros {
+		if err := rejectLeadingZeroOctets(s); err != nil {
+			return nil, err
+		}
+	}
+	if opts.RFC6943 {
+		if err := rejectTrailingWhitespace(s); err != nil {
+			return nil, err
+		}
+	}
+	addr, err := NewIPAddressString(s).ToAddress()
+	if err != nil {
+		return nil, err
+	}
+	if !opts.AllowNonCanonicalIPv6 && addr.IsIPv6() {
+		if addr.ToIPv6Address().ToCanonicalString() != s {
+			return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ipv6.non.canonical"}}
+		}
+	}
+	if !opts.AllowEmbeddedIPv4 && addr.IsIPv6() && addr.ToIPv6Address().GetSegment(5).GetSegmentValue() == 0xffff && strings.Contains(s, ".") {
+		return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ipv6.embedded.ipv4"}}
+	}
+	if debug.IsStrictPrefixEnabled() && addr.GetNetworkPrefixLength() != nil && !addr.IsPrefixBlock() {
+		return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.prefix.host.bits.set"}}
+	}
+	return addr, nil
+}
+
+// rejectLeadingZeroOctets rejects any dotted-decimal IPv4 octet with a leading
+// zero (eg "010.0.0.1"), which different parsers have historically interpreted
+// as either octal or decimal, the ambiguity behind CVE-2021-29923. The same
+// ambiguity applies to an embedded IPv4-in-IPv6 quad (eg "::ffff:010.0.0.1"),
+// so the dotted-decimal field checked is either the whole of s, for plain
+// IPv4, or the field following the last ':', for IPv6.
+func rejectLeadingZeroOctets(s string) AddressStringException {
+	host := s
+	if slash := strings.IndexByte(s, '/'); slash >= 0 {
+		host = s[:slash]
+	}
+	if zone := strings.IndexByte(host, IPv6ZoneSeparator); zone >= 0 {
+		host = host[:zone]
+	}
+	quad := host
+	if colon := strings.LastIndexByte(host, ':'); colon >= 0 {
+		quad = host[colon+1:]
+	}
+	if !strings.ContainsRune(quad, '.') {
+		return nil
+	}
+	for _, octet := range strings.Split(quad, ".") {
+		if len(octet) > 1 && octet[0] == '0' {
+			return &addressStringError{addressError: addressError{key: "ipaddress.error.ipv4.leading.zero"}}
+		}
+	}
+	return nil
+}
+
+// rejectTrailingWhitespace rejects s if it ends in a space or tab, which
+// NewIPAddressString otherwise silently trims.
+func rejectTrailingWhitespace(s string) AddressStringException {
+	if len(s) > 0 {
+		last := s[len(s)-1]
+		if last == ' ' || last == '\t' {
+			return &addressStringError{addressError: addressError{key: "ipaddress.error.trailing.whitespace"}}
+		}
+	}
+	return nil
+}
+
+// LintCompressible reports, as advice rather than a parse error, whether s is
+// a syntactically valid IPv6 address written without "::" whose RFC 5952
+// form does compress a run of zero groups, eg "1:0:0:0:0:0:0:2". A string
+// that already contains "::" is not reported, even where a different run
+// could have been compressed. It does not affect parsing; ok is false
+// whenever no such improvement exists, including when s is not a
+// recognizable IPv6 address at all.
+func LintCompressible(s string) (reason string, ok bool) {
+	addr, err := NewIPAddressString(s).ToAddress()
+	if err != nil {
+		return "", false
+	}
+	ipv6Addr := addr.ToIPv6Address()
+	if ipv6Addr == nil {
+		return "", false
+	}
+	if canonical := ipv6Addr.ToRFC5952String(); canonical != s && !strings.Contains(s, "::") {
+		if strings.Contains(canonical, "::") {
+			return "a longer run of zero groups could be compressed with \"::\"", true
+		}
+	}
+	return "", false
+}
+
+// StrictParseError is returned by ParseAddr when s fails one of the RFC 6943
+// conformance checks that this package's default, permissive parsing does
+// not enforce. Offender carries the specific substring of s responsible for
+// the rejection, for tooling that wants to underline it in a diagnostic.
+type StrictParseError struct {
+	Input    string
+	Reason   string
+	Offender string
+}
+
+// Error renders e in the form `ParseAddr("010.0.0.1"): IPv4 field has octet
+// with leading zero`.
+func (e *StrictParseError) Error() string {
+	return "ParseAddr(\"" + e.Input + "\"): " + e.Reason
+}
+
+// ParseAddr parses s under RFC 6943 strict conformance and returns a
+// *StrictParseError, rather than the usual AddressStringException, when s is
+// rejected: IPv4 octets with a leading zero, including one inside an embedded
+// IPv4-in-IPv6 quad, unless the octet is literally "0", and any trailing
+// whitespace. This package's default parsing via NewIPAddressString remains
+// permissive; ParseAddr is an explicit opt-in for callers, such as an SSRF
+// filter or ACL, that need a single unambiguous reading per input along with
+// a diagnostic precise enough to show the user what was wrong.
+func ParseAddr(s string) (*IPAddress, error) {
+	if err := rejectTrailingWhitespace(s); err != nil {
+		return nil, &StrictParseError{Input: s, Reason: "trailing whitespace"}
+	}
+	if err := rejectLeadingZeroOctets(s); err != nil {
+		offender := leadingZeroOctet(s)
+		return nil, &StrictParseError{Input: s, Reason: "IPv4 field has octet with leading zero", Offender: offender}
+	}
+	addr, err := NewIPAddressString(s).ToAddress()
+	if err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
+
+// leadingZeroOctet returns the first dotted-decimal octet of s, or of its
+// embedded IPv4-in-IPv6 quad, found with a leading zero; it is called only
+// after rejectLeadingZeroOctets has already confirmed one exists.
+func leadingZeroOctet(s string) string {
+	host := s
+	if slash := strings.IndexByte(s, '/'); slash >= 0 {
+		host = s[:slash]
+	}
+	if zone := strings.IndexByte(host, IPv6ZoneSeparator); zone >= 0 {
+		host = host[:zone]
+	}
+	quad := host
+	if colon := strings.LastIndexByte(host, ':'); colon >= 0 {
+		quad = host[colon+1:]
+	}
+	for _, octet := range strings.Split(quad, ".") {
+		if len(octet) > 1 && octet[0] == '0' {
+			return octet
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package ipaddr
+
+import "math/bits"
+
+// Split divides addr, a prefix block, into the set of prefix blocks of length
+// newPrefixLen that exactly cover it, in the style of ipcalc-ng's -S/--split.
+// newPrefixLen must be greater than or equal to addr's own prefix length; if
+// it is equal, Split returns addr alone.  It is an error for newPrefixLen to
+// be coarser than addr's own prefix length, or to exceed the address bit
+// count.
+func (addr *IPAddress) Split(newPrefixLen BitCount) ([]*IPAddress, AddressValueException) {
+	addr = addr.init()
+	prefixLen := addr.GetNetworkPrefixLength()
+	if prefixLen == nil {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixLength.required"}}
+	}
+	if newPrefixLen < prefixLen.Len() {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixLength.invalid"}}
+	}
+	bitCount := BitCount(ipVersionBitCount(addr.GetIPVersion()))
+	if newPrefixLen > bitCount {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+	}
+	block := addr.ToPrefixBlockLen(newPrefixLen)
+	var result []*IPAddress
+	if ipv4Addr := block.ToIPv4Address(); ipv4Addr != nil {
+		iter := ipv4Addr.PrefixBlockIterator()
+		for iter.HasNext() {
+			result = append(result, iter.Next().ToIPAddress())
+		}
+	} else if ipv6Addr := block.ToIPv6Address(); ipv6Addr != nil {
+		iter := ipv6Addr.PrefixBlockIterator()
+		for iter.HasNext() {
+			result = append(result, iter.Next().ToIPAddress())
+		}
+	}
+	return result, nil
+}
+
+// SplitInto splits addr into the smallest number of equal-sized child prefix
+// blocks that is at least nParts, by extending addr's prefix length just far
+// enough to yield that many blocks.  It is an error for nParts to be
+// non-positive or to require a prefix length beyond the address bit count.
+func (addr *IPAddress) SplitInto(nParts int) ([]*IPAddress, AddressValueException) {
+	addr = addr.init()
+	if nParts <= 0 {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+	}
+	prefixLen := addr.GetNetworkPrefixLength()
+	if prefixLen == nil {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixLength.required"}}
+	}
+	extraBits := BitCount(bits.Len(uint(nParts - 1)))
+	return addr.Split(prefixLen.Len() + extraBits)
+}
+
+// SplitByHostCount splits addr into the smallest number of equal-sized child
+// prefix blocks each able to hold at least hosts usable host addresses,
+// reserving the network and broadcast addresses on IPv4 child blocks as
+// ipcalc-ng does.  It is an error for the resulting prefix length to be
+// coarser than addr's own.
+func (addr *IPAddress) SplitByHostCount(hosts uint64) ([]*IPAddress, AddressValueException) {
+	addr = addr.init()
+	reserved := uint64(0)
+	if addr.IsIPv4() {
+		reserved = 2
+	}
+	hostBits := BitCount(0)
+	for (uint64(1) << uint(hostBits)) < hosts+reserved {
+		hostBits++
+	}
+	bitCount := BitCount(ipVersionBitCount(addr.GetIPVersion()))
+	newPrefixLen := bitCount - hostBits
+	return addr.Split(newPrefixLen)
+}
+
+// Aggregate merges a list of addresses and prefix blocks into the minimal set
+// of disjoint prefix blocks covering the same addresses, coalescing adjacent
+// and overlapping blocks.  All addresses must share the same IP version.
+func Aggregate(addrs []*IPAddress) []*IPAddress {
+	var builder IPSetBuilder
+	for _, addr := range addrs {
+		builder.Add(addr)
+	}
+	return builder.Build().Prefixes()
+}
@@ -0,0 +1,117 @@
+package ipaddr
+
+import "testing"
+
+// TestSplit checks that Split carves a prefix block into the expected
+// number of equal-sized child blocks, for both IPv4 and IPv6.
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		addr         string
+		newPrefixLen BitCount
+		wantCount    int
+	}{
+		{"10.100.1.0/24", 26, 4},
+		{"2001:db8::/32", 34, 4},
+		{"10.100.1.0/24", 24, 1},
+	}
+	for _, tt := range tests {
+		addr, err := NewIPAddressString(tt.addr).ToAddress()
+		if err != nil {
+			t.Fatalf("%q: failed to parse: %v", tt.addr, err)
+		}
+		got, serr := addr.Split(tt.newPrefixLen)
+		if serr != nil {
+			t.Fatalf("%q: Split(%d): %v", tt.addr, tt.newPrefixLen, serr)
+		}
+		if len(got) != tt.wantCount {
+			t.Errorf("%q: Split(%d) got %d blocks, want %d", tt.addr, tt.newPrefixLen, len(got), tt.wantCount)
+		}
+	}
+}
+
+// TestSplitErrors checks that Split rejects a coarser prefix length and an
+// address with no network prefix length.
+func TestSplitErrors(t *testing.T) {
+	addr, err := NewIPAddressString("10.100.1.0/24").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if _, serr := addr.Split(16); serr == nil {
+		t.Error("Split(16) on a /24: expected an error, got none")
+	}
+	unprefixed, err := NewIPAddressString("10.100.1.0").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if _, serr := unprefixed.Split(26); serr == nil {
+		t.Error("Split on an address with no prefix length: expected an error, got none")
+	}
+}
+
+// TestSplitInto checks that SplitInto extends the prefix just far enough to
+// yield at least nParts equal blocks.
+func TestSplitInto(t *testing.T) {
+	addr, err := NewIPAddressString("10.100.1.0/24").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	tests := []struct {
+		nParts    int
+		wantCount int
+	}{
+		{1, 1},
+		{3, 4},
+		{4, 4},
+		{5, 8},
+	}
+	for _, tt := range tests {
+		got, serr := addr.SplitInto(tt.nParts)
+		if serr != nil {
+			t.Fatalf("SplitInto(%d): %v", tt.nParts, serr)
+		}
+		if len(got) != tt.wantCount {
+			t.Errorf("SplitInto(%d) got %d blocks, want %d", tt.nParts, len(got), tt.wantCount)
+		}
+	}
+}
+
+// TestAggregate checks that Aggregate coalesces adjacent prefix blocks into
+// their single covering parent.
+func TestAggregate(t *testing.T) {
+	a, err := NewIPAddressString("10.0.0.0/25").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	b, err := NewIPAddressString("10.0.0.128/25").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	result := Aggregate([]*IPAddress{a, b})
+	if len(result) != 1 {
+		t.Fatalf("got %d blocks, want 1: %v", len(result), result)
+	}
+	want, err := NewIPAddressString("10.0.0.0/24").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if result[0].GetLower().String() != want.GetLower().String() || result[0].GetNetworkPrefixLength().Len() != want.GetNetworkPrefixLength().Len() {
+		t.Errorf("got %v, want %v", result[0], want)
+	}
+}
+
+// TestAggregateDisjoint checks that Aggregate leaves non-adjacent blocks
+// uncoalesced.
+func TestAggregateDisjoint(t *testing.T) {
+	a, err := NewIPAddressString("10.0.0.0/24").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	b, err := NewIPAddressString("192.168.0.0/24").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	result := Aggregate([]*IPAddress{a, b})
+	if len(result) != 2 {
+		t.Fatalf("got %d blocks, want 2: %v", len(result), result)
+	}
+}
@@ -0,0 +1,115 @@
+package ipaddr
+
+import (
+	"fmt"
+	"net/netip"
+	"sync"
+)
+
+// SyntheticEncoder maps a non-IP identifier, such as a .onion service key
+// hash, to an IPv6Address within the prefix it is registered under.
+type SyntheticEncoder func(id []byte) (*IPv6Address, error)
+
+// SyntheticDecoder recovers the identifier embedded in addr by a matching
+// SyntheticEncoder, reporting false if addr does not hold one.
+type SyntheticDecoder func(addr *IPv6Address) ([]byte, bool)
+
+type syntheticBlock struct {
+	prefix netip.Prefix
+	encode SyntheticEncoder
+	decode SyntheticDecoder
+}
+
+// SyntheticAddressConverter maps non-IP identifiers to IPv6 addresses within
+// a set of registered prefixes and back, the way OnionCat embeds a .onion
+// service's key hash in fd87:d87e:eb43::/48 and CJDNS embeds a public key
+// hash in fc00::/8. Registering more than one prefix lets a single converter
+// serve several overlay networks at once; Decode tries each registered
+// prefix containing addr, in registration order.
+type SyntheticAddressConverter struct {
+	mu     sync.RWMutex
+	blocks []syntheticBlock
+}
+
+// NewSyntheticAddressConverter returns a SyntheticAddressConverter with no
+// registered prefixes.
+func NewSyntheticAddressConverter() *SyntheticAddressConverter {
+	return &SyntheticAddressConverter{}
+}
+
+// DefaultSyntheticConverter is the converter IPAddressString.AsSyntheticID
+// consults. Overlay-network code registers its prefixes here at init time,
+// the way codecs register themselves with image or database/sql.
+var DefaultSyntheticConverter = NewSyntheticAddressConverter()
+
+// Register adds a (prefix, encode, decode) triple to c. A later call whose
+// prefix overlaps an earlier one is accepted; Decode still tries every
+// matching block in registration order, so the first registered encoder for
+// a given address space takes precedence.
+func (c *SyntheticAddressConverter) Register(prefix netip.Prefix, encode SyntheticEncoder, decode SyntheticDecoder) error {
+	if !prefix.IsValid() || !prefix.Addr().Is6() {
+		return fmt.Errorf("ipaddress: %s is not a valid IPv6 prefix", prefix)
+	}
+	if encode == nil || decode == nil {
+		return fmt.Errorf("ipaddress: both encode and decode are required to register a synthetic address block")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blocks = append(c.blocks, syntheticBlock{prefix: prefix, encode: encode, decode: decode})
+	return nil
+}
+
+// Encode maps id to an IPv6Address using the first registered block whose
+// encoder accepts id, trying blocks in registration order.
+func (c *SyntheticAddressConverter) Encode(id []byte) (*IPv6Address, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var lastErr error
+	for _, b := range c.blocks {
+		addr, err := b.encode(id)
+		if err == nil {
+			return addr, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("ipaddress: no synthetic address block is registered")
+	}
+	return nil, lastErr
+}
+
+// Decode recovers the identifier embedded in addr, trying every registered
+// block whose prefix contains addr, in registration order. ok is false if no
+// registered block's decoder recognizes addr.
+func (c *SyntheticAddressConverter) Decode(addr *IPv6Address) (id []byte, ok bool) {
+	if addr == nil {
+		return nil, false
+	}
+	netAddr := addr.ToNetIPAddr()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, b := range c.blocks {
+		if netAddr.IsValid() && !b.prefix.Contains(netAddr) {
+			continue
+		}
+		if id, ok := b.decode(addr); ok {
+			return id, true
+		}
+	}
+	return nil, false
+}
+
+// AsSyntheticID parses str and, if it holds a single IPv6 address, decodes it
+// via DefaultSyntheticConverter, reporting false if str does not parse as an
+// IPv6 address or no registered synthetic address block recognizes it.
+func (str *IPAddressString) AsSyntheticID() (id []byte, ok bool) {
+	addr, err := str.ToAddress()
+	if err != nil || addr == nil {
+		return nil, false
+	}
+	ipv6Addr := addr.ToIPv6Address()
+	if ipv6Addr == nil {
+		return nil, false
+	}
+	return DefaultSyntheticConverter.Decode(ipv6Addr)
+}
@@ -1292,20 +1292,20 @@ func (t ipAddressRangeTester) run() {
 	t.testContains("192.13.1.0/25", "192.13.1.1-127", false)
 
 	t.testNotContains("192.13.1.0/25", "192.13.1.1-255")
-	//testContainsNonZeroHosts("192.13.1.1-127", "192.13.1.0/25")
-	//testContainsNonZeroHosts("192.13.1.1-255", "192.13.1.0/24")
-	//testNotContainsNonZeroHosts("192.13.1.1-255", "192.13.1.0/23")
-	//
-	//testContainsNonZeroHosts("192.13.1.0-255", "192.13.1.0/23")
+	t.testContainsNonZeroHosts("192.13.1.1-127", "192.13.1.0/25")
+	t.testContainsNonZeroHosts("192.13.1.1-255", "192.13.1.0/24")
+	t.testNotContainsNonZeroHosts("192.13.1.1-255", "192.13.1.0/23")
+
+	t.testContainsNonZeroHosts("192.13.1.0-255", "192.13.1.0/23")
 
 	t.testContains("192.13.1.0-255", "192.13.1.0/23", false)
 
 	t.testContains("192.13.0-1.0-255", "192.13.1.0/23", false)
 	t.testContains("192.13.0-1.0-255", "192.13.0.0/23", true)
 
-	//testContainsNonZeroHosts("::192:13:1:1-7fff", "::192:13:1:0/113")
-	//testContainsNonZeroHosts("::192:13:1:1-ffff", "::192:13:1:0/112")
-	//testNotContainsNonZeroHosts("::192:13:1:1-ffff", "::192:13:1:0/111")
+	t.testContainsNonZeroHosts("::192:13:1:1-7fff", "::192:13:1:0/113")
+	t.testContainsNonZeroHosts("::192:13:1:1-ffff", "::192:13:1:0/112")
+	t.testNotContainsNonZeroHosts("::192:13:1:1-ffff", "::192:13:1:0/111")
 
 	t.testSubnet("1.2-4.3.4", "255.255.254.255", 24, "1.2-4.2.4/24", "1.2-4.2.4", "1.2-4.3.4/24")
 	t.testSubnet("1.2-4.3.4", "255.248.254.255", 24, "1.0.2.4/24", "1.0.2.4", "1.2-4.3.4/24")
@@ -1891,6 +1891,24 @@ func (t ipAddressRangeTester) testPrefix(original string, prefixLength ipaddr.Pr
 	t.incrementTestCount()
 }
 
+func (t ipAddressRangeTester) testContainsNonZeroHosts(container, contained string) {
+	containerAddr := t.createAddress(container).GetAddress()
+	containedAddr := t.createAddress(contained).GetAddress()
+	if !containerAddr.ContainsNonZeroHosts(containedAddr) {
+		t.addFailure(newFailure("contains non-zero hosts failed: "+contained, containerAddr.ToAddressString()))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressRangeTester) testNotContainsNonZeroHosts(container, contained string) {
+	containerAddr := t.createAddress(container).GetAddress()
+	containedAddr := t.createAddress(contained).GetAddress()
+	if containerAddr.ContainsNonZeroHosts(containedAddr) {
+		t.addFailure(newFailure("contains non-zero hosts should have failed: "+contained, containerAddr.ToAddressString()))
+	}
+	t.incrementTestCount()
+}
+
 func (t ipAddressRangeTester) testTrees() {
 
 	t.testTree("1.2.3.4", []string{
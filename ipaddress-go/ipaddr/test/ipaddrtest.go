@@ -1297,6 +1297,74 @@ func (t ipAddressTester) run() {
 	t.ipv6test(true, "a:b:c:d:e:f:0::")
 	t.ipv6test(false, "':10.0.0.1")
 
+	t.testFullyExpanded("::1", "0000:0000:0000:0000:0000:0000:0000:0001")
+	t.testFullyExpanded("2001:db8::1", "2001:0db8:0000:0000:0000:0000:0000:0001")
+	t.testFullyExpanded("::", "0000:0000:0000:0000:0000:0000:0000:0000")
+	t.testFullyExpanded("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff")
+
+	// Address classification: IsGlobal, IsShared, IsBenchmarking,
+	// IsDocumentation, IsReserved, IsUniqueLocal, IsUnicastLinkLocal,
+	// IsIPv4Mapped, IsIPv4Compatible, each exercised for a clean match, a
+	// clean non-match, and (where a boundary exists to straddle) the
+	// tri-valued partial-overlap case.
+	t.testClassification("8.8.8.8", "global", ipaddr.TriTrue)
+	t.testClassification("10.0.0.1", "global", ipaddr.TriFalse)
+	t.testClassification("10.0.0.0/7", "global", ipaddr.TriUnknown) // half 10.0.0.0/8 private, half global
+
+	t.testClassification("192.168.1.1", "global", ipaddr.TriFalse)
+	t.testClassification("192.168.1.1", "shared", ipaddr.TriFalse)
+	t.testClassification("100.64.0.1", "shared", ipaddr.TriTrue)
+	t.testClassification("8.8.8.8", "shared", ipaddr.TriFalse)
+	t.testClassification("100.0.0.0/9", "shared", ipaddr.TriUnknown) // half inside 100.64.0.0/10, half outside
+
+	t.testClassification("198.18.0.1", "benchmarking", ipaddr.TriTrue)
+	t.testClassification("2001:2::1", "benchmarking", ipaddr.TriTrue)
+	t.testClassification("8.8.8.8", "benchmarking", ipaddr.TriFalse)
+	t.testClassification("198.16.0.0/14", "benchmarking", ipaddr.TriUnknown) // half 198.18.0.0/15, half not
+
+	t.testClassification("192.0.2.1", "documentation", ipaddr.TriTrue)
+	t.testClassification("198.51.100.1", "documentation", ipaddr.TriTrue)
+	t.testClassification("203.0.113.1", "documentation", ipaddr.TriTrue)
+	t.testClassification("2001:db8::1", "documentation", ipaddr.TriTrue)
+	t.testClassification("8.8.8.8", "documentation", ipaddr.TriFalse)
+
+	t.testClassification("240.0.0.1", "reserved", ipaddr.TriTrue)
+	t.testClassification("8.8.8.8", "reserved", ipaddr.TriFalse)
+	t.testClassification("2001:db8::1", "reserved", ipaddr.TriFalse)
+
+	t.testClassification("fc00::1", "uniqueLocal", ipaddr.TriTrue)
+	t.testClassification("2001:db8::1", "uniqueLocal", ipaddr.TriFalse)
+	t.testClassification("10.0.0.1", "uniqueLocal", ipaddr.TriFalse)
+
+	t.testClassification("169.254.0.1", "unicastLinkLocal", ipaddr.TriTrue)
+	t.testClassification("fe80::1", "unicastLinkLocal", ipaddr.TriTrue)
+	t.testClassification("8.8.8.8", "unicastLinkLocal", ipaddr.TriFalse)
+
+	t.testClassification("::ffff:1.2.3.4", "ipv4Mapped", ipaddr.TriTrue)
+	t.testClassification("2001:db8::1", "ipv4Mapped", ipaddr.TriFalse)
+	t.testClassification("1.2.3.4", "ipv4Mapped", ipaddr.TriFalse)
+
+	t.testClassification("::1.2.3.4", "ipv4Compatible", ipaddr.TriTrue)
+	t.testClassification("::1", "ipv4Compatible", ipaddr.TriFalse)
+	t.testClassification("::", "ipv4Compatible", ipaddr.TriFalse)
+	t.testClassification("2001:db8::1", "ipv4Compatible", ipaddr.TriFalse)
+}
+
+func (t ipAddressTester) testFullyExpanded(host string, expected string) {
+	str := t.createAddress(host)
+	addr, err := str.ToAddress()
+	if err != nil {
+		t.addFailure(newFailure("failed "+err.Error(), str))
+		return
+	}
+	ipv6Addr := addr.ToIPv6Address()
+	if ipv6Addr == nil {
+		t.addFailure(newIPAddrFailure("not an IPv6 address", addr))
+		return
+	}
+	if expanded := ipv6Addr.ToExpandedString(); expanded != expected {
+		t.addFailure(newIPAddrFailure("expanded string expected: "+expected+" got: "+expanded, addr))
+	}
 }
 
 func (t ipAddressTester) testEquivalentPrefix(host string, prefix ipaddr.BitCount) {
@@ -1445,11 +1513,9 @@ func (t ipAddressTester) testBitwiseOr(orig string, prefixAdjustment ipaddr.Pref
 	t.incrementTestCount()
 }
 
-//
 //	void testPrefixBitwiseOr(String orig, Integer prefix, String or, String expectedNetworkResult) {
 //		testPrefixBitwiseOr(orig, prefix, or, expectedNetworkResult, null);
 //	}
-//
 func (t ipAddressTester) testPrefixBitwiseOr(orig string, prefix ipaddr.BitCount, or, expectedNetworkResult, expectedFullResult string) {
 	original := t.createAddress(orig).GetAddress()
 	orAddr := t.createAddress(or).GetAddress()
@@ -1645,6 +1711,47 @@ func (t ipAddressTester) testMatchesInetAton(matches bool, host1Str, host2Str st
 	t.incrementTestCount()
 }
 
+// testClassification parses host and checks that the named classification
+// predicate ("global", "shared", "benchmarking", "documentation", "reserved",
+// "uniqueLocal", "unicastLinkLocal", "ipv4Mapped", or "ipv4Compatible")
+// reports expected, exercising ipaddr.IPAddress's range-aware Tribool
+// classification surface (classify.go, ipv4mapped.go).
+func (t ipAddressTester) testClassification(host string, predicate string, expected ipaddr.Tribool) {
+	str := t.createAddress(host)
+	addr, err := str.ToAddress()
+	if err != nil {
+		t.addFailure(newFailure("failed "+err.Error(), str))
+		return
+	}
+	var got ipaddr.Tribool
+	switch predicate {
+	case "global":
+		got = addr.IsGlobal()
+	case "shared":
+		got = addr.IsShared()
+	case "benchmarking":
+		got = addr.IsBenchmarking()
+	case "documentation":
+		got = addr.IsDocumentation()
+	case "reserved":
+		got = addr.IsReserved()
+	case "uniqueLocal":
+		got = addr.IsUniqueLocal()
+	case "unicastLinkLocal":
+		got = addr.IsUnicastLinkLocal()
+	case "ipv4Mapped":
+		got = addr.IsIPv4Mapped()
+	case "ipv4Compatible":
+		got = addr.IsIPv4Compatible()
+	default:
+		t.addFailure(newIPAddrFailure("unknown classification predicate "+predicate, addr))
+		return
+	}
+	if got != expected {
+		t.addFailure(newIPAddrFailure(predicate+" expected: "+expected.String()+" got: "+got.String(), addr))
+	}
+}
+
 func (t ipAddressTester) ipv4_inet_aton_test(pass bool, x string) {
 	addr := t.createInetAtonAddress(x)
 	t.iptest(pass, addr, false, false, true)
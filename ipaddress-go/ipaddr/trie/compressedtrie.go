@@ -0,0 +1,391 @@
+package trie
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+)
+
+// This file adds Trie, a path-compressed binary radix trie keyed directly on
+// an address family's raw bytes rather than *ipaddr.IPAddress segment
+// access, modeled on the node-compression design of WireGuard's allowedips
+// table. Unlike AssociativeTrie (trie.go), which walks one bit at a time via
+// GetSegment/bitAt, Trie collapses runs of single-child nodes into one node
+// carrying a bit count, so a sparse table (eg a handful of /32s alongside a
+// /8) stays shallow rather than one level deep per bit. It does not
+// replicate WireGuard's RCU/lock-free concurrency or lazy node removal
+// during deletes; Remove here simply clears the node's value, like
+// AssociativeTrie.Remove, which keeps the implementation small at the cost
+// of not reclaiming now-unnecessary glue nodes.
+
+// ipBytes constrains Trie to the two fixed-width byte arrays that back this
+// module's address families.
+type ipBytes interface {
+	[4]byte | [16]byte
+}
+
+// bitLen returns the bit width of family B: 32 for [4]byte, 128 for [16]byte.
+func bitLen[B ipBytes]() int {
+	var zero B
+	if _, ok := any(zero).([4]byte); ok {
+		return 32
+	}
+	return 128
+}
+
+// bitAtIndex returns the bit of bs at position i, counting from the most
+// significant bit (i=0).
+func bitAtIndex[B ipBytes](bs B, i int) int {
+	raw := any(bs)
+	var b byte
+	switch v := raw.(type) {
+	case [4]byte:
+		b = v[i/8]
+	case [16]byte:
+		b = v[i/8]
+	}
+	return int(b>>uint(7-i%8)) & 1
+}
+
+// commonPrefixLen returns the number of leading bits a and b share, using
+// math/bits.LeadingZeros32/64 over each half of the backing array rather
+// than a bit-at-a-time scan.
+func commonPrefixLen[B ipBytes](a, b B) int {
+	switch av := any(a).(type) {
+	case [4]byte:
+		bv := any(b).([4]byte)
+		x := binary.BigEndian.Uint32(av[:]) ^ binary.BigEndian.Uint32(bv[:])
+		if x == 0 {
+			return 32
+		}
+		return bits.LeadingZeros32(x)
+	case [16]byte:
+		bv := any(b).([16]byte)
+		hiX := binary.BigEndian.Uint64(av[:8]) ^ binary.BigEndian.Uint64(bv[:8])
+		if hiX != 0 {
+			return bits.LeadingZeros64(hiX)
+		}
+		loX := binary.BigEndian.Uint64(av[8:]) ^ binary.BigEndian.Uint64(bv[8:])
+		if loX == 0 {
+			return 128
+		}
+		return 64 + bits.LeadingZeros64(loX)
+	}
+	return 0
+}
+
+func minInt(vals ...int) int {
+	m := vals[0]
+	for _, v := range vals[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// trieNode is an internal node of Trie. A node with hasValue false and two
+// children is a pure "glue" node introduced to let two prefixes with a
+// shorter common prefix share a branch point.
+type trieNode[B ipBytes, V any] struct {
+	child    [2]*trieNode[B, V]
+	bits     B
+	cidr     int
+	hasValue bool
+	value    V
+
+	owner                any
+	ownerPrev, ownerNext *trieNode[B, V]
+}
+
+// Trie is a path-compressed binary radix trie over address family B (either
+// [4]byte for IPv4 or [16]byte for IPv6), associating each inserted prefix
+// with a value of type V and, optionally, an owner key used by RemoveOwner
+// to bulk-remove every entry an owner holds without walking the whole
+// trie. The zero Trie is not usable; create one with NewTrie.
+type Trie[B ipBytes, V any] struct {
+	root   *trieNode[B, V]
+	size   int
+	owners map[any]*trieNode[B, V]
+}
+
+// NewTrie returns an empty Trie for address family B.
+func NewTrie[B ipBytes, V any]() *Trie[B, V] {
+	return &Trie[B, V]{owners: make(map[any]*trieNode[B, V])}
+}
+
+// Size returns the number of prefixes currently holding a value in the trie.
+func (t *Trie[B, V]) Size() int {
+	return t.size
+}
+
+// keyOf extracts prefix's raw bytes and prefix length, reporting ok as false
+// if prefix is nil or its byte length does not match family B.
+func keyOf[B ipBytes](prefix *ipaddr.IPAddress) (key B, cidr int, ok bool) {
+	if prefix == nil {
+		return key, 0, false
+	}
+	var raw []byte
+	if v4 := prefix.ToIPv4Address(); v4 != nil {
+		raw = v4.GetBytes()
+	} else if v6 := prefix.ToIPv6Address(); v6 != nil {
+		raw = v6.GetBytes()
+	} else {
+		return key, 0, false
+	}
+	cidr = bitLen[B]()
+	if prefLen := prefix.GetNetworkPrefixLength(); prefLen != nil {
+		cidr = prefLen.Len()
+	}
+	switch any(key).(type) {
+	case [4]byte:
+		if len(raw) != 4 {
+			return key, 0, false
+		}
+		var b [4]byte
+		copy(b[:], raw)
+		return any(b).(B), cidr, true
+	case [16]byte:
+		if len(raw) != 16 {
+			return key, 0, false
+		}
+		var b [16]byte
+		copy(b[:], raw)
+		return any(b).(B), cidr, true
+	}
+	return key, 0, false
+}
+
+// addrOf reconstructs the *ipaddr.IPAddress prefix block named by bits and
+// cidr, dispatching on family B.
+func addrOf[B ipBytes](key B, cidr int) *ipaddr.IPAddress {
+	prefixLength := ipaddr.ToPrefixLen(ipaddr.BitCount(cidr))
+	switch bv := any(key).(type) {
+	case [4]byte:
+		addr, err := ipaddr.NewIPv4AddressFromPrefixedIP(bv[:], prefixLength)
+		if err != nil {
+			return nil
+		}
+		return addr.ToIPAddress()
+	case [16]byte:
+		addr, err := ipaddr.NewIPv6AddressFromPrefixedIP(bv[:], prefixLength)
+		if err != nil {
+			return nil
+		}
+		return addr.ToIPAddress()
+	}
+	return nil
+}
+
+func (t *Trie[B, V]) linkOwner(n *trieNode[B, V]) {
+	if n.owner == nil {
+		return
+	}
+	head := t.owners[n.owner]
+	n.ownerNext = head
+	n.ownerPrev = nil
+	if head != nil {
+		head.ownerPrev = n
+	}
+	t.owners[n.owner] = n
+}
+
+func (t *Trie[B, V]) unlinkOwner(n *trieNode[B, V]) {
+	if n.owner == nil {
+		return
+	}
+	if n.ownerPrev != nil {
+		n.ownerPrev.ownerNext = n.ownerNext
+	} else {
+		t.owners[n.owner] = n.ownerNext
+	}
+	if n.ownerNext != nil {
+		n.ownerNext.ownerPrev = n.ownerPrev
+	}
+	n.ownerPrev, n.ownerNext = nil, nil
+}
+
+// Insert adds prefix to the trie, associated with value and owner. owner may
+// be nil if the caller has no use for RemoveOwner. If prefix is already
+// present, its value and owner are replaced.
+func (t *Trie[B, V]) Insert(prefix *ipaddr.IPAddress, owner any, value V) bool {
+	key, cidr, ok := keyOf[B](prefix)
+	if !ok {
+		return false
+	}
+	newNode := &trieNode[B, V]{bits: key, cidr: cidr, hasValue: true, value: value, owner: owner}
+	parent := &t.root
+	for {
+		cur := *parent
+		if cur == nil {
+			*parent = newNode
+			t.size++
+			t.linkOwner(newNode)
+			return true
+		}
+		common := minInt(commonPrefixLen(cur.bits, newNode.bits), cur.cidr, newNode.cidr)
+		switch {
+		case common == cur.cidr && common == newNode.cidr:
+			// exact match: replace this node's value/owner
+			if !cur.hasValue {
+				t.size++
+			}
+			t.unlinkOwner(cur)
+			cur.hasValue = true
+			cur.value = value
+			cur.owner = owner
+			t.linkOwner(cur)
+			return true
+		case common == cur.cidr:
+			// newNode is strictly more specific than cur: descend
+			bit := bitAtIndex(newNode.bits, cur.cidr)
+			parent = &cur.child[bit]
+			continue
+		case common == newNode.cidr:
+			// newNode is strictly less specific than cur: newNode becomes
+			// cur's new parent
+			bit := bitAtIndex(cur.bits, newNode.cidr)
+			newNode.child[bit] = cur
+			*parent = newNode
+			t.size++
+			t.linkOwner(newNode)
+			return true
+		default:
+			// neither contains the other: split with a valueless glue node
+			glue := &trieNode[B, V]{bits: cur.bits, cidr: common}
+			bitCur := bitAtIndex(cur.bits, common)
+			bitNew := bitAtIndex(newNode.bits, common)
+			glue.child[bitCur] = cur
+			glue.child[bitNew] = newNode
+			*parent = glue
+			t.size++
+			t.linkOwner(newNode)
+			return true
+		}
+	}
+}
+
+// findExact returns the node holding exactly prefix, or nil if no such node
+// exists (regardless of whether it currently holds a value).
+func (t *Trie[B, V]) findExact(prefix *ipaddr.IPAddress) *trieNode[B, V] {
+	key, cidr, ok := keyOf[B](prefix)
+	if !ok {
+		return nil
+	}
+	n := t.root
+	for n != nil {
+		common := minInt(commonPrefixLen(n.bits, key), n.cidr, cidr)
+		if common < n.cidr {
+			return nil
+		}
+		if n.cidr == cidr {
+			return n
+		}
+		bit := bitAtIndex(key, n.cidr)
+		n = n.child[bit]
+	}
+	return nil
+}
+
+// Remove deletes the exact prefix from the trie, if present. Like
+// AssociativeTrie.Remove, the node is not structurally removed, only
+// cleared, so repeated Insert/Remove cycles on churning prefixes do not
+// shrink the tree back down; see the file comment.
+func (t *Trie[B, V]) Remove(prefix *ipaddr.IPAddress) bool {
+	n := t.findExact(prefix)
+	if n == nil || !n.hasValue {
+		return false
+	}
+	t.unlinkOwner(n)
+	n.owner = nil
+	n.hasValue = false
+	var zero V
+	n.value = zero
+	t.size--
+	return true
+}
+
+// Lookup performs a longest-prefix-match of addr against the trie, returning
+// the value of the most specific matching prefix.
+func (t *Trie[B, V]) Lookup(addr *ipaddr.IPAddress) (value V, ok bool) {
+	key, _, kok := keyOf[B](addr)
+	if !kok {
+		return value, false
+	}
+	width := bitLen[B]()
+	n := t.root
+	var best *trieNode[B, V]
+	for n != nil {
+		if commonPrefixLen(n.bits, key) < n.cidr {
+			break
+		}
+		if n.hasValue {
+			best = n
+		}
+		if n.cidr >= width {
+			break
+		}
+		n = n.child[bitAtIndex(key, n.cidr)]
+	}
+	if best == nil {
+		return value, false
+	}
+	return best.value, true
+}
+
+// Walk calls fn once for every prefix currently holding a value, in no
+// particular order, stopping early if fn returns false.
+func (t *Trie[B, V]) Walk(fn func(prefix *ipaddr.IPAddress, value V) bool) {
+	var visit func(n *trieNode[B, V]) bool
+	visit = func(n *trieNode[B, V]) bool {
+		if n == nil {
+			return true
+		}
+		if n.hasValue {
+			if !fn(addrOf[B](n.bits, n.cidr), n.value) {
+				return false
+			}
+		}
+		return visit(n.child[0]) && visit(n.child[1])
+	}
+	visit(t.root)
+}
+
+// RemoveOwner clears every entry currently owned by owner, using the
+// intrusive per-owner list threaded through trieNode rather than walking
+// the whole trie, and returns how many entries were cleared.
+func (t *Trie[B, V]) RemoveOwner(owner any) int {
+	n := t.owners[owner]
+	count := 0
+	for n != nil {
+		next := n.ownerNext
+		if n.hasValue {
+			n.hasValue = false
+			var zero V
+			n.value = zero
+			t.size--
+			count++
+		}
+		n.owner, n.ownerPrev, n.ownerNext = nil, nil, nil
+		n = next
+	}
+	delete(t.owners, owner)
+	return count
+}
+
+// IPv4Trie is a Trie specialized to the IPv4 byte family.
+type IPv4Trie[V any] = Trie[[4]byte, V]
+
+// IPv6Trie is a Trie specialized to the IPv6 byte family.
+type IPv6Trie[V any] = Trie[[16]byte, V]
+
+// NewIPv4Trie returns an empty IPv4Trie.
+func NewIPv4Trie[V any]() *IPv4Trie[V] {
+	return NewTrie[[4]byte, V]()
+}
+
+// NewIPv6Trie returns an empty IPv6Trie.
+func NewIPv6Trie[V any]() *IPv6Trie[V] {
+	return NewTrie[[16]byte, V]()
+}
@@ -0,0 +1,461 @@
+// Package trie provides a binary radix (Patricia) trie keyed by address
+// prefix bits, for bulk containment and longest-prefix-match lookup against
+// large routing or ACL tables.  Unlike pairwise Contains checks, which are
+// O(N) per lookup against a table of N entries, the trie resolves a lookup
+// in time proportional to the bit width of the address.
+package trie
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+)
+
+// node is a node of an AssociativeTrie. prefix and bitCount together name
+// the prefix block this node occupies: prefix's leading bitCount bits are
+// significant, the rest are the block's host bits. A node with hasValue
+// false is a "glue" node, introduced purely to give two stored prefixes
+// with a shorter common prefix a shared branch point; it holds no element
+// of its own.
+type node[V any] struct {
+	prefix    *ipaddr.IPAddress
+	bitCount  ipaddr.BitCount
+	hasValue  bool
+	value     V
+	zero, one *node[V]
+}
+
+// AssociativeTrie is a binary radix tree of address prefixes, each optionally
+// associated with a value of type V.  A zero AssociativeTrie is not usable;
+// create one with NewAssociativeTrie.
+type AssociativeTrie[V any] struct {
+	root *node[V]
+	size int
+}
+
+// NewAssociativeTrie returns an empty trie.
+func NewAssociativeTrie[V any]() *AssociativeTrie[V] {
+	return &AssociativeTrie[V]{}
+}
+
+// Size returns the number of prefixes stored in the trie.
+func (t *AssociativeTrie[V]) Size() int {
+	return t.size
+}
+
+func bitAt(addr *ipaddr.IPAddress, index ipaddr.BitCount) int {
+	segBits := ipaddr.BitCount(8)
+	if addr.IsIPv6() {
+		segBits = 16
+	}
+	seg := addr.GetSegment(int(index / segBits))
+	shift := segBits - 1 - (index % segBits)
+	return int((seg.GetSegmentValue() >> uint(shift)) & 1)
+}
+
+// commonPrefixLen returns how many of a's and b's leading bits agree, never
+// exceeding limit.
+func commonPrefixLen(a, b *ipaddr.IPAddress, limit ipaddr.BitCount) ipaddr.BitCount {
+	var i ipaddr.BitCount
+	for i < limit {
+		if bitAt(a, i) != bitAt(b, i) {
+			break
+		}
+		i++
+	}
+	return i
+}
+
+func minBitCount(a, b ipaddr.BitCount) ipaddr.BitCount {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// prefixBitCount returns the number of significant bits for addr: its
+// network prefix length if it has one, otherwise its full address width
+// (treating a bare address as a single-address, maximally-specific prefix).
+func prefixBitCount(addr *ipaddr.IPAddress) ipaddr.BitCount {
+	if prefixLen := addr.GetNetworkPrefixLength(); prefixLen != nil {
+		return prefixLen.Len()
+	}
+	return ipaddr.BitCount(addr.GetBitCount())
+}
+
+// spanAsPrefixBlocks decomposes a non-CIDR-aligned range (such as one parsed
+// from "1.2.3.0-5") into the minimal sequence of CIDR prefix blocks that
+// exactly covers it, in increasing address order.
+func spanAsPrefixBlocks(rng *ipaddr.IPAddress) []*ipaddr.IPAddress {
+	version := rng.GetIPVersion()
+	width := ipaddr.BitCount(rng.GetBitCount())
+	low := new(big.Int).SetBytes(rng.GetLower().GetBytes())
+	high := new(big.Int).SetBytes(rng.GetUpper().GetBytes())
+
+	var blocks []*ipaddr.IPAddress
+	one := big.NewInt(1)
+	for low.Cmp(high) <= 0 {
+		size := trailingZeroBits(low, int(width))
+		for size > 0 {
+			blockLen := new(big.Int).Lsh(one, uint(size))
+			end := new(big.Int).Sub(new(big.Int).Add(low, blockLen), one)
+			if end.Cmp(high) <= 0 {
+				break
+			}
+			size--
+		}
+		blockAddr := addrFromValue(low, version, width)
+		prefixLen := width - ipaddr.BitCount(size)
+		blocks = append(blocks, blockAddr.ToPrefixBlockLen(prefixLen))
+		low.Add(low, new(big.Int).Lsh(one, uint(size)))
+	}
+	return blocks
+}
+
+// trailingZeroBits returns the number of trailing zero bits in v, treating v
+// as a width-bit value (so a zero v has width trailing zero bits).
+func trailingZeroBits(v *big.Int, width int) int {
+	if v.Sign() == 0 {
+		return width
+	}
+	n := 0
+	for v.Bit(n) == 0 {
+		n++
+	}
+	return n
+}
+
+// addrFromValue reconstructs the address of the given IP version from its
+// integer value, rendered as a width-bit big-endian byte string.
+func addrFromValue(value *big.Int, version ipaddr.IPVersion, width ipaddr.BitCount) *ipaddr.IPAddress {
+	bytes := make([]byte, width/8)
+	value.FillBytes(bytes)
+	if version == ipaddr.IPv6 {
+		addr, _ := ipaddr.NewIPv6AddressFromIP(bytes)
+		return addr.ToIPAddress()
+	}
+	addr, _ := ipaddr.NewIPv4AddressFromIP(bytes)
+	return addr.ToIPAddress()
+}
+
+// Add inserts the given prefix block into the trie, associating it with
+// value.  If the exact prefix is already present, its value is replaced. If
+// prefix is a range that is not itself an aligned CIDR block (eg one parsed
+// from "1.2.3.0-5"), it is first decomposed via spanAsPrefixBlocks into the
+// minimal set of CIDR blocks that cover it, each added in turn.
+func (t *AssociativeTrie[V]) Add(prefix *ipaddr.IPAddress, value V) {
+	if prefix.IsMultiple() && !prefix.IsSinglePrefixBlock() {
+		for _, block := range spanAsPrefixBlocks(prefix) {
+			t.Add(block, value)
+		}
+		return
+	}
+	bitCount := prefixBitCount(prefix)
+	t.addOne(prefix.ToPrefixBlockLen(bitCount), bitCount, value)
+}
+
+// addOne inserts a single already-normalized CIDR block, splitting an
+// existing node on their common-prefix length (Patricia-trie style) so that
+// broader stored prefixes always sit closer to the root than the more
+// specific prefixes nested within them.
+func (t *AssociativeTrie[V]) addOne(block *ipaddr.IPAddress, bitCount ipaddr.BitCount, value V) {
+	newNode := &node[V]{prefix: block, bitCount: bitCount, hasValue: true, value: value}
+	parent := &t.root
+	for {
+		cur := *parent
+		if cur == nil {
+			*parent = newNode
+			t.size++
+			return
+		}
+		common := commonPrefixLen(cur.prefix, block, minBitCount(cur.bitCount, bitCount))
+		switch {
+		case common == cur.bitCount && common == bitCount:
+			// exact match: replace this node's value
+			if !cur.hasValue {
+				t.size++
+			}
+			cur.hasValue = true
+			cur.value = value
+			cur.prefix = block
+			return
+		case common == cur.bitCount:
+			// newNode is strictly more specific than cur: descend
+			if bitAt(block, cur.bitCount) == 0 {
+				parent = &cur.zero
+			} else {
+				parent = &cur.one
+			}
+			continue
+		case common == bitCount:
+			// newNode is strictly less specific than cur: newNode becomes
+			// cur's new parent
+			if bitAt(cur.prefix, bitCount) == 0 {
+				newNode.zero = cur
+			} else {
+				newNode.one = cur
+			}
+			*parent = newNode
+			t.size++
+			return
+		default:
+			// neither contains the other: split with a valueless glue node
+			glue := &node[V]{prefix: block.ToPrefixBlockLen(common), bitCount: common}
+			if bitAt(cur.prefix, common) == 0 {
+				glue.zero = cur
+			} else {
+				glue.one = cur
+			}
+			if bitAt(block, common) == 0 {
+				glue.zero = newNode
+			} else {
+				glue.one = newNode
+			}
+			*parent = glue
+			t.size++
+			return
+		}
+	}
+}
+
+// Remove deletes the exact prefix from the trie, if present.
+func (t *AssociativeTrie[V]) Remove(prefix *ipaddr.IPAddress) bool {
+	n := t.findExact(prefix, prefixBitCount(prefix))
+	if n == nil || !n.hasValue {
+		return false
+	}
+	n.hasValue = false
+	var zero V
+	n.value = zero
+	t.size--
+	return true
+}
+
+// findExact returns the node holding exactly the given prefix (bitCount
+// significant bits), or nil if no such node exists, regardless of whether
+// it currently holds a value.
+func (t *AssociativeTrie[V]) findExact(prefix *ipaddr.IPAddress, bitCount ipaddr.BitCount) *node[V] {
+	n := t.root
+	for n != nil {
+		common := commonPrefixLen(n.prefix, prefix, minBitCount(n.bitCount, bitCount))
+		if common < n.bitCount {
+			return nil
+		}
+		if n.bitCount == bitCount {
+			return n
+		}
+		if bitAt(prefix, n.bitCount) == 0 {
+			n = n.zero
+		} else {
+			n = n.one
+		}
+	}
+	return nil
+}
+
+// Contains returns whether addr matches a prefix stored in the trie exactly
+// or is covered by one of the stored prefix blocks.
+func (t *AssociativeTrie[V]) Contains(addr *ipaddr.IPAddress) bool {
+	return len(t.ElementsContaining(addr)) > 0
+}
+
+// ElementsContaining returns every stored prefix that covers addr, ordered
+// from the trie root (broadest match) to the most specific match.
+func (t *AssociativeTrie[V]) ElementsContaining(addr *ipaddr.IPAddress) []*ipaddr.IPAddress {
+	var result []*ipaddr.IPAddress
+	n := t.root
+	width := ipaddr.BitCount(addr.GetBitCount())
+	for n != nil {
+		if commonPrefixLen(n.prefix, addr, minBitCount(n.bitCount, width)) < n.bitCount {
+			break
+		}
+		if n.hasValue && n.prefix.Contains(addr) {
+			result = append(result, n.prefix)
+		}
+		if n.bitCount >= width {
+			break
+		}
+		if bitAt(addr, n.bitCount) == 0 {
+			n = n.zero
+		} else {
+			n = n.one
+		}
+	}
+	return result
+}
+
+// ElementsContainedBy returns every stored prefix that is contained by
+// prefix.
+func (t *AssociativeTrie[V]) ElementsContainedBy(prefix *ipaddr.IPAddress) []*ipaddr.IPAddress {
+	var result []*ipaddr.IPAddress
+	var walk func(n *node[V])
+	walk = func(n *node[V]) {
+		if n == nil {
+			return
+		}
+		if n.hasValue && prefix.Contains(n.prefix) {
+			result = append(result, n.prefix)
+		}
+		walk(n.zero)
+		walk(n.one)
+	}
+	walk(t.root)
+	return result
+}
+
+// LongestPrefixMatch returns the most specific stored prefix covering addr,
+// and whether one was found.
+func (t *AssociativeTrie[V]) LongestPrefixMatch(addr *ipaddr.IPAddress) (*ipaddr.IPAddress, bool) {
+	matches := t.ElementsContaining(addr)
+	if len(matches) == 0 {
+		return nil, false
+	}
+	return matches[len(matches)-1], true
+}
+
+// ShortestPrefixMatch returns the broadest stored prefix covering addr, and
+// whether one was found.
+func (t *AssociativeTrie[V]) ShortestPrefixMatch(addr *ipaddr.IPAddress) (*ipaddr.IPAddress, bool) {
+	matches := t.ElementsContaining(addr)
+	if len(matches) == 0 {
+		return nil, false
+	}
+	return matches[0], true
+}
+
+// Get returns the value associated with the longest prefix match for addr.
+func (t *AssociativeTrie[V]) Get(addr *ipaddr.IPAddress) (value V, ok bool) {
+	n := t.root
+	width := ipaddr.BitCount(addr.GetBitCount())
+	var best *node[V]
+	for n != nil {
+		if commonPrefixLen(n.prefix, addr, minBitCount(n.bitCount, width)) < n.bitCount {
+			break
+		}
+		if n.hasValue && n.prefix.Contains(addr) {
+			best = n
+		}
+		if n.bitCount >= width {
+			break
+		}
+		if bitAt(addr, n.bitCount) == 0 {
+			n = n.zero
+		} else {
+			n = n.one
+		}
+	}
+	if best == nil {
+		var zero V
+		return zero, false
+	}
+	return best.value, true
+}
+
+// Walk calls fn once for every prefix currently holding a value, visiting
+// each node's zero child before its one child, stopping early if fn returns
+// false.
+func (t *AssociativeTrie[V]) Walk(fn func(prefix *ipaddr.IPAddress, value V) bool) {
+	var visit func(n *node[V]) bool
+	visit = func(n *node[V]) bool {
+		if n == nil {
+			return true
+		}
+		if n.hasValue {
+			if !fn(n.prefix, n.value) {
+				return false
+			}
+		}
+		return visit(n.zero) && visit(n.one)
+	}
+	visit(t.root)
+}
+
+// Clone returns a deep copy of t; mutating the copy does not affect t.
+func (t *AssociativeTrie[V]) Clone() *AssociativeTrie[V] {
+	return &AssociativeTrie[V]{root: cloneNode(t.root), size: t.size}
+}
+
+func cloneNode[V any](n *node[V]) *node[V] {
+	if n == nil {
+		return nil
+	}
+	return &node[V]{
+		prefix:   n.prefix,
+		bitCount: n.bitCount,
+		hasValue: n.hasValue,
+		value:    n.value,
+		zero:     cloneNode(n.zero),
+		one:      cloneNode(n.one),
+	}
+}
+
+// Equal reports whether t and other hold exactly the same set of prefixes,
+// each with an equal value (compared via reflect.DeepEqual), regardless of
+// the two tries' internal shapes.
+func (t *AssociativeTrie[V]) Equal(other *AssociativeTrie[V]) bool {
+	if t.Size() != other.Size() {
+		return false
+	}
+	equal := true
+	t.Walk(func(prefix *ipaddr.IPAddress, value V) bool {
+		n := other.findExact(prefix, prefixBitCount(prefix))
+		if n == nil || !n.hasValue || !reflect.DeepEqual(value, n.value) {
+			equal = false
+			return false
+		}
+		return true
+	})
+	return equal
+}
+
+// String renders t as an indented tree-shaped dump, one line per node,
+// children indented one level deeper than their parent and listed zero
+// child before one child, for use in snapshot tests. Internal glue nodes
+// introduced purely to join two divergent prefixes are rendered with no
+// value.
+func (t *AssociativeTrie[V]) String() string {
+	var b strings.Builder
+	var dump func(n *node[V], depth int)
+	dump = func(n *node[V], depth int) {
+		if n == nil {
+			return
+		}
+		b.WriteString(strings.Repeat("  ", depth))
+		b.WriteString(n.prefix.String())
+		if n.hasValue {
+			fmt.Fprintf(&b, " -> %v", n.value)
+		}
+		b.WriteByte('\n')
+		dump(n.zero, depth+1)
+		dump(n.one, depth+1)
+	}
+	dump(t.root, 0)
+	return b.String()
+}
+
+// IPv4AddressTrie is an AssociativeTrie specialized with no associated value,
+// for plain IPv4 prefix membership tables.
+type IPv4AddressTrie = AssociativeTrie[struct{}]
+
+// IPv6AddressTrie is an AssociativeTrie specialized with no associated value,
+// for plain IPv6 prefix membership tables.
+type IPv6AddressTrie = AssociativeTrie[struct{}]
+
+// NewIPv4AddressTrie returns an empty IPv4AddressTrie.
+func NewIPv4AddressTrie() *IPv4AddressTrie {
+	return NewAssociativeTrie[struct{}]()
+}
+
+// NewIPv6AddressTrie returns an empty IPv6AddressTrie.
+func NewIPv6AddressTrie() *IPv6AddressTrie {
+	return NewAssociativeTrie[struct{}]()
+}
+
+// AddPrefix inserts prefix into a value-less trie.
+func (t *AssociativeTrie[V]) AddPrefix(prefix *ipaddr.IPAddress) {
+	var zero V
+	t.Add(prefix, zero)
+}
@@ -0,0 +1,207 @@
+package trie
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/seancfoley/ipaddress/ipaddress-go/ipaddr"
+)
+
+func mustParse(t *testing.T, s string) *ipaddr.IPAddress {
+	t.Helper()
+	addr, err := ipaddr.NewIPAddressString(s).ToAddress()
+	if err != nil {
+		t.Fatalf("%q: failed to parse: %v", s, err)
+	}
+	return addr
+}
+
+// TestAddOrdersBroadestPrefixAtRoot checks the regression this fix targets:
+// inserting a more specific prefix before a less specific, overlapping one
+// must still leave the broader prefix at the root, since
+// ElementsContaining/LongestPrefixMatch rely on root-to-leaf ordering from
+// broadest to most specific.
+func TestAddOrdersBroadestPrefixAtRoot(t *testing.T) {
+	tr := NewIPv4AddressTrie()
+	tr.AddPrefix(mustParse(t, "10.1.2.0/24"))
+	tr.AddPrefix(mustParse(t, "10.0.0.0/8"))
+
+	addr := mustParse(t, "10.1.2.3")
+	matches := tr.ElementsContaining(addr)
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want 2: %v", len(matches), matches)
+	}
+	if matches[0].String() != "10.0.0.0/8" {
+		t.Errorf("broadest match got %v, want 10.0.0.0/8", matches[0])
+	}
+	if matches[1].String() != "10.1.2.0/24" {
+		t.Errorf("most specific match got %v, want 10.1.2.0/24", matches[1])
+	}
+}
+
+func TestLongestAndShortestPrefixMatch(t *testing.T) {
+	tr := NewIPv4AddressTrie()
+	tr.AddPrefix(mustParse(t, "10.1.2.0/24"))
+	tr.AddPrefix(mustParse(t, "10.0.0.0/8"))
+
+	addr := mustParse(t, "10.1.2.3")
+	longest, ok := tr.LongestPrefixMatch(addr)
+	if !ok || longest.String() != "10.1.2.0/24" {
+		t.Errorf("LongestPrefixMatch got %v, %v, want 10.1.2.0/24, true", longest, ok)
+	}
+	shortest, ok := tr.ShortestPrefixMatch(addr)
+	if !ok || shortest.String() != "10.0.0.0/8" {
+		t.Errorf("ShortestPrefixMatch got %v, %v, want 10.0.0.0/8, true", shortest, ok)
+	}
+
+	if _, ok := tr.LongestPrefixMatch(mustParse(t, "192.168.1.1")); ok {
+		t.Error("LongestPrefixMatch for unrelated address: expected no match")
+	}
+}
+
+func TestContainsAndGet(t *testing.T) {
+	tr := NewAssociativeTrie[string]()
+	tr.Add(mustParse(t, "192.168.0.0/16"), "inner")
+
+	if !tr.Contains(mustParse(t, "192.168.5.5")) {
+		t.Error("Contains: expected true for covered address")
+	}
+	if tr.Contains(mustParse(t, "10.0.0.1")) {
+		t.Error("Contains: expected false for uncovered address")
+	}
+	value, ok := tr.Get(mustParse(t, "192.168.5.5"))
+	if !ok || value != "inner" {
+		t.Errorf("Get got (%q, %v), want (\"inner\", true)", value, ok)
+	}
+}
+
+func TestElementsContainedBy(t *testing.T) {
+	tr := NewIPv4AddressTrie()
+	tr.AddPrefix(mustParse(t, "10.0.0.0/8"))
+	tr.AddPrefix(mustParse(t, "10.1.0.0/16"))
+	tr.AddPrefix(mustParse(t, "172.16.0.0/12"))
+
+	contained := tr.ElementsContainedBy(mustParse(t, "10.0.0.0/8"))
+	if len(contained) != 2 {
+		t.Fatalf("got %d elements, want 2: %v", len(contained), contained)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	tr := NewIPv4AddressTrie()
+	tr.AddPrefix(mustParse(t, "10.0.0.0/8"))
+	if tr.Size() != 1 {
+		t.Fatalf("Size got %d, want 1", tr.Size())
+	}
+	if !tr.Remove(mustParse(t, "10.0.0.0/8")) {
+		t.Error("Remove: expected true for a present prefix")
+	}
+	if tr.Size() != 0 {
+		t.Errorf("Size after remove got %d, want 0", tr.Size())
+	}
+	if tr.Contains(mustParse(t, "10.1.2.3")) {
+		t.Error("Contains after remove: expected false")
+	}
+	if tr.Remove(mustParse(t, "10.0.0.0/8")) {
+		t.Error("Remove: expected false for an absent prefix")
+	}
+}
+
+// TestAddRangeDecomposesIntoPrefixBlocks checks that adding a non-CIDR-
+// aligned range splits it into CIDR prefix blocks before insertion, rather
+// than being rejected or stored verbatim.
+func TestAddRangeDecomposesIntoPrefixBlocks(t *testing.T) {
+	tr := NewIPv4AddressTrie()
+	tr.AddPrefix(mustParse(t, "1.2.3.0-5"))
+
+	for _, s := range []string{"1.2.3.0", "1.2.3.3", "1.2.3.5"} {
+		if !tr.Contains(mustParse(t, s)) {
+			t.Errorf("expected %q to be covered after adding range 1.2.3.0-5", s)
+		}
+	}
+	if tr.Contains(mustParse(t, "1.2.3.6")) {
+		t.Error("1.2.3.6 should not be covered by range 1.2.3.0-5")
+	}
+	if tr.Size() == 0 {
+		t.Error("expected at least one stored prefix block after decomposing the range")
+	}
+}
+
+func TestCloneAndEqual(t *testing.T) {
+	tr := NewIPv4AddressTrie()
+	tr.AddPrefix(mustParse(t, "10.0.0.0/8"))
+	tr.AddPrefix(mustParse(t, "10.1.0.0/16"))
+
+	clone := tr.Clone()
+	if !tr.Equal(clone) {
+		t.Error("Clone: expected clone to equal the original")
+	}
+
+	clone.AddPrefix(mustParse(t, "192.168.0.0/16"))
+	if tr.Equal(clone) {
+		t.Error("Equal: expected mutated clone to no longer equal the original")
+	}
+	if tr.Contains(mustParse(t, "192.168.1.1")) {
+		t.Error("Clone: mutating the clone should not affect the original")
+	}
+}
+
+func TestString(t *testing.T) {
+	tr := NewIPv4AddressTrie()
+	tr.AddPrefix(mustParse(t, "10.0.0.0/8"))
+	tr.AddPrefix(mustParse(t, "10.1.0.0/16"))
+
+	s := tr.String()
+	if s == "" {
+		t.Fatal("String: expected non-empty output")
+	}
+	if tr.Clone().String() != s {
+		t.Error("String: expected a clone to render identically")
+	}
+}
+
+// BenchmarkLinearContainsSweep measures the cost of testing a lookup address
+// against 100k CIDR prefixes via pairwise Contains checks, as a baseline for
+// BenchmarkTrieLongestPrefixMatch.
+func BenchmarkLinearContainsSweep(b *testing.B) {
+	entries, lookup := buildBenchmarkEntries(100_000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, e := range entries {
+			e.Contains(lookup)
+		}
+	}
+}
+
+// BenchmarkTrieLongestPrefixMatch measures the cost of the same lookup
+// resolved via AssociativeTrie.LongestPrefixMatch against a trie built from
+// the same 100k CIDR prefixes.
+func BenchmarkTrieLongestPrefixMatch(b *testing.B) {
+	entries, lookup := buildBenchmarkEntries(100_000)
+	tr := NewIPv4AddressTrie()
+	for _, e := range entries {
+		tr.AddPrefix(e)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr.LongestPrefixMatch(lookup)
+	}
+}
+
+// buildBenchmarkEntries generates n random IPv4 CIDR blocks with prefix
+// lengths between 8 and 32, plus a lookup address covered by the last entry.
+func buildBenchmarkEntries(n int) (entries []*ipaddr.IPAddress, lookup *ipaddr.IPAddress) {
+	r := rand.New(rand.NewSource(1))
+	entries = make([]*ipaddr.IPAddress, 0, n)
+	for i := 0; i < n; i++ {
+		bytes := []byte{byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256))}
+		addr, err := ipaddr.NewIPv4AddressFromIP(bytes)
+		if err != nil {
+			continue
+		}
+		prefixLen := ipaddr.BitCount(8 + r.Intn(25))
+		entries = append(entries, addr.ToIPAddress().ToPrefixBlockLen(prefixLen))
+	}
+	lookup = entries[len(entries)-1].GetLower()
+	return entries, lookup
+}
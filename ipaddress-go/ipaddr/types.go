@@ -88,20 +88,40 @@ type PrefixLen = *PrefixBitCount
 type BitCount = int // using signed integers allows for easier arithmetic
 type bitCount = uint8
 
-const maxBitCountInternal, minBitCountInternal = math.MaxUint8, 0
+// maxBitCountInternal is one less than bitCount's (uint8) max value, since
+// PrefixBitCount reserves the bCount value 0 to mean "invalid/absent", and
+// so encodes an actual length L internally as L+1; see PrefixBitCount.
+const maxBitCountInternal, minBitCountInternal = math.MaxUint8 - 1, 0
 
 func ToBitCountString(i BitCount) string {
 	return strconv.Itoa(i)
 }
 
+// PrefixBitCount holds a prefix length.  Internally, bCount encodes the
+// actual length as length+1, reserving the zero value to mean
+// "invalid/absent", so that the zero value of PrefixBitCount itself (as
+// opposed to a nil *PrefixBitCount) is also a well-defined invalid state:
+// two invalid PrefixBitCount values always compare equal via ==, and a
+// valid zero-length prefix (bCount==1) is never confused with it, the way
+// net/netip.Prefix was fixed to avoid multiple equivalent invalid forms.
+// Most code reaches PrefixBitCount only through the PrefixLen pointer alias,
+// where nil already means "absent"; this encoding additionally makes a
+// directly held, non-pointer PrefixBitCount safe to use with == or as a map
+// key.
 type PrefixBitCount struct { //TODO look into whether PrefixBitCount needs to be public.  Are the methods still accessible?  What does it look like in the godocs?
 	bCount bitCount
 }
 
-// Len() returns the length of the prefix.  If the receiver is nil, representing the absence of a prefix length, returns 0.
+// IsValid returns whether the receiver represents an actual prefix length,
+// as opposed to a nil pointer or a zero-value (invalid) PrefixBitCount.
+func (p *PrefixBitCount) IsValid() bool {
+	return p != nil && p.bCount != 0
+}
+
+// Len() returns the length of the prefix.  If the receiver is nil or invalid, representing the absence of a prefix length, returns 0.
 // It will also return 0 if the receiver is a prefix with length is 0.
 func (p *PrefixBitCount) Len() BitCount {
-	if p == nil {
+	if !p.IsValid() {
 		return 0
 	}
 	return p.bitCount()
@@ -114,46 +134,47 @@ func (p *PrefixBitCount) Len() BitCount {
 //	return p.bitCount(), true
 //}
 
-// before calling this, check for nil
+// before calling this, check IsValid
 func (p *PrefixBitCount) bitCount() BitCount {
-	return BitCount(p.bCount)
+	return BitCount(p.bCount) - 1
 }
 
 // Equal compares two PrefixLen values for equality.  This method is intended for the PrefixLen type.  BitCount values should be compared with == operator.
-//func (p *PrefixBitCount) Equal(other *BitCount) bool {
-//	if p == nil {
-//		return other == nil
+//
+//	func (p *PrefixBitCount) Equal(other *BitCount) bool {
+//		if p == nil {
+//			return other == nil
+//		}
+//		return other != nil && p.bitCount() == *other
 //	}
-//	return other != nil && p.bitCount() == *other
-//}
 func (p *PrefixBitCount) Equal(other PrefixLen) bool {
-	if p == nil {
-		return other == nil
+	if !p.IsValid() {
+		return !other.IsValid()
 	}
-	return other != nil && p.bitCount() == other.bitCount()
+	return other.IsValid() && p.bitCount() == other.bitCount()
 }
 
 // Matches compares a PrefixLen value with a bit count
 func (p *PrefixBitCount) Matches(other BitCount) bool {
-	return p != nil && p.bitCount() == other
+	return p.IsValid() && p.bitCount() == other
 }
 
 // Compare compares PrefixLen values, returning -1, 0, or 1 if the receiver is less than, equal to, or greater than the argument.
 // This method is intended for the PrefixLen type.  BitCount values should be compared with ==, >, <, >= and <= operators.
 func (p *PrefixBitCount) Compare(other PrefixLen) int {
-	if p == nil {
-		if other == nil {
+	if !p.IsValid() {
+		if !other.IsValid() {
 			return 0
 		}
 		return 1
-	} else if other == nil {
+	} else if !other.IsValid() {
 		return -1
 	}
 	return p.bitCount() - other.bitCount()
 }
 
 func (p *PrefixBitCount) String() string {
-	if p == nil {
+	if !p.IsValid() {
 		return nilString()
 	}
 	return strconv.Itoa(p.bitCount())
@@ -213,7 +234,7 @@ func initPrefLens() ([]PrefixBitCount, []PrefixLen) {
 	cachedPrefBitcounts := make([]PrefixBitCount, IPv6BitCount+1)
 	cachedPrefLens := make([]PrefixLen, IPv6BitCount+1)
 	for i := bitCount(0); i <= IPv6BitCount; i++ {
-		cachedPrefBitcounts[i] = PrefixBitCount{i}
+		cachedPrefBitcounts[i] = PrefixBitCount{i + 1}
 		cachedPrefLens[i] = &cachedPrefBitcounts[i]
 	}
 	return cachedPrefBitcounts, cachedPrefLens
@@ -233,7 +254,7 @@ func ToPrefixLen(i BitCount) PrefixLen {
 	if i > maxBitCountInternal {
 		i = maxBitCountInternal
 	}
-	return &PrefixBitCount{bitCount(i)}
+	return &PrefixBitCount{bitCount(i) + 1}
 }
 
 func cacheBitCount(i BitCount) PrefixLen {
@@ -250,7 +271,7 @@ func cachePrefix(i BitCount) *PrefixLen {
 	if i > maxBitCountInternal {
 		i = maxBitCountInternal
 	}
-	res := &PrefixBitCount{bitCount(i)}
+	res := &PrefixBitCount{bitCount(i) + 1}
 	return &res
 }
 
@@ -401,7 +422,7 @@ func checkBitCount(prefixLength, max BitCount) BitCount {
 }
 
 func checkPrefLen(prefixLength PrefixLen, max BitCount) PrefixLen {
-	if prefixLength != nil {
+	if prefixLength.IsValid() {
 		prefLen := prefixLength.bitCount()
 		if prefLen > max {
 			return cacheBitCount(max)
@@ -0,0 +1,180 @@
+package ipaddr
+
+import "math/big"
+
+// This file adds an allocation-free uint128 representation for single IPv6
+// addresses, alongside the existing segmented representation, analogous to
+// the approach netaddr/netip take for the whole address.
+//
+// Note on scope: IPv6Address must remain exactly struct{ipAddressInternal}
+// with no additional fields, since the rest of this package converts between
+// IPAddress, IPv4Address, and IPv6Address via unsafe.Pointer casts that
+// assume identical layout. That rules out caching hi/lo directly on
+// IPv6Address itself. Instead, Uint128Value extracts the fast-path value on
+// demand from the existing segment storage: for a single address (no
+// multi-valued segments) this involves no *big.Int and no slice allocation,
+// which is the allocation win the uint128 representation is after, even
+// though the value itself isn't cached between calls.
+
+// Uint128 is a 128-bit unsigned integer, stored as big-endian (Hi, Lo)
+// uint64 halves, matching IPv6's bit order.
+type Uint128 struct {
+	Hi, Lo uint64
+}
+
+// Uint128Value extracts addr's value as a Uint128 without going through
+// *big.Int, reporting ok as false if addr is multi-valued (a subnet or range
+// rather than a single address), in which case no single 128-bit value
+// exists.
+func (addr *IPv6Address) Uint128Value() (value Uint128, ok bool) {
+	addr = addr.init()
+	if addr.IsMultiple() {
+		return Uint128{}, false
+	}
+	var hi, lo uint64
+	for i := 0; i < IPv6SegmentCount; i++ {
+		v := uint64(addr.GetSegment(i).GetSegmentValue())
+		if i < 4 {
+			hi = hi<<16 | v
+		} else {
+			lo = lo<<16 | v
+		}
+	}
+	return Uint128{Hi: hi, Lo: lo}, true
+}
+
+// ToIPv6Address builds the single IPv6 address represented by u.
+func (u Uint128) ToIPv6Address() *IPv6Address {
+	bytes := make([]byte, IPv6ByteCount)
+	for i := 0; i < 8; i++ {
+		bytes[7-i] = byte(u.Hi >> (8 * i))
+		bytes[15-i] = byte(u.Lo >> (8 * i))
+	}
+	addr, _ := NewIPv6AddressFromIP(bytes)
+	return addr
+}
+
+// Add returns u+v with 128-bit wraparound, carrying out of Lo into Hi.
+func (u Uint128) Add(v Uint128) Uint128 {
+	lo := u.Lo + v.Lo
+	hi := u.Hi + v.Hi
+	if lo < u.Lo {
+		hi++
+	}
+	return Uint128{Hi: hi, Lo: lo}
+}
+
+// Sub returns u-v with 128-bit wraparound.
+func (u Uint128) Sub(v Uint128) Uint128 {
+	lo := u.Lo - v.Lo
+	hi := u.Hi - v.Hi
+	if u.Lo < v.Lo {
+		hi--
+	}
+	return Uint128{Hi: hi, Lo: lo}
+}
+
+// And returns the bitwise AND of u and v.
+func (u Uint128) And(v Uint128) Uint128 {
+	return Uint128{Hi: u.Hi & v.Hi, Lo: u.Lo & v.Lo}
+}
+
+// Or returns the bitwise OR of u and v.
+func (u Uint128) Or(v Uint128) Uint128 {
+	return Uint128{Hi: u.Hi | v.Hi, Lo: u.Lo | v.Lo}
+}
+
+// Cmp compares u and v, returning -1, 0, or 1.
+func (u Uint128) Cmp(v Uint128) int {
+	if u.Hi != v.Hi {
+		if u.Hi < v.Hi {
+			return -1
+		}
+		return 1
+	}
+	if u.Lo != v.Lo {
+		if u.Lo < v.Lo {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// BigInt converts u to a *big.Int, lazily, only when a caller actually needs
+// arbitrary-precision interop (eg GetValue).
+func (u Uint128) BigInt() *big.Int {
+	v := new(big.Int).SetUint64(u.Hi)
+	v.Lsh(v, 64)
+	v.Or(v, new(big.Int).SetUint64(u.Lo))
+	return v
+}
+
+// networkMaskUint128 returns the Uint128 with the top prefixLen bits set and
+// the rest clear, the fast-path equivalent of GetNetworkMask for a single
+// IPv6 address.
+func networkMaskUint128(prefixLen BitCount) Uint128 {
+	if prefixLen <= 0 {
+		return Uint128{}
+	}
+	if prefixLen >= IPv6BitCount {
+		return Uint128{Hi: ^uint64(0), Lo: ^uint64(0)}
+	}
+	if prefixLen <= 64 {
+		return Uint128{Hi: ^uint64(0) << (64 - uint(prefixLen))}
+	}
+	return Uint128{Hi: ^uint64(0), Lo: ^uint64(0) << (128 - uint(prefixLen))}
+}
+
+// ContainsFast reports whether addr's prefix block (of its own network
+// prefix length) contains other, using the allocation-free Uint128 fast path
+// when both addresses are single-valued; it falls back to the general
+// Contains implementation otherwise.
+func (addr *IPv6Address) ContainsFast(other *IPv6Address) bool {
+	addr = addr.init()
+	prefLen := addr.GetNetworkPrefixLength()
+	if prefLen == nil {
+		return addr.Contains(other)
+	}
+	addrVal, ok := addr.Uint128Value()
+	if !ok {
+		return addr.Contains(other)
+	}
+	otherVal, ok := other.Uint128Value()
+	if !ok {
+		return addr.Contains(other)
+	}
+	mask := networkMaskUint128(prefLen.Len())
+	return addrVal.And(mask) == otherVal.And(mask)
+}
+
+// MaskFast returns addr masked with other's value, using the allocation-free
+// Uint128 fast path when both addresses are single-valued; it falls back to
+// the general Mask implementation otherwise.
+func (addr *IPv6Address) MaskFast(other *IPv6Address) (*IPv6Address, error) {
+	addr = addr.init()
+	addrVal, ok := addr.Uint128Value()
+	if !ok {
+		return addr.Mask(other)
+	}
+	otherVal, ok := other.Uint128Value()
+	if !ok {
+		return addr.Mask(other)
+	}
+	return addrVal.And(otherVal).ToIPv6Address(), nil
+}
+
+// IncrementFast returns addr advanced by increment, using the allocation-free
+// Uint128 fast path when addr is single-valued; it falls back to the general
+// Increment implementation otherwise.
+func (addr *IPv6Address) IncrementFast(increment int64) *IPv6Address {
+	addr = addr.init()
+	addrVal, ok := addr.Uint128Value()
+	if !ok {
+		return addr.Increment(increment)
+	}
+	if increment >= 0 {
+		return addrVal.Add(Uint128{Lo: uint64(increment)}).ToIPv6Address()
+	}
+	return addrVal.Sub(Uint128{Lo: uint64(-increment)}).ToIPv6Address()
+}
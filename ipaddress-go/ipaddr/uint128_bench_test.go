@@ -0,0 +1,33 @@
+package ipaddr
+
+import "testing"
+
+var benchV6, benchV6Other = func() (*IPv6Address, *IPv6Address) {
+	a, _ := NewIPAddressString("2001:db8::1").ToAddress()
+	b, _ := NewIPAddressString("2001:db8::/32").ToAddress()
+	return a.ToIPv6Address(), b.ToIPv6Address()
+}()
+
+func BenchmarkContainsFast(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchV6Other.ContainsFast(benchV6)
+	}
+}
+
+func BenchmarkContainsSegmented(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchV6Other.Contains(benchV6)
+	}
+}
+
+func BenchmarkIncrementFast(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchV6.IncrementFast(1)
+	}
+}
+
+func BenchmarkIncrementSegmented(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchV6.Increment(1)
+	}
+}
@@ -0,0 +1,249 @@
+package ipaddr
+
+// This file fills the "uint32"/uint128 gap noted in ipv4addr.go: fast
+// integer-based constructors and accessors for IPv4Address and IPv6Address,
+// for hot paths such as routing tables and longest-prefix match where
+// paying a net.IP byte-slice allocation per lookup is unacceptable.
+
+func uint32SegmentProvider(val uint32) SegmentValueProvider {
+	return func(segmentIndex int) SegInt {
+		shift := uint(8 * (IPv4SegmentCount - 1 - segmentIndex))
+		return SegInt(val >> shift & 0xff)
+	}
+}
+
+// NewIPv4AddressFromUint32 creates an IPv4Address from its 32-bit integer
+// value, most significant byte first, avoiding any byte-slice allocation.
+func NewIPv4AddressFromUint32(val uint32) *IPv4Address {
+	return NewIPv4AddressFromValues(uint32SegmentProvider(val))
+}
+
+// NewIPv4AddressFromPrefixedUint32 creates an IPv4Address from its 32-bit
+// integer value with the given prefix length.
+func NewIPv4AddressFromPrefixedUint32(val uint32, prefixLength PrefixLen) (*IPv4Address, AddressValueException) {
+	return NewIPv4AddressFromPrefixedValues(uint32SegmentProvider(val), prefixLength)
+}
+
+// NewIPv4AddressFromUint32Range creates an IPv4Address subnet spanning every
+// address whose 32-bit integer value is between lower and upper, inclusive.
+func NewIPv4AddressFromUint32Range(lower, upper uint32) *IPv4Address {
+	return NewIPv4AddressFromRange(uint32SegmentProvider(lower), uint32SegmentProvider(upper))
+}
+
+// Uint32 returns this address's 32-bit integer value, most significant byte
+// first. The receiver must represent a single address; a multi-valued subnet
+// returns the integer value of its lowest address.
+func (addr *IPv4Address) Uint32() uint32 {
+	addr = addr.init()
+	return addr.uint32Value(false)
+}
+
+// UpperUint32 returns the 32-bit integer value of this address's highest
+// address.
+func (addr *IPv4Address) UpperUint32() uint32 {
+	addr = addr.init()
+	return addr.uint32Value(true)
+}
+
+func (addr *IPv4Address) uint32Value(upper bool) uint32 {
+	var val uint32
+	for i := 0; i < IPv4SegmentCount; i++ {
+		seg := addr.GetSegment(i)
+		var segVal SegInt
+		if upper {
+			segVal = seg.GetUpperSegmentValue()
+		} else {
+			segVal = seg.GetSegmentValue()
+		}
+		val = val<<8 | uint32(segVal)
+	}
+	return val
+}
+
+// Uint32Contains reports whether val, as a 32-bit integer address value, lies
+// between addr's lowest and highest values, without constructing an
+// intermediate IPv4Address. For a subnet whose values are not sequential, eg
+// 1.2.*.5, this includes values that are not in addr.
+func (addr *IPv4Address) Uint32Contains(val uint32) bool {
+	addr = addr.init()
+	return val >= addr.Uint32() && val <= addr.UpperUint32()
+}
+
+// Uint32Compare compares addr's lowest address against val, returning -1, 0,
+// or 1 as addr's value is less than, equal to, or greater than val.
+func (addr *IPv4Address) Uint32Compare(val uint32) int {
+	addr = addr.init()
+	switch lower := addr.Uint32(); {
+	case lower < val:
+		return -1
+	case lower > val:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Uint32Mask returns the 32-bit integer value of addr's lowest address with
+// mask applied via bitwise AND, without constructing an intermediate
+// IPv4Address.
+func (addr *IPv4Address) Uint32Mask(mask uint32) uint32 {
+	addr = addr.init()
+	return addr.Uint32() & mask
+}
+
+func uint64SegmentProvider(high, low uint64) SegmentValueProvider {
+	return func(segmentIndex int) SegInt {
+		var word uint64
+		var shift uint
+		if segmentIndex < 4 {
+			word = high
+			shift = uint(16 * (3 - segmentIndex))
+		} else {
+			word = low
+			shift = uint(16 * (7 - segmentIndex))
+		}
+		return SegInt(word >> shift & 0xffff)
+	}
+}
+
+// NewIPv6AddressFromUint64s creates an IPv6Address from its 128-bit integer
+// value split into the high and low 64 bits, most significant word first,
+// avoiding any byte-slice allocation.
+func NewIPv6AddressFromUint64s(high, low uint64) *IPv6Address {
+	return NewIPv6AddressFromVals(uint64SegmentProvider(high, low))
+}
+
+// NewIPv6AddressFromPrefixedUint64s creates an IPv6Address from its 128-bit
+// integer value, split into high and low 64-bit halves, with the given
+// prefix length.
+func NewIPv6AddressFromPrefixedUint64s(high, low uint64, prefixLength PrefixLen) (*IPv6Address, AddressValueException) {
+	return NewIPv6AddressFromPrefixedVals(uint64SegmentProvider(high, low), prefixLength)
+}
+
+// NewIPv6AddressFromUint64sRange creates an IPv6Address subnet spanning every
+// address whose 128-bit integer value, split into high/low 64-bit halves, is
+// between (lowerHigh,lowerLow) and (upperHigh,upperLow), inclusive.
+func NewIPv6AddressFromUint64sRange(lowerHigh, lowerLow, upperHigh, upperLow uint64) *IPv6Address {
+	return NewIPv6AddressFromRange(
+		uint64SegmentProvider(lowerHigh, lowerLow),
+		uint64SegmentProvider(upperHigh, upperLow),
+	)
+}
+
+// Uint64Values returns this address's 128-bit integer value as high and low
+// 64-bit halves. The receiver must represent a single address; a
+// multi-valued subnet returns the value of its lowest address.
+func (addr *IPv6Address) Uint64Values() (high, low uint64) {
+	addr = addr.init()
+	return addr.uint64Values(false)
+}
+
+// UpperUint64Values returns the 128-bit integer value of this address's
+// highest address, as high and low 64-bit halves.
+func (addr *IPv6Address) UpperUint64Values() (high, low uint64) {
+	addr = addr.init()
+	return addr.uint64Values(true)
+}
+
+func (addr *IPv6Address) uint64Values(upper bool) (high, low uint64) {
+	for i := 0; i < 4; i++ {
+		seg := addr.GetSegment(i)
+		var segVal SegInt
+		if upper {
+			segVal = seg.GetUpperSegmentValue()
+		} else {
+			segVal = seg.GetSegmentValue()
+		}
+		high = high<<16 | uint64(segVal)
+	}
+	for i := 4; i < 8; i++ {
+		seg := addr.GetSegment(i)
+		var segVal SegInt
+		if upper {
+			segVal = seg.GetUpperSegmentValue()
+		} else {
+			segVal = seg.GetSegmentValue()
+		}
+		low = low<<16 | uint64(segVal)
+	}
+	return
+}
+
+// Uint64sContains reports whether the 128-bit value (high,low) lies between
+// addr's lowest and highest values, without constructing an intermediate
+// IPv6Address. For a subnet whose values are not sequential this includes
+// values that are not in addr.
+func (addr *IPv6Address) Uint64sContains(high, low uint64) bool {
+	addr = addr.init()
+	lowerHigh, lowerLow := addr.Uint64Values()
+	upperHigh, upperLow := addr.UpperUint64Values()
+	return !uint128Less(high, low, lowerHigh, lowerLow) && !uint128Less(upperHigh, upperLow, high, low)
+}
+
+// Uint64sCompare compares addr's lowest address against (high,low), returning
+// -1, 0, or 1 as addr's value is less than, equal to, or greater than it.
+func (addr *IPv6Address) Uint64sCompare(high, low uint64) int {
+	addr = addr.init()
+	lowerHigh, lowerLow := addr.Uint64Values()
+	switch {
+	case uint128Less(lowerHigh, lowerLow, high, low):
+		return -1
+	case uint128Less(high, low, lowerHigh, lowerLow):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Uint64sMask returns the 128-bit integer value of addr's lowest address
+// with mask (maskHigh,maskLow) applied via bitwise AND, as high and low
+// 64-bit halves, without constructing an intermediate IPv6Address.
+func (addr *IPv6Address) Uint64sMask(maskHigh, maskLow uint64) (high, low uint64) {
+	addr = addr.init()
+	lowerHigh, lowerLow := addr.Uint64Values()
+	return lowerHigh & maskHigh, lowerLow & maskLow
+}
+
+// uint128Less reports whether (aHigh,aLow), read as a 128-bit unsigned
+// integer, is less than (bHigh,bLow).
+func uint128Less(aHigh, aLow, bHigh, bLow uint64) bool {
+	if aHigh != bHigh {
+		return aHigh < bHigh
+	}
+	return aLow < bLow
+}
+
+// NewIPv4AddressSectionFromUint32 creates an IPv4AddressSection from its
+// 32-bit integer value, most significant byte first.
+func NewIPv4AddressSectionFromUint32(val uint32) *IPv4AddressSection {
+	return NewIPv4AddressFromUint32(val).GetSection()
+}
+
+// Uint32 returns this section's 32-bit integer value, most significant byte
+// first, per IPv4Address.Uint32.
+func (section *IPv4AddressSection) Uint32() uint32 {
+	var val uint32
+	for i := 0; i < IPv4SegmentCount; i++ {
+		val = val<<8 | uint32(section.GetSegment(i).GetSegmentValue())
+	}
+	return val
+}
+
+// NewIPv6AddressSectionFromUint64s creates an IPv6AddressSection from its
+// 128-bit integer value split into high and low 64-bit halves, most
+// significant word first.
+func NewIPv6AddressSectionFromUint64s(high, low uint64) *IPv6AddressSection {
+	return NewIPv6AddressFromUint64s(high, low).GetSection()
+}
+
+// Uint64Values returns this section's 128-bit integer value as high and low
+// 64-bit halves, per IPv6Address.Uint64Values.
+func (section *IPv6AddressSection) Uint64Values() (high, low uint64) {
+	for i := 0; i < 4; i++ {
+		high = high<<16 | uint64(section.GetSegment(i).GetSegmentValue())
+	}
+	for i := 4; i < 8; i++ {
+		low = low<<16 | uint64(section.GetSegment(i).GetSegmentValue())
+	}
+	return
+}
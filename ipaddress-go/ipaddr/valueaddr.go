@@ -0,0 +1,470 @@
+package ipaddr
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+)
+
+// This file adds Addr and Prefix, compact comparable value types that mirror
+// what net/netip did against net.IP: a fixed 128-bit payload plus a small
+// zone/family tag, with no pointers and no allocation, for callers that only
+// need to store or compare a single address rather than pull in the full
+// AddressDivisionGrouping machinery for ranges, wildcards, or prefix blocks.
+
+// ipAddrFamily distinguishes the family of an Addr, including the zero value
+// representing an invalid, unspecified Addr (the counterpart of a nil
+// *IPAddress).
+type ipAddrFamily uint8
+
+const (
+	ipAddrFamilyNone ipAddrFamily = iota
+	ipAddrFamilyV4
+	ipAddrFamilyV6
+)
+
+// Addr is a comparable value type holding a single IPv4 or IPv6 address (and,
+// for IPv6, an optional zone), stored as two uint64s rather than a
+// *AddressSection. It has no pointers and no unsafe.Pointer-cached fields, so
+// it is safe to compare with ==, use as a map key, and copy freely. The zero
+// Addr is not valid; use IsValid to distinguish it from a parsed address.
+type Addr struct {
+	hi, lo uint64
+	zone   Zone
+	family ipAddrFamily
+}
+
+// IsValid reports whether a holds an actual IPv4 or IPv6 address, as opposed
+// to the zero Addr.
+func (a Addr) IsValid() bool {
+	return a.family != ipAddrFamilyNone
+}
+
+// Is4 reports whether a is an IPv4 address.
+func (a Addr) Is4() bool {
+	return a.family == ipAddrFamilyV4
+}
+
+// Is6 reports whether a is an IPv6 address, including an IPv4-mapped one.
+func (a Addr) Is6() bool {
+	return a.family == ipAddrFamilyV6
+}
+
+// Is4In6 reports whether a is the IPv4-mapped IPv6 address ::ffff:a.b.c.d.
+func (a Addr) Is4In6() bool {
+	return a.family == ipAddrFamilyV6 && a.hi == 0 && a.lo>>32 == 0xffff
+}
+
+// Unmap returns a, unless a is Is4In6, in which case it returns the embedded
+// IPv4 address with any zone discarded.
+func (a Addr) Unmap() Addr {
+	if !a.Is4In6() {
+		return a
+	}
+	return Addr{lo: a.lo & 0xffffffff, family: ipAddrFamilyV4}
+}
+
+// Zone returns a's IPv6 zone identifier, or the empty Zone if a has none or
+// is not an IPv6 address.
+func (a Addr) Zone() Zone {
+	return a.zone
+}
+
+// WithZone returns a with its zone identifier set to zone. It is a no-op for
+// an IPv4 address.
+func (a Addr) WithZone(zone Zone) Addr {
+	if a.family != ipAddrFamilyV6 {
+		return a
+	}
+	a.zone = zone
+	return a
+}
+
+// AddrFrom4 returns the IPv4 address given by the bytes in addr, in network
+// byte order.
+func AddrFrom4(addr [4]byte) Addr {
+	return Addr{
+		lo:     uint64(binary.BigEndian.Uint32(addr[:])),
+		family: ipAddrFamilyV4,
+	}
+}
+
+// AddrFrom16 returns the IPv6 address given by the bytes in addr, in network
+// byte order.
+func AddrFrom16(addr [16]byte) Addr {
+	return Addr{
+		hi:     binary.BigEndian.Uint64(addr[:8]),
+		lo:     binary.BigEndian.Uint64(addr[8:]),
+		family: ipAddrFamilyV6,
+	}
+}
+
+// AddrFromSlice returns the Addr given by the 4 or 16 bytes in slice, in
+// network byte order. It returns false if slice's length is neither 4 nor
+// 16.
+func AddrFromSlice(slice []byte) (a Addr, ok bool) {
+	switch len(slice) {
+	case IPv4ByteCount:
+		var bytes [4]byte
+		copy(bytes[:], slice)
+		return AddrFrom4(bytes), true
+	case IPv6ByteCount:
+		var bytes [16]byte
+		copy(bytes[:], slice)
+		return AddrFrom16(bytes), true
+	}
+	return Addr{}, false
+}
+
+// as4 returns a's address as 4 bytes, valid only when a.Is4().
+func (a Addr) as4() (bytes [4]byte) {
+	binary.BigEndian.PutUint32(bytes[:], uint32(a.lo))
+	return
+}
+
+// as16 returns a's address as 16 bytes, valid only when a.Is6().
+func (a Addr) as16() (bytes [16]byte) {
+	binary.BigEndian.PutUint64(bytes[:8], a.hi)
+	binary.BigEndian.PutUint64(bytes[8:], a.lo)
+	return
+}
+
+// AsSlice returns a's address as a byte slice: 4 bytes for IPv4, 16 for IPv6,
+// or nil if a is not valid.
+func (a Addr) AsSlice() []byte {
+	if a.Is4() {
+		bytes := a.as4()
+		return bytes[:]
+	} else if a.Is6() {
+		bytes := a.as16()
+		return bytes[:]
+	}
+	return nil
+}
+
+// IsLoopback reports whether a is a loopback address, 127.0.0.0/8 for IPv4 or
+// ::1 for IPv6.
+func (a Addr) IsLoopback() bool {
+	a = a.Unmap()
+	if a.Is4() {
+		return a.as4()[0] == 127
+	}
+	return a.Is6() && a.hi == 0 && a.lo == 1
+}
+
+// IsMulticast reports whether a is a multicast address, 224.0.0.0/4 for IPv4
+// or ff00::/8 for IPv6.
+func (a Addr) IsMulticast() bool {
+	a = a.Unmap()
+	if a.Is4() {
+		return a.as4()[0]&0xf0 == 0xe0
+	}
+	return a.Is6() && a.hi>>56 == 0xff
+}
+
+// IsLinkLocalUnicast reports whether a is a link-local unicast address,
+// 169.254.0.0/16 for IPv4 or fe80::/10 for IPv6.
+func (a Addr) IsLinkLocalUnicast() bool {
+	a = a.Unmap()
+	if a.Is4() {
+		bytes := a.as4()
+		return bytes[0] == 169 && bytes[1] == 254
+	}
+	return a.Is6() && a.hi>>54 == 0x3fa
+}
+
+// IsPrivate reports whether a is a private-use address: one of the RFC 1918
+// blocks (10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16) for IPv4, or a unique
+// local address (fc00::/7) for IPv6.
+func (a Addr) IsPrivate() bool {
+	a = a.Unmap()
+	if a.Is4() {
+		bytes := a.as4()
+		return bytes[0] == 10 ||
+			(bytes[0] == 172 && bytes[1]&0xf0 == 16) ||
+			(bytes[0] == 192 && bytes[1] == 168)
+	}
+	return a.Is6() && a.hi>>57 == 0x7e
+}
+
+// Compare returns a total order over Addr values: invalid orders before IPv4,
+// which orders before IPv6; within a family, addresses are ordered by value
+// and then, for IPv6, by zone.
+func (a Addr) Compare(other Addr) int {
+	if c := int(a.family) - int(other.family); c != 0 {
+		return c
+	}
+	if a.hi != other.hi {
+		if a.hi < other.hi {
+			return -1
+		}
+		return 1
+	}
+	if a.lo != other.lo {
+		if a.lo < other.lo {
+			return -1
+		}
+		return 1
+	}
+	if a.family == ipAddrFamilyV6 && a.zone != other.zone {
+		if a.zone < other.zone {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// Less reports whether a sorts before other, per Compare.
+func (a Addr) Less(other Addr) bool {
+	return a.Compare(other) < 0
+}
+
+// ToAddr converts addr to the compact value-type Addr, reporting ok as false
+// if addr is nil or holds more than one address, since Addr cannot represent
+// a range.
+func (addr *IPAddress) ToAddr() (a Addr, ok bool) {
+	if addr == nil || addr.IsMultiple() {
+		return Addr{}, false
+	}
+	if v4 := addr.ToIPv4Address(); v4 != nil {
+		return Addr{lo: uint64(v4.GetSection().Uint32()), family: ipAddrFamilyV4}, true
+	} else if v6 := addr.ToIPv6Address(); v6 != nil {
+		hi, lo := v6.GetSection().Uint64Values()
+		return Addr{hi: hi, lo: lo, zone: v6.GetZone(), family: ipAddrFamilyV6}, true
+	}
+	return Addr{}, false
+}
+
+// String returns a's canonical string form, the same as the equivalent
+// *IPAddress's String, or "invalid Addr" if a is not valid.
+func (a Addr) String() string {
+	if !a.IsValid() {
+		return "invalid Addr"
+	}
+	return a.ToIPAddress().String()
+}
+
+// ToIPAddress converts a back to the heavy grouping-based *IPAddress,
+// returning nil if a is not valid.
+func (a Addr) ToIPAddress() *IPAddress {
+	if a.Is4() {
+		return NewIPv4AddressFromUint32(uint32(a.lo)).ToIPAddress()
+	} else if a.Is6() {
+		v6 := NewIPv6AddressFromUint64s(a.hi, a.lo)
+		if a.zone != noZone {
+			v6 = NewIPv6AddressZoned(v6.GetSection(), a.zone)
+		}
+		return v6.ToIPAddress()
+	}
+	return nil
+}
+
+// ToPrefix converts addr to the compact value-type Prefix, pairing addr's
+// lowest address with its prefix length. It reports ok as false if addr is
+// nil or has no prefix length.
+func (addr *IPAddress) ToPrefix() (p Prefix, ok bool) {
+	if addr == nil {
+		return Prefix{}, false
+	}
+	prefLen := addr.GetNetworkPrefixLength()
+	if prefLen == nil {
+		return Prefix{}, false
+	}
+	a, ok := addr.GetLower().ToAddr()
+	if !ok {
+		return Prefix{}, false
+	}
+	return PrefixFrom(a, prefLen.Len()), true
+}
+
+// Prefix is the value-type counterpart of IPAddress's prefix-block notion: an
+// Addr together with a prefix bit length, with no allocation. The zero
+// Prefix is not valid; use PrefixFrom to build one.
+type Prefix struct {
+	ip   Addr
+	bits int16
+}
+
+// PrefixFrom returns the Prefix of ip with the given bit length. It is
+// invalid if ip is not valid or bits is out of range for ip's family (0 to 32
+// for IPv4, 0 to 128 for IPv6).
+func PrefixFrom(ip Addr, bits int) Prefix {
+	if !ip.IsValid() || bits < 0 || bits > ip.bitCount() {
+		return Prefix{ip: ip, bits: -1}
+	}
+	return Prefix{ip: ip, bits: int16(bits)}
+}
+
+// bitCount returns the bit width of a's family, or 0 if a is not valid.
+func (a Addr) bitCount() int {
+	if a.Is4() {
+		return IPv4BitCount
+	} else if a.Is6() {
+		return IPv6BitCount
+	}
+	return 0
+}
+
+// IsValid reports whether p was built with a valid Addr and in-range bit
+// length.
+func (p Prefix) IsValid() bool {
+	return p.bits >= 0 && p.ip.IsValid()
+}
+
+// Addr returns p's address, including any host bits beyond its prefix
+// length.
+func (p Prefix) Addr() Addr {
+	return p.ip
+}
+
+// Bits returns p's prefix length, or -1 if p is not valid.
+func (p Prefix) Bits() int {
+	return int(p.bits)
+}
+
+// masked returns addr with every bit beyond the given prefix length cleared.
+func masked(addr Addr, bits int) Addr {
+	if addr.Is4() {
+		v := uint32(addr.lo)
+		if bits <= 0 {
+			v = 0
+		} else if bits < IPv4BitCount {
+			v &= ^uint32(0) << uint(IPv4BitCount-bits)
+		}
+		return Addr{lo: uint64(v), family: ipAddrFamilyV4}
+	} else if addr.Is6() {
+		hi, lo := addr.hi, addr.lo
+		if bits <= 0 {
+			hi, lo = 0, 0
+		} else if bits < 64 {
+			hi &= ^uint64(0) << uint(64-bits)
+			lo = 0
+		} else if bits < IPv6BitCount {
+			lo &= ^uint64(0) << uint(IPv6BitCount-bits)
+		}
+		return Addr{hi: hi, lo: lo, zone: addr.zone, family: ipAddrFamilyV6}
+	}
+	return Addr{}
+}
+
+// Masked returns p with its address's host bits (every bit beyond p.Bits())
+// cleared, mirroring netip.Prefix.Masked. It returns the zero Prefix if p is
+// not valid.
+func (p Prefix) Masked() Prefix {
+	if !p.IsValid() {
+		return Prefix{}
+	}
+	return Prefix{ip: masked(p.ip, p.Bits()), bits: p.bits}
+}
+
+// IsSingleIP reports whether p's prefix length covers every bit of its
+// address's family, ie p's prefix block contains exactly one address.
+func (p Prefix) IsSingleIP() bool {
+	return p.IsValid() && p.Bits() == p.ip.bitCount()
+}
+
+// Overlaps reports whether p and other share any address: each is
+// contained in the other's prefix block at the shorter of the two prefix
+// lengths, the value-type counterpart of IPAddressSeqRange overlap checks
+// done elsewhere via GetLower/GetUpper comparisons.
+func (p Prefix) Overlaps(other Prefix) bool {
+	if !p.IsValid() || !other.IsValid() || p.ip.family != other.ip.family {
+		return false
+	}
+	minBits := p.Bits()
+	if other.Bits() < minBits {
+		minBits = other.Bits()
+	}
+	return masked(p.ip, minBits) == masked(other.ip, minBits)
+}
+
+// String returns p's canonical string form, "addr/bits", or "invalid
+// Prefix" if p is not valid.
+func (p Prefix) String() string {
+	if !p.IsValid() {
+		return "invalid Prefix"
+	}
+	return p.ip.String() + "/" + strconv.Itoa(p.Bits())
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, using the
+// same "addr/bits" form as String.
+func (p Prefix) MarshalText() ([]byte, error) {
+	if !p.IsValid() {
+		return []byte{}, nil
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, reversing
+// MarshalText.
+func (p *Prefix) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*p = Prefix{}
+		return nil
+	}
+	s := string(text)
+	slash := strings.LastIndexByte(s, '/')
+	if slash < 0 {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.prefixLength.invalid"}}
+	}
+	bits, err := strconv.Atoi(s[slash+1:])
+	if err != nil {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.prefixLength.invalid"}}
+	}
+	addr, aerr := NewIPAddressString(s[:slash]).ToAddress()
+	if aerr != nil {
+		return aerr
+	}
+	a, ok := addr.ToAddr()
+	if !ok {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.prefixLength.invalid"}}
+	}
+	result := PrefixFrom(a, bits)
+	if !result.IsValid() {
+		return &addressStringError{addressError: addressError{key: "ipaddress.error.prefixLength.invalid"}}
+	}
+	*p = result
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The
+// binary form is p's address bytes (4 or 16) followed by a 1-byte prefix
+// length.
+func (p Prefix) MarshalBinary() ([]byte, error) {
+	if !p.IsValid() {
+		return []byte{}, nil
+	}
+	out := append([]byte{}, p.ip.AsSlice()...)
+	return append(out, byte(p.Bits())), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// reversing MarshalBinary.
+func (p *Prefix) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		*p = Prefix{}
+		return nil
+	}
+	addrBytes := data[:len(data)-1]
+	a, ok := AddrFromSlice(addrBytes)
+	if !ok {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	result := PrefixFrom(a, int(data[len(data)-1]))
+	if !result.IsValid() {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.byte.count"}}
+	}
+	*p = result
+	return nil
+}
+
+// Contains reports whether addr falls within p: same family, and equal to
+// p.Addr() in every bit up to p.Bits().
+func (p Prefix) Contains(addr Addr) bool {
+	if !p.IsValid() || !addr.IsValid() || p.ip.family != addr.family {
+		return false
+	}
+	return masked(p.ip, p.Bits()) == masked(addr, p.Bits())
+}
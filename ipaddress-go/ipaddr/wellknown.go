@@ -0,0 +1,131 @@
+package ipaddr
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// This file fleshes out the well-known-address API promised by
+// IPAddressNetwork's GetLoopback, caching each constructed address lazily,
+// the same double-checked-lock-then-atomic.StorePointer pattern getMask
+// uses for mask caching in network.go, so repeated calls don't reparse the
+// same literal.
+
+// lazyAddr returns the address already cached at *target, or computes,
+// caches, and returns one via compute, double-checking under mu so compute
+// runs at most once.
+func lazyAddr(target **IPAddress, mu *sync.Mutex, compute func() *IPAddress) *IPAddress {
+	loc := (*unsafe.Pointer)(unsafe.Pointer(target))
+	if v := (*IPAddress)(atomic.LoadPointer(loc)); v != nil {
+		return v
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if v := (*IPAddress)(atomic.LoadPointer(loc)); v != nil {
+		return v
+	}
+	v := compute()
+	atomic.StorePointer(loc, unsafe.Pointer(v))
+	return v
+}
+
+// lazyAddrList is lazyAddr for a cached slice of addresses.
+func lazyAddrList(target **[]*IPAddress, mu *sync.Mutex, compute func() []*IPAddress) []*IPAddress {
+	loc := (*unsafe.Pointer)(unsafe.Pointer(target))
+	if v := (*[]*IPAddress)(atomic.LoadPointer(loc)); v != nil {
+		return *v
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if v := (*[]*IPAddress)(atomic.LoadPointer(loc)); v != nil {
+		return *v
+	}
+	list := compute()
+	atomic.StorePointer(loc, unsafe.Pointer(&list))
+	return list
+}
+
+// GetUnspecified returns the unspecified address for this version, 0.0.0.0
+// for IPv4 or :: for IPv6.
+func (network *IPv4AddressNetwork) GetUnspecified() *IPAddress {
+	return lazyAddr(&network.unspecified, &network.wkMu, func() *IPAddress {
+		return mustPrefixBlock("0.0.0.0")
+	})
+}
+
+// GetLinkLocalPrefix returns the link-local prefix 169.254.0.0/16.
+func (network *IPv4AddressNetwork) GetLinkLocalPrefix() *IPAddress {
+	return lazyAddr(&network.linkLocalPrefix, &network.wkMu, func() *IPAddress {
+		return mustPrefixBlock("169.254.0.0/16")
+	})
+}
+
+// GetMulticastPrefix returns the multicast prefix 224.0.0.0/4.
+func (network *IPv4AddressNetwork) GetMulticastPrefix() *IPAddress {
+	return lazyAddr(&network.multicastPrefix, &network.wkMu, func() *IPAddress {
+		return mustPrefixBlock("224.0.0.0/4")
+	})
+}
+
+// GetPrivatePrefixes returns the RFC 1918 private address prefixes:
+// 10.0.0.0/8, 172.16.0.0/12, and 192.168.0.0/16.
+func (network *IPv4AddressNetwork) GetPrivatePrefixes() []*IPAddress {
+	return lazyAddrList(&network.privatePrefixes, &network.wkMu, func() []*IPAddress {
+		return []*IPAddress{
+			mustPrefixBlock("10.0.0.0/8"),
+			mustPrefixBlock("172.16.0.0/12"),
+			mustPrefixBlock("192.168.0.0/16"),
+		}
+	})
+}
+
+// GetDocumentationPrefixes returns the RFC 5737 documentation prefixes:
+// 192.0.2.0/24 (TEST-NET-1), 198.51.100.0/24 (TEST-NET-2), and
+// 203.0.113.0/24 (TEST-NET-3).
+func (network *IPv4AddressNetwork) GetDocumentationPrefixes() []*IPAddress {
+	return lazyAddrList(&network.docPrefixes, &network.wkMu, func() []*IPAddress {
+		return []*IPAddress{
+			mustPrefixBlock("192.0.2.0/24"),
+			mustPrefixBlock("198.51.100.0/24"),
+			mustPrefixBlock("203.0.113.0/24"),
+		}
+	})
+}
+
+// GetUnspecified returns the unspecified address ::.
+func (network *IPv6AddressNetwork) GetUnspecified() *IPAddress {
+	return lazyAddr(&network.unspecified, &network.wkMu, func() *IPAddress {
+		return mustPrefixBlock("::")
+	})
+}
+
+// GetLinkLocalPrefix returns the link-local prefix fe80::/10.
+func (network *IPv6AddressNetwork) GetLinkLocalPrefix() *IPAddress {
+	return lazyAddr(&network.linkLocalPrefix, &network.wkMu, func() *IPAddress {
+		return mustPrefixBlock("fe80::/10")
+	})
+}
+
+// GetMulticastPrefix returns the multicast prefix ff00::/8.
+func (network *IPv6AddressNetwork) GetMulticastPrefix() *IPAddress {
+	return lazyAddr(&network.multicastPrefix, &network.wkMu, func() *IPAddress {
+		return mustPrefixBlock("ff00::/8")
+	})
+}
+
+// GetPrivatePrefixes returns the RFC 4193 unique local address prefix
+// fc00::/7.
+func (network *IPv6AddressNetwork) GetPrivatePrefixes() []*IPAddress {
+	return lazyAddrList(&network.privatePrefixes, &network.wkMu, func() []*IPAddress {
+		return []*IPAddress{mustPrefixBlock("fc00::/7")}
+	})
+}
+
+// GetDocumentationPrefixes returns the RFC 3849 documentation prefix
+// 2001:db8::/32.
+func (network *IPv6AddressNetwork) GetDocumentationPrefixes() []*IPAddress {
+	return lazyAddrList(&network.docPrefixes, &network.wkMu, func() []*IPAddress {
+		return []*IPAddress{mustPrefixBlock("2001:db8::/32")}
+	})
+}
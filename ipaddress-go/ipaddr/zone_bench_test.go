@@ -0,0 +1,25 @@
+package ipaddr
+
+import "testing"
+
+// BenchmarkZonedAddressesDistinctZone parses addresses with a fresh,
+// never-before-seen zone each time, so canonicalZone's intern table gains a
+// new entry on every iteration.
+func BenchmarkZonedAddressesDistinctZone(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		zone := Zone([]byte{byte(i), byte(i >> 8), byte(i >> 16)})
+		NewIPv6AddressZoned(zeroIPv6.GetSection(), zone)
+	}
+}
+
+// BenchmarkZonedAddressesSharedZone parses addresses with the same handful
+// of zones repeating, as with `ip -6 neigh` output from a host with a
+// small, fixed set of interfaces: canonicalZone lets every resulting
+// address share one of a few backing strings rather than allocating a new
+// one per address.
+func BenchmarkZonedAddressesSharedZone(b *testing.B) {
+	zones := []Zone{"eth0", "eth1", "wlan0"}
+	for i := 0; i < b.N; i++ {
+		NewIPv6AddressZoned(zeroIPv6.GetSection(), zones[i%len(zones)])
+	}
+}
@@ -0,0 +1,41 @@
+package ipaddr
+
+import "strings"
+
+// splitZone separates a raw address string into the address portion and its
+// zone identifier, if any, per RFC 4007.  Both the plain form ("fe80::1%eth0")
+// and the URL/bracketed form ("fe80::1%25eth0", where "%25" is the
+// percent-encoding of "%") are recognized; in the URL form only the "%25"
+// separator is removed, and any other percent-escapes in the zone are
+// returned as written.  If no zone separator is present, zone is the empty
+// string.
+func splitZone(s string) (address string, zone Zone) {
+	if idx := strings.IndexByte(s, IPv6ZoneSeparator); idx >= 0 {
+		rest := s[idx+1:]
+		if strings.HasPrefix(rest, "25") {
+			rest = rest[2:]
+		}
+		return s[:idx], Zone(rest)
+	}
+	return s, noZone
+}
+
+// ParseZonedIPv6Address parses an IPv6 address string that may carry a
+// trailing zone identifier in either plain ("fe80::1%eth0") or URL-encoded
+// ("fe80::1%25eth0") form, returning an IPv6Address with the zone attached.
+func ParseZonedIPv6Address(s string) (*IPv6Address, AddressStringException) {
+	address, zone := splitZone(s)
+	str := NewIPAddressString(address)
+	addr, err := str.ToAddress()
+	if err != nil {
+		return nil, err
+	}
+	ipv6Addr := addr.ToIPv6Address()
+	if ipv6Addr == nil {
+		return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	}
+	if zone != noZone {
+		ipv6Addr = NewIPv6AddressZoned(ipv6Addr.GetSection(), zone)
+	}
+	return ipv6Addr, nil
+}
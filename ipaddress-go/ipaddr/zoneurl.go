@@ -0,0 +1,77 @@
+package ipaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToURLZoneString returns the host portion of a URL containing this address
+// per RFC 6874: the address is bracketed as usual, but a zone identifier, if
+// present, is percent-encoded as "%25<zone>" since a literal "%" is not legal
+// in a URL host.  For example, "fe80::1%eth0" becomes "[fe80::1%25eth0]".
+func (addr *IPv6Address) ToURLZoneString() string {
+	addr = addr.init()
+	base := addr.WithoutZone().ToCanonicalString()
+	if !addr.HasZone() {
+		return "[" + base + "]"
+	}
+	return fmt.Sprintf("[%s%%25%s]", base, percentEncodeZone(string(addr.zone)))
+}
+
+// percentEncodeZone percent-encodes the characters RFC 6874 requires encoding
+// within a zone ID placed in a URL: anything outside unreserved characters.
+func percentEncodeZone(zone string) string {
+	var b strings.Builder
+	for i := 0; i < len(zone); i++ {
+		c := zone[i]
+		if isURLUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func isURLUnreserved(c byte) bool {
+	return c >= 'A' && c <= 'Z' ||
+		c >= 'a' && c <= 'z' ||
+		c >= '0' && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// ParseURLZoneIPv6Address parses a bracketed IPv6 URL host per RFC 6874, eg
+// "[fe80::1%25eth0]", decoding the percent-encoded zone identifier.  The
+// brackets are optional on input for convenience, but the "%25" zone
+// introducer, if present, is decoded rather than treated as literal text.
+func ParseURLZoneIPv6Address(host string) (*IPv6Address, AddressStringException) {
+	host = strings.TrimPrefix(host, "[")
+	host = strings.TrimSuffix(host, "]")
+	if idx := strings.Index(host, "%25"); idx >= 0 {
+		host = host[:idx] + "%" + percentDecodeZone(host[idx+3:])
+	}
+	addr, err := NewIPAddressString(host).ToAddress()
+	if err != nil {
+		return nil, err
+	}
+	ipv6Addr := addr.ToIPv6Address()
+	if ipv6Addr == nil {
+		return nil, &addressStringError{addressError: addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	}
+	return ipv6Addr, nil
+}
+
+func percentDecodeZone(zone string) string {
+	var b strings.Builder
+	for i := 0; i < len(zone); i++ {
+		if zone[i] == '%' && i+2 < len(zone) {
+			var val int
+			fmt.Sscanf(zone[i+1:i+3], "%02x", &val)
+			b.WriteByte(byte(val))
+			i += 2
+		} else {
+			b.WriteByte(zone[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package ipaddr
+
+import "testing"
+
+// TestToURLZoneString checks RFC 6874 percent-encoding of a zone identifier
+// in a bracketed URL host, and that an address with no zone is still
+// bracketed but unencoded.
+func TestToURLZoneString(t *testing.T) {
+	zoned, err := NewIPAddressString("fe80::1%eth0").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	got := zoned.ToIPv6Address().ToURLZoneString()
+	if want := "[fe80::1%25eth0]"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	unzoned, err := NewIPAddressString("fe80::1").ToAddress()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	got = unzoned.ToIPv6Address().ToURLZoneString()
+	if want := "[fe80::1]"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// TestParseURLZoneIPv6AddressRoundTrip checks that ParseURLZoneIPv6Address
+// reverses ToURLZoneString, with and without brackets.
+func TestParseURLZoneIPv6AddressRoundTrip(t *testing.T) {
+	tests := []string{
+		"[fe80::1%25eth0]",
+		"fe80::1%25eth0",
+	}
+	for _, s := range tests {
+		addr, err := ParseURLZoneIPv6Address(s)
+		if err != nil {
+			t.Fatalf("%q: ParseURLZoneIPv6Address: %v", s, err)
+		}
+		if !addr.HasZone() {
+			t.Fatalf("%q: expected a zone, got none", s)
+		}
+		if string(addr.zone) != "eth0" {
+			t.Errorf("%q: zone got %q, want %q", s, addr.zone, "eth0")
+		}
+		if got := addr.ToURLZoneString(); got != "[fe80::1%25eth0]" {
+			t.Errorf("%q: round trip got %q, want %q", s, got, "[fe80::1%25eth0]")
+		}
+	}
+}
+
+// TestParseURLZoneIPv6AddressPercentEncoded checks that a zone written after
+// the percent-encoded "%25" separator, and containing a '.', is parsed intact.
+func TestParseURLZoneIPv6AddressPercentEncoded(t *testing.T) {
+	addr, err := ParseURLZoneIPv6Address("[fe80::1%25eth0.1]")
+	if err != nil {
+		t.Fatalf("ParseURLZoneIPv6Address: %v", err)
+	}
+	if string(addr.zone) != "eth0.1" {
+		t.Errorf("zone got %q, want %q", addr.zone, "eth0.1")
+	}
+}